@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	defaultAliasLabels      = []string{"index", "alias", "is_write_index", "is_hidden", "routing_index", "routing_search"}
+	defaultAliasLabelValues = func(indexName, aliasName string, alias AliasDataResponse) []string {
+		return []string{
+			indexName,
+			aliasName,
+			strconv.FormatBool(alias.IsWriteIndex),
+			strconv.FormatBool(alias.IsHidden),
+			alias.IndexRouting,
+			alias.SearchRouting,
+		}
+	}
+)
+
+// Aliases information struct
+type Aliases struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	aliasInfo *prometheus.Desc
+}
+
+// NewAliases defines Aliases Prometheus metrics
+func NewAliases(logger log.Logger, client *http.Client, url *url.URL) *Aliases {
+	constLabels := constLabelsFromURL(url)
+	return &Aliases{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_alias", "up"),
+			Help:        "Was the last scrape of the ElasticSearch aliases endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_alias", "total_scrapes"),
+			Help:        "Current total ElasticSearch aliases scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_alias", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		aliasInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index_alias", "info"),
+			"Constant metric with the alias configuration of an index as labels",
+			defaultAliasLabels, constLabels,
+		),
+	}
+}
+
+// Describe add Aliases metrics descriptions
+func (a *Aliases) Describe(ch chan<- *prometheus.Desc) {
+	ch <- a.aliasInfo
+	ch <- a.up.Desc()
+	ch <- a.totalScrapes.Desc()
+	ch <- a.jsonParseFailures.Desc()
+}
+
+func (a *Aliases) fetchAndDecodeAliases() (AliasResponse, error) {
+	var ar AliasResponse
+
+	u := *a.url
+	u.Path = path.Join(u.Path, "/_alias")
+	err := getAndDecodeURL(a.logger, a.client, &u, &ar, a.jsonParseFailures)
+	return ar, err
+}
+
+// Collect gets Aliases metric values
+func (a *Aliases) Collect(ch chan<- prometheus.Metric) {
+	a.totalScrapes.Inc()
+	defer func() {
+		ch <- a.up
+		ch <- a.totalScrapes
+		ch <- a.jsonParseFailures
+	}()
+
+	aliasResp, err := a.fetchAndDecodeAliases()
+	if err != nil {
+		a.up.Set(0)
+		_ = level.Warn(a.logger).Log(
+			"msg", "failed to fetch and decode aliases",
+			"err", err,
+		)
+		return
+	}
+	a.up.Set(1)
+
+	for indexName, index := range aliasResp {
+		for aliasName, alias := range index.Aliases {
+			ch <- prometheus.MustNewConstMetric(
+				a.aliasInfo,
+				prometheus.GaugeValue,
+				1,
+				defaultAliasLabelValues(indexName, aliasName, alias)...,
+			)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package collector
+
+// AliasResponse is a representation of the aliases of every index
+type AliasResponse map[string]AliasIndexResponse
+
+// AliasIndexResponse is a representation of the aliases of a single index
+type AliasIndexResponse struct {
+	Aliases map[string]AliasDataResponse `json:"aliases"`
+}
+
+// AliasDataResponse is a representation of a single alias configuration
+type AliasDataResponse struct {
+	IsWriteIndex  bool   `json:"is_write_index"`
+	IsHidden      bool   `json:"is_hidden"`
+	IndexRouting  string `json:"index_routing"`
+	SearchRouting string `json:"search_routing"`
+}
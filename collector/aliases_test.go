@@ -0,0 +1,54 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestAliases(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/logs-000001 -H 'Content-Type: application/json' -d '{"aliases":{"logs":{"is_write_index":true}}}'
+	//  curl -XPUT http://localhost:9200/logs-000002 -H 'Content-Type: application/json' -d '{"aliases":{"logs":{"is_write_index":false},"logs-routed":{"index_routing":"1","search_routing":"1,2"}}}'
+	//  curl -XPUT http://localhost:9200/twitter
+	//  curl http://localhost:9200/_alias
+	tcs := map[string]string{
+		"7.6.2": `{"logs-000001":{"aliases":{"logs":{"is_write_index":true}}},"logs-000002":{"aliases":{"logs":{"is_write_index":false},"logs-routed":{"index_routing":"1","search_routing":"1,2"}}},"twitter":{"aliases":{}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		a := NewAliases(log.NewNopLogger(), http.DefaultClient, u)
+		ar, err := a.fetchAndDecodeAliases()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode aliases: %s", err)
+		}
+		t.Logf("[%s] Aliases Response: %+v", ver, ar)
+		if len(ar) != 3 {
+			t.Fatalf("Wrong number of indices")
+		}
+		if len(ar["twitter"].Aliases) != 0 {
+			t.Errorf("Wrong number of aliases for twitter")
+		}
+		labels := defaultAliasLabelValues("logs-000001", "logs", ar["logs-000001"].Aliases["logs"])
+		if labels[2] != "true" || labels[3] != "false" {
+			t.Errorf("Wrong labels for write alias: %v", labels)
+		}
+		labels = defaultAliasLabelValues("logs-000002", "logs-routed", ar["logs-000002"].Aliases["logs-routed"])
+		if labels[2] != "false" || labels[4] != "1" || labels[5] != "1,2" {
+			t.Errorf("Wrong labels for routed alias: %v", labels)
+		}
+	}
+}
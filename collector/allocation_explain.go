@@ -0,0 +1,177 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// AllocationExplain information struct
+type AllocationExplain struct {
+	logger    log.Logger
+	client    *http.Client
+	url       *url.URL
+	catShards *CatShardsCache
+	maxShards int
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	allocationDecision *prometheus.Desc
+}
+
+// NewAllocationExplain defines AllocationExplain Prometheus metrics. The allocation is explained
+// for at most maxShards unassigned shard copies per scrape.
+func NewAllocationExplain(logger log.Logger, client *http.Client, url *url.URL, catShards *CatShardsCache, maxShards int) *AllocationExplain {
+	constLabels := constLabelsFromURL(url)
+	return &AllocationExplain{
+		logger:    logger,
+		client:    client,
+		url:       url,
+		catShards: catShards,
+		maxShards: maxShards,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "allocation_explain", "up"),
+			Help:        "Was the last scrape of the ElasticSearch allocation explain endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "allocation_explain", "total_scrapes"),
+			Help:        "Current total ElasticSearch allocation explain scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "allocation_explain", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		allocationDecision: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "unassigned_shard", "allocation_decision"),
+			"Constant metric with the decision and decider explaining why an unassigned shard copy is not allocated",
+			[]string{"index", "shard", "primary_or_replica", "decision", "decider"}, constLabels,
+		),
+	}
+}
+
+// Describe add AllocationExplain metrics descriptions
+func (a *AllocationExplain) Describe(ch chan<- *prometheus.Desc) {
+	ch <- a.allocationDecision
+	ch <- a.up.Desc()
+	ch <- a.totalScrapes.Desc()
+	ch <- a.jsonParseFailures.Desc()
+}
+
+func (a *AllocationExplain) doAndParse(req *http.Request, data interface{}) error {
+	u := req.URL
+	res, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s %s://%s:%s%s: %s",
+			req.Method, u.Scheme, u.Hostname(), u.Port(), u.Path, err)
+	}
+
+	defer func() {
+		err = res.Body.Close()
+		if err != nil {
+			_ = level.Warn(a.logger).Log(
+				"msg", "failed to close http.Client",
+				"err", err,
+			)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(data); err != nil {
+		a.jsonParseFailures.Inc()
+		return err
+	}
+	return nil
+}
+
+func (a *AllocationExplain) fetchAndDecodeCatShards() (CatShardsResponse, error) {
+	return a.catShards.fetchAndDecodeCatShards(a.jsonParseFailures)
+}
+
+func (a *AllocationExplain) fetchAndDecodeAllocationExplain(shard CatShardResponse) (AllocationExplainResponse, error) {
+	var aer AllocationExplainResponse
+
+	number, err := strconv.Atoi(shard.Shard)
+	if err != nil {
+		return aer, fmt.Errorf("invalid shard number %q: %s", shard.Shard, err)
+	}
+	body, err := json.Marshal(AllocationExplainRequest{
+		Index:   shard.Index,
+		Shard:   number,
+		Primary: shard.PriRep == "p",
+	})
+	if err != nil {
+		return aer, err
+	}
+
+	u := *a.url
+	u.Path = path.Join(u.Path, "/_cluster/allocation/explain")
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return aer, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	err = a.doAndParse(req, &aer)
+	return aer, err
+}
+
+// Collect gets AllocationExplain metric values
+func (a *AllocationExplain) Collect(ch chan<- prometheus.Metric) {
+	a.totalScrapes.Inc()
+	defer func() {
+		ch <- a.up
+		ch <- a.totalScrapes
+		ch <- a.jsonParseFailures
+	}()
+
+	catShardsResp, err := a.fetchAndDecodeCatShards()
+	if err != nil {
+		a.up.Set(0)
+		_ = level.Warn(a.logger).Log(
+			"msg", "failed to fetch and decode cat shards",
+			"err", err,
+		)
+		return
+	}
+	a.up.Set(1)
+
+	for _, shard := range unassignedShards(catShardsResp, a.maxShards) {
+		explainResp, err := a.fetchAndDecodeAllocationExplain(shard)
+		if err != nil {
+			_ = level.Warn(a.logger).Log(
+				"msg", "failed to fetch and decode allocation explain",
+				"index", shard.Index,
+				"shard", shard.Shard,
+				"err", err,
+			)
+			continue
+		}
+		primaryOrReplica := "replica"
+		if explainResp.Primary {
+			primaryOrReplica = "primary"
+		}
+		for _, decision := range explainResp.decisions() {
+			ch <- prometheus.MustNewConstMetric(
+				a.allocationDecision,
+				prometheus.GaugeValue,
+				1,
+				shard.Index, shard.Shard, primaryOrReplica, decision.decision, decision.decider,
+			)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"sort"
+	"strconv"
+)
+
+// AllocationExplainRequest is a representation of the shard copy to explain
+type AllocationExplainRequest struct {
+	Index   string `json:"index"`
+	Shard   int    `json:"shard"`
+	Primary bool   `json:"primary"`
+}
+
+// AllocationExplainResponse is a representation of the allocation explanation of a shard copy
+type AllocationExplainResponse struct {
+	Index                   string                                  `json:"index"`
+	Shard                   int                                     `json:"shard"`
+	Primary                 bool                                    `json:"primary"`
+	CurrentState            string                                  `json:"current_state"`
+	CanAllocate             string                                  `json:"can_allocate"`
+	AllocateExplanation     string                                  `json:"allocate_explanation"`
+	NodeAllocationDecisions []AllocationExplainNodeDecisionResponse `json:"node_allocation_decisions"`
+}
+
+// AllocationExplainNodeDecisionResponse is a representation of the allocation decision for a single node
+type AllocationExplainNodeDecisionResponse struct {
+	NodeID       string                             `json:"node_id"`
+	NodeName     string                             `json:"node_name"`
+	NodeDecision string                             `json:"node_decision"`
+	Deciders     []AllocationExplainDeciderResponse `json:"deciders"`
+}
+
+// AllocationExplainDeciderResponse is a representation of the decision of a single allocation decider
+type AllocationExplainDeciderResponse struct {
+	Decider     string `json:"decider"`
+	Decision    string `json:"decision"`
+	Explanation string `json:"explanation"`
+}
+
+// allocationDecision is a decision of a decider preventing the allocation of a shard copy
+type allocationDecision struct {
+	decision string
+	decider  string
+}
+
+// decisions returns the distinct decisions of the deciders across all nodes, or the overall
+// decision without a decider if no decider explains it
+func (r AllocationExplainResponse) decisions() []allocationDecision {
+	seen := map[allocationDecision]bool{}
+	var decisions []allocationDecision
+	for _, node := range r.NodeAllocationDecisions {
+		for _, decider := range node.Deciders {
+			d := allocationDecision{decision: decider.Decision, decider: decider.Decider}
+			if !seen[d] {
+				seen[d] = true
+				decisions = append(decisions, d)
+			}
+		}
+	}
+	if len(decisions) == 0 {
+		decisions = append(decisions, allocationDecision{decision: r.CanAllocate})
+	}
+	return decisions
+}
+
+// unassignedShards returns the first n unassigned shard copies, primaries first as they leave
+// data unavailable, then by index and shard number
+func unassignedShards(shards CatShardsResponse, n int) CatShardsResponse {
+	var unassigned CatShardsResponse
+	for _, shard := range shards {
+		if shard.State == "UNASSIGNED" {
+			unassigned = append(unassigned, shard)
+		}
+	}
+	sort.SliceStable(unassigned, func(i, j int) bool {
+		a, b := unassigned[i], unassigned[j]
+		if a.PriRep != b.PriRep {
+			return a.PriRep == "p"
+		}
+		if a.Index != b.Index {
+			return a.Index < b.Index
+		}
+		as, _ := strconv.Atoi(a.Shard)
+		bs, _ := strconv.Atoi(b.Shard)
+		return as < bs
+	})
+	if len(unassigned) > n {
+		unassigned = unassigned[:n]
+	}
+	return unassigned
+}
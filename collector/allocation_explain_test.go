@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestAllocationExplain(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":2,"number_of_replicas":1}}'
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"transient":{"cluster.routing.allocation.disk.watermark.low":"1b"}}'
+	//  curl -XPUT http://localhost:9200/logs -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":1,"number_of_replicas":0}}'
+	//  curl 'http://localhost:9200/_cat/shards?format=json&h=index,shard,prirep,state'
+	//  curl -XPOST http://localhost:9200/_cluster/allocation/explain -H 'Content-Type: application/json' -d '{"index":"logs","shard":0,"primary":true}'
+	//  curl -XPOST http://localhost:9200/_cluster/allocation/explain -H 'Content-Type: application/json' -d '{"index":"twitter","shard":0,"primary":false}'
+	catShards := `[{"index":"twitter","shard":"1","prirep":"p","state":"STARTED"},{"index":"twitter","shard":"1","prirep":"r","state":"UNASSIGNED"},{"index":"twitter","shard":"0","prirep":"p","state":"STARTED"},{"index":"twitter","shard":"0","prirep":"r","state":"UNASSIGNED"},{"index":"logs","shard":"0","prirep":"p","state":"UNASSIGNED"}]`
+	explanations := map[string]string{
+		"logs/0/true":     `{"index":"logs","shard":0,"primary":true,"current_state":"unassigned","unassigned_info":{"reason":"INDEX_CREATED","at":"2020-04-01T10:00:00.000Z","last_allocation_status":"no"},"can_allocate":"no","allocate_explanation":"cannot allocate because allocation is not permitted to any of the nodes","node_allocation_decisions":[{"node_id":"9_P7yui4SQOkzGhTZCyjxQ","node_name":"node-0","transport_address":"127.0.0.1:9300","node_decision":"no","weight_ranking":1,"deciders":[{"decider":"disk_threshold","decision":"NO","explanation":"the node is above the low watermark cluster setting [cluster.routing.allocation.disk.watermark.low=1b], using more disk space than the maximum allowed [100.0%], actual free: [35.1%]"}]}]}`,
+		"twitter/0/false": `{"index":"twitter","shard":0,"primary":false,"current_state":"unassigned","unassigned_info":{"reason":"INDEX_CREATED","at":"2020-04-01T09:00:00.000Z","last_allocation_status":"no_attempt"},"can_allocate":"no","allocate_explanation":"cannot allocate because allocation is not permitted to any of the nodes","node_allocation_decisions":[{"node_id":"9_P7yui4SQOkzGhTZCyjxQ","node_name":"node-0","transport_address":"127.0.0.1:9300","node_decision":"no","weight_ranking":1,"deciders":[{"decider":"same_shard","decision":"NO","explanation":"a copy of this shard is already allocated to this node [[twitter][0], node[9_P7yui4SQOkzGhTZCyjxQ], [P], s[STARTED], a[id=3XKS9bkOT9qvdNsY3Gzbhw]]"},{"decider":"disk_threshold","decision":"NO","explanation":"the node is above the low watermark cluster setting [cluster.routing.allocation.disk.watermark.low=1b]"}]}]}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_cat/shards" {
+			fmt.Fprint(w, catShards)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/_cluster/allocation/explain" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req AllocationExplainRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode allocation explain request: %s", err)
+		}
+		out, ok := explanations[fmt.Sprintf("%s/%d/%t", req.Index, req.Shard, req.Primary)]
+		if !ok {
+			t.Errorf("Unexpected allocation explain request for %+v", req)
+		}
+		fmt.Fprint(w, out)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	a := NewAllocationExplain(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u), 2)
+	csr, err := a.fetchAndDecodeCatShards()
+	if err != nil {
+		t.Fatalf("Failed to fetch or decode cat shards: %s", err)
+	}
+	unassigned := unassignedShards(csr, 2)
+	if len(unassigned) != 2 {
+		t.Fatalf("Wrong number of unassigned shards: %d", len(unassigned))
+	}
+	if unassigned[0].Index != "logs" || unassigned[1].Index != "twitter" || unassigned[1].Shard != "0" {
+		t.Errorf("Unassigned primaries should be explained first, got %+v", unassigned)
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		a.Collect(ch)
+		close(ch)
+	}()
+	expected := map[string]bool{
+		"logs/0/primary/NO/disk_threshold":    true,
+		"twitter/0/replica/NO/same_shard":     true,
+		"twitter/0/replica/NO/disk_threshold": true,
+	}
+	found := 0
+	for metric := range ch {
+		if !strings.Contains(metric.Desc().String(), `"elasticsearch_unassigned_shard_allocation_decision"`) {
+			continue
+		}
+		found++
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Failed to write metric: %s", err)
+		}
+		labels := map[string]string{}
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		key := strings.Join([]string{labels["index"], labels["shard"], labels["primary_or_replica"], labels["decision"], labels["decider"]}, "/")
+		if !expected[key] {
+			t.Errorf("Unexpected allocation decision %s", key)
+		}
+	}
+	if found != len(expected) {
+		t.Errorf("Expected %d allocation decisions, found %d", len(expected), found)
+	}
+
+	if d := (AllocationExplainResponse{CanAllocate: "throttled"}).decisions(); len(d) != 1 || d[0].decision != "throttled" || d[0].decider != "" {
+		t.Errorf("Wrong decisions without deciders: %+v", d)
+	}
+}
@@ -0,0 +1,156 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type autoscalingMetric struct {
+	Type  prometheus.ValueType
+	Desc  *prometheus.Desc
+	Value func(policy AutoscalingPolicyCapacityResponse) float64
+}
+
+var (
+	defaultAutoscalingLabels = []string{"policy_name"}
+)
+
+// Autoscaling information struct
+type Autoscaling struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	policyMetrics []*autoscalingMetric
+}
+
+// NewAutoscaling defines Autoscaling Prometheus metrics
+func NewAutoscaling(logger log.Logger, client *http.Client, url *url.URL) *Autoscaling {
+	constLabels := constLabelsFromURL(url)
+	return &Autoscaling{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "autoscaling", "up"),
+			Help:        "Was the last scrape of the ElasticSearch autoscaling capacity endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "autoscaling", "total_scrapes"),
+			Help:        "Current total ElasticSearch autoscaling scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "autoscaling", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		policyMetrics: []*autoscalingMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "autoscaling", "required_capacity_nodes"),
+					"Estimated number of nodes needed for the required capacity of the policy",
+					defaultAutoscalingLabels, constLabels,
+				),
+				Value: func(policy AutoscalingPolicyCapacityResponse) float64 {
+					return float64(policy.requiredNodes())
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "autoscaling", "current_capacity_nodes"),
+					"Number of nodes currently governed by the policy",
+					defaultAutoscalingLabels, constLabels,
+				),
+				Value: func(policy AutoscalingPolicyCapacityResponse) float64 {
+					return float64(len(policy.CurrentNodes))
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "autoscaling", "required_capacity_storage_bytes"),
+					"Total storage required by the policy",
+					defaultAutoscalingLabels, constLabels,
+				),
+				Value: func(policy AutoscalingPolicyCapacityResponse) float64 {
+					return float64(policy.RequiredCapacity.Total.Storage)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "autoscaling", "current_capacity_storage_bytes"),
+					"Total storage of the nodes currently governed by the policy",
+					defaultAutoscalingLabels, constLabels,
+				),
+				Value: func(policy AutoscalingPolicyCapacityResponse) float64 {
+					return float64(policy.CurrentCapacity.Total.Storage)
+				},
+			},
+		},
+	}
+}
+
+// Describe add Autoscaling metrics descriptions
+func (a *Autoscaling) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range a.policyMetrics {
+		ch <- metric.Desc
+	}
+	ch <- a.up.Desc()
+	ch <- a.totalScrapes.Desc()
+	ch <- a.jsonParseFailures.Desc()
+}
+
+func (a *Autoscaling) fetchAndDecodeAutoscalingCapacity() (AutoscalingCapacityResponse, error) {
+	var acr AutoscalingCapacityResponse
+
+	u := *a.url
+	u.Path = path.Join(u.Path, "/_autoscaling/capacity")
+	err := getAndDecodeURL(a.logger, a.client, &u, &acr, a.jsonParseFailures)
+	return acr, err
+}
+
+// Collect gets Autoscaling metric values
+func (a *Autoscaling) Collect(ch chan<- prometheus.Metric) {
+	a.totalScrapes.Inc()
+	defer func() {
+		ch <- a.up
+		ch <- a.totalScrapes
+		ch <- a.jsonParseFailures
+	}()
+
+	autoscalingCapacityResp, err := a.fetchAndDecodeAutoscalingCapacity()
+	if err != nil {
+		a.up.Set(0)
+		_ = level.Warn(a.logger).Log(
+			"msg", "failed to fetch and decode autoscaling capacity",
+			"err", err,
+		)
+		return
+	}
+	a.up.Set(1)
+
+	for policyName, policy := range autoscalingCapacityResp.Policies {
+		for _, metric := range a.policyMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(policy),
+				policyName,
+			)
+		}
+	}
+}
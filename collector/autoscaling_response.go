@@ -0,0 +1,49 @@
+package collector
+
+import "math"
+
+// AutoscalingCapacityResponse is a representation of the autoscaling capacity API
+type AutoscalingCapacityResponse struct {
+	Policies map[string]AutoscalingPolicyCapacityResponse `json:"policies"`
+}
+
+// AutoscalingPolicyCapacityResponse is a representation of the capacity of a single autoscaling policy
+type AutoscalingPolicyCapacityResponse struct {
+	RequiredCapacity AutoscalingCapacityTierResponse `json:"required_capacity"`
+	CurrentCapacity  AutoscalingCapacityTierResponse `json:"current_capacity"`
+	CurrentNodes     []struct {
+		Name string `json:"name"`
+	} `json:"current_nodes"`
+}
+
+// AutoscalingCapacityTierResponse is a representation of the capacity per node and in total of a policy
+type AutoscalingCapacityTierResponse struct {
+	Node  AutoscalingCapacityResourcesResponse `json:"node"`
+	Total AutoscalingCapacityResourcesResponse `json:"total"`
+}
+
+// AutoscalingCapacityResourcesResponse is a representation of the storage and memory capacity in bytes,
+// resources without a decider are not reported
+type AutoscalingCapacityResourcesResponse struct {
+	Storage int64 `json:"storage"`
+	Memory  int64 `json:"memory"`
+}
+
+// requiredNodes estimates the number of nodes needed for the required capacity, since the API only
+// reports the required capacity per node and in total. Every resource needs the total divided by the
+// capacity of a single node, rounded up.
+func (p AutoscalingPolicyCapacityResponse) requiredNodes() int64 {
+	var nodes int64
+	for _, resource := range []struct{ node, total int64 }{
+		{p.RequiredCapacity.Node.Storage, p.RequiredCapacity.Total.Storage},
+		{p.RequiredCapacity.Node.Memory, p.RequiredCapacity.Total.Memory},
+	} {
+		if resource.node <= 0 {
+			continue
+		}
+		if n := int64(math.Ceil(float64(resource.total) / float64(resource.node))); n > nodes {
+			nodes = n
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestAutoscalingCapacity(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION (with a trial license)
+	//  curl -XPUT http://localhost:9200/_autoscaling/policy/hot -H 'Content-Type: application/json' -d '{"roles":["data_hot"],"deciders":{"fixed":{"storage":"300gb","memory":"16gb","nodes":3}}}'
+	//  curl -XPUT http://localhost:9200/_autoscaling/policy/ml -H 'Content-Type: application/json' -d '{"roles":["ml"],"deciders":{"fixed":{"memory":"8gb","nodes":1}}}'
+	//  curl http://localhost:9200/_autoscaling/capacity
+	tcs := map[string]string{
+		"7.17.0": `{"policies":{"hot":{"required_capacity":{"node":{"storage":107374182400,"memory":17179869184},"total":{"storage":322122547200,"memory":51539607552}},"current_capacity":{"node":{"storage":107374182400,"memory":17179869184},"total":{"storage":214748364800,"memory":34359738368}},"current_nodes":[{"name":"instance-0000000000"},{"name":"instance-0000000001"}],"deciders":{"fixed":{"required_capacity":{"node":{"storage":107374182400,"memory":17179869184},"total":{"storage":322122547200,"memory":51539607552}},"reason_summary":"fixed storage [100gb] memory [16gb] nodes [3]","reason_details":null}}},"ml":{"required_capacity":{"node":{"memory":8589934592},"total":{"memory":8589934592}},"current_capacity":{"node":{"storage":0,"memory":0},"total":{"storage":0,"memory":0}},"current_nodes":[],"deciders":{"fixed":{"required_capacity":{"node":{"memory":8589934592},"total":{"memory":8589934592}},"reason_summary":"fixed memory [8gb] nodes [1]","reason_details":null}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		a := NewAutoscaling(log.NewNopLogger(), http.DefaultClient, u)
+		acr, err := a.fetchAndDecodeAutoscalingCapacity()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode autoscaling capacity: %s", err)
+		}
+		t.Logf("[%s] Autoscaling Capacity Response: %+v", ver, acr)
+
+		expected := map[string][]float64{
+			// the hot tier requires a third node
+			"hot": {3, 2, 322122547200, 214748364800},
+			// the ml tier has no nodes yet and requires no storage
+			"ml": {1, 0, 0, 0},
+		}
+		if len(acr.Policies) != len(expected) {
+			t.Fatalf("[%s] Wrong number of policies: %d", ver, len(acr.Policies))
+		}
+		for name, values := range expected {
+			for i, metric := range a.policyMetrics {
+				if v := metric.Value(acr.Policies[name]); v != values[i] {
+					t.Errorf("[%s] Wrong value for policy %s metric %d: got %v, expected %v", ver, name, i, v, values[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type catHealthMetric struct {
+	Type  prometheus.ValueType
+	Desc  *prometheus.Desc
+	Value func(clusterHealth CatHealthClusterResponse) float64
+}
+
+// CatHealth type defines the collector struct
+type CatHealth struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	metrics      []*catHealthMetric
+	statusMetric *prometheus.Desc
+}
+
+// NewCatHealth returns a new Collector exposing the cluster health from the cat health API.
+// It is a fallback for legacy clusters and exports the same metrics as ClusterHealth, with the
+// same help texts, so only one of both must be registered.
+func NewCatHealth(logger log.Logger, client *http.Client, url *url.URL) *CatHealth {
+	subsystem := "cluster_health"
+	constLabels := constLabelsFromURL(url)
+
+	return &CatHealth{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, subsystem, "up"),
+			Help:        "Was the last scrape of the ElasticSearch cluster health endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, subsystem, "total_scrapes"),
+			Help:        "Current total ElasticSearch cluster health scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, subsystem, "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+
+		metrics: []*catHealthMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "active_primary_shards"),
+					"The number of primary shards in your cluster. This is an aggregate total across all indices.",
+					defaultClusterHealthLabels, constLabels,
+				),
+				Value: func(clusterHealth CatHealthClusterResponse) float64 {
+					return float64(clusterHealth.ActivePrimaryShards)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "active_shards"),
+					"Aggregate total of all shards across all indices, which includes replica shards.",
+					defaultClusterHealthLabels, constLabels,
+				),
+				Value: func(clusterHealth CatHealthClusterResponse) float64 {
+					return float64(clusterHealth.ActiveShards)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "initializing_shards"),
+					"Count of shards that are being freshly created.",
+					defaultClusterHealthLabels, constLabels,
+				),
+				Value: func(clusterHealth CatHealthClusterResponse) float64 {
+					return float64(clusterHealth.InitializingShards)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "number_of_data_nodes"),
+					"Number of data nodes in the cluster.",
+					defaultClusterHealthLabels, constLabels,
+				),
+				Value: func(clusterHealth CatHealthClusterResponse) float64 {
+					return float64(clusterHealth.NumberOfDataNodes)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "number_of_nodes"),
+					"Number of nodes in the cluster.",
+					defaultClusterHealthLabels, constLabels,
+				),
+				Value: func(clusterHealth CatHealthClusterResponse) float64 {
+					return float64(clusterHealth.NumberOfNodes)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "relocating_shards"),
+					"The number of shards that are currently moving from one node to another node.",
+					defaultClusterHealthLabels, constLabels,
+				),
+				Value: func(clusterHealth CatHealthClusterResponse) float64 {
+					return float64(clusterHealth.RelocatingShards)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "unassigned_shards"),
+					"The number of shards that exist in the cluster state, but cannot be found in the cluster itself.",
+					defaultClusterHealthLabels, constLabels,
+				),
+				Value: func(clusterHealth CatHealthClusterResponse) float64 {
+					return float64(clusterHealth.UnassignedShards)
+				},
+			},
+		},
+		statusMetric: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "status"),
+			"Whether all primary and replica shards are allocated.",
+			[]string{"cluster", "color"}, constLabels,
+		),
+	}
+}
+
+// Describe set Prometheus metrics descriptions.
+func (c *CatHealth) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric.Desc
+	}
+	ch <- c.statusMetric
+
+	ch <- c.up.Desc()
+	ch <- c.totalScrapes.Desc()
+	ch <- c.jsonParseFailures.Desc()
+}
+
+func (c *CatHealth) fetchAndDecodeCatHealth() (CatHealthResponse, error) {
+	var chr CatHealthResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_cat/health")
+	u.RawQuery = "format=json"
+	err := getAndDecodeURL(c.logger, c.client, &u, &chr, c.jsonParseFailures)
+	return chr, err
+}
+
+// Collect collects CatHealth metrics.
+func (c *CatHealth) Collect(ch chan<- prometheus.Metric) {
+	c.totalScrapes.Inc()
+	defer func() {
+		ch <- c.up
+		ch <- c.totalScrapes
+		ch <- c.jsonParseFailures
+	}()
+
+	catHealthResp, err := c.fetchAndDecodeCatHealth()
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode cat health",
+			"err", err,
+		)
+		return
+	}
+	c.up.Set(1)
+
+	for _, clusterHealth := range catHealthResp {
+		for _, metric := range c.metrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(clusterHealth),
+				clusterHealth.Cluster,
+			)
+		}
+
+		for _, color := range colors {
+			var value float64
+			if clusterHealth.Status == color {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				c.statusMetric,
+				prometheus.GaugeValue,
+				value,
+				clusterHealth.Cluster, color,
+			)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package collector
+
+// CatHealthResponse is a representation of the cat health API
+type CatHealthResponse []CatHealthClusterResponse
+
+// CatHealthClusterResponse is a representation of the health of a cluster in the cat health API
+type CatHealthClusterResponse struct {
+	Cluster             string `json:"cluster"`
+	Status              string `json:"status"`
+	NumberOfNodes       int64  `json:"node.total,string"`
+	NumberOfDataNodes   int64  `json:"node.data,string"`
+	ActiveShards        int64  `json:"shards,string"`
+	ActivePrimaryShards int64  `json:"pri,string"`
+	RelocatingShards    int64  `json:"relo,string"`
+	InitializingShards  int64  `json:"init,string"`
+	UnassignedShards    int64  `json:"unassign,string"`
+}
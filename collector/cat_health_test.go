@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCatHealth(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 elasticsearch:VERSION-alpine
+	//  curl -XPUT http://localhost:9200/twitter
+	//  curl 'http://localhost:9200/_cat/health?format=json'
+	tcs := map[string]string{
+		"2.4.5": `[{"epoch":"1498820641","timestamp":"11:04:01","cluster":"elasticsearch","status":"yellow","node.total":"1","node.data":"1","shards":"5","pri":"5","relo":"0","init":"0","unassign":"5","pending_tasks":"0","max_task_wait_time":"-","active_shards_percent":"50.0%"}]`,
+		"5.4.2": `[{"epoch":"1498820641","timestamp":"11:04:01","cluster":"elasticsearch","status":"yellow","node.total":"1","node.data":"1","shards":"5","pri":"5","relo":"0","init":"0","unassign":"5","pending_tasks":"0","max_task_wait_time":"-","active_shards_percent":"50.0%"}]`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewCatHealth(log.NewNopLogger(), http.DefaultClient, u)
+		chr, err := c.fetchAndDecodeCatHealth()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cat health: %s", err)
+		}
+		t.Logf("[%s] Cat Health Response: %+v", ver, chr)
+		if len(chr) != 1 {
+			t.Fatalf("Wrong number of clusters")
+		}
+		clusterHealth := chr[0]
+		if clusterHealth.Cluster != "elasticsearch" {
+			t.Errorf("Wrong cluster name")
+		}
+		if clusterHealth.Status != "yellow" {
+			t.Errorf("Wrong cluster status")
+		}
+		// active_primary_shards, active_shards, initializing_shards, number_of_data_nodes,
+		// number_of_nodes, relocating_shards, unassigned_shards
+		expected := []float64{5, 5, 0, 1, 1, 0, 5}
+		for i, metric := range c.metrics {
+			if v := metric.Value(clusterHealth); v != expected[i] {
+				t.Errorf("Wrong value for cat health metric %d: got %v, expected %v", i, v, expected[i])
+			}
+		}
+	}
+}
+
+func TestCatHealthDescriptors(t *testing.T) {
+	u := &url.URL{Scheme: "http", Host: "localhost:9200"}
+	describe := func(c prometheus.Collector) map[string]string {
+		ch := make(chan *prometheus.Desc)
+		go func() {
+			c.Describe(ch)
+			close(ch)
+		}()
+		descs := make(map[string]string)
+		for desc := range ch {
+			name := strings.SplitN(desc.String(), `"`, 3)[1]
+			descs[name] = desc.String()
+		}
+		return descs
+	}
+
+	// both collectors export the same metrics, their descriptors must not conflict
+	clusterHealth := describe(NewClusterHealth(log.NewNopLogger(), http.DefaultClient, u, nil, 0, 0, false))
+	for name, desc := range describe(NewCatHealth(log.NewNopLogger(), http.DefaultClient, u)) {
+		if clusterHealthDesc, ok := clusterHealth[name]; !ok || clusterHealthDesc != desc {
+			t.Errorf("Descriptor of %s differs from cluster health: %s, %s", name, desc, clusterHealthDesc)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CatMaster type defines the collector struct
+type CatMaster struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	masterNode     *masterNodeTracker
+	masterNodeInfo *prometheus.Desc
+}
+
+// NewCatMaster returns a new Collector exposing the elected master node from the cat master API.
+// ClusterHealth already exports the master node, so this collector is meant for the legacy
+// compatibility mode where only CatHealth is registered.
+func NewCatMaster(logger log.Logger, client *http.Client, url *url.URL) *CatMaster {
+	subsystem := "cat_master"
+	constLabels := constLabelsFromURL(url)
+
+	return &CatMaster{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, subsystem, "up"),
+			Help:        "Was the last scrape of the ElasticSearch cat master endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, subsystem, "total_scrapes"),
+			Help:        "Current total ElasticSearch cat master scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, subsystem, "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		masterNode: newMasterNodeTracker("master_elections_total",
+			"Number of times a different master node was elected between scrapes.", constLabels),
+		masterNodeInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "master_node_info"),
+			"Constant metric with the currently elected master node as labels.",
+			[]string{"node_id", "node_ip", "node_name", "host"}, constLabels,
+		),
+	}
+}
+
+// Describe set Prometheus metrics descriptions.
+func (c *CatMaster) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.masterNode.changes.Desc()
+	ch <- c.masterNodeInfo
+
+	ch <- c.up.Desc()
+	ch <- c.totalScrapes.Desc()
+	ch <- c.jsonParseFailures.Desc()
+}
+
+// Collect collects CatMaster metrics.
+func (c *CatMaster) Collect(ch chan<- prometheus.Metric) {
+	c.totalScrapes.Inc()
+	defer func() {
+		ch <- c.up
+		ch <- c.totalScrapes
+		ch <- c.jsonParseFailures
+		ch <- c.masterNode.changes
+	}()
+
+	masterNode, err := fetchAndDecodeMasterNode(c.logger, c.client, c.url, c.jsonParseFailures)
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode cat master",
+			"err", err,
+		)
+		return
+	}
+	c.up.Set(1)
+
+	c.masterNode.track(masterNode.ID)
+	ch <- prometheus.MustNewConstMetric(
+		c.masterNodeInfo,
+		prometheus.GaugeValue,
+		1,
+		masterNode.ID, masterNode.IP, masterNode.Node, masterNode.Host,
+	)
+}
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestCatMaster(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 elasticsearch:VERSION-alpine
+	//  curl 'http://localhost:9200/_cat/master?format=json'
+	//  docker stop <master container> (second cluster node takes over)
+	//  curl 'http://localhost:9200/_cat/master?format=json'
+	tcs := map[string][]string{
+		"2.4.5": {
+			`[{"id":"zjLZUFfBRq6_TNy0S2rnVQ","host":"172.17.0.2","ip":"172.17.0.2","node":"Quasar"}]`,
+			`[{"id":"zjLZUFfBRq6_TNy0S2rnVQ","host":"172.17.0.2","ip":"172.17.0.2","node":"Quasar"}]`,
+			`[{"id":"bN4Pw0hIQl6sYo8fWvvZkQ","host":"172.17.0.3","ip":"172.17.0.3","node":"Mystique"}]`,
+		},
+		"5.4.2": {
+			`[{"id":"9_P7yui4SQOkzGhTZCyjxQ","host":"172.17.0.2","ip":"172.17.0.2","node":"9_P7yui"}]`,
+			`[{"id":"Jx0Vt0hTR0iVbVGRx1oBCA","host":"172.17.0.3","ip":"172.17.0.3","node":"Jx0Vt0h"}]`,
+			`[{"id":"9_P7yui4SQOkzGhTZCyjxQ","host":"172.17.0.2","ip":"172.17.0.2","node":"9_P7yui"}]`,
+		},
+	}
+	changes := map[string]float64{"2.4.5": 1, "5.4.2": 2}
+	for ver, masters := range tcs {
+		var scrape int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, masters[scrape])
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewCatMaster(log.NewNopLogger(), http.DefaultClient, u)
+		names := []string{"elasticsearch_cat_master_up", "elasticsearch_cluster_master_elections_total", "elasticsearch_cluster_master_node_info"}
+		metrics := make(map[string]*dto.Metric)
+		for scrape = range masters {
+			ch := make(chan prometheus.Metric, 10)
+			c.Collect(ch)
+			close(ch)
+			for metric := range ch {
+				for _, name := range names {
+					if !strings.Contains(metric.Desc().String(), `"`+name+`"`) {
+						continue
+					}
+					m := &dto.Metric{}
+					if err := metric.Write(m); err != nil {
+						t.Fatalf("Failed to write %s: %s", name, err)
+					}
+					metrics[name] = m
+				}
+			}
+		}
+
+		if v := metrics["elasticsearch_cat_master_up"].GetGauge().GetValue(); v != 1 {
+			t.Errorf("[%s] Wrong cat master up: %v", ver, v)
+		}
+		if v := metrics["elasticsearch_cluster_master_elections_total"].GetCounter().GetValue(); v != changes[ver] {
+			t.Errorf("[%s] Wrong number of master node changes: %v", ver, v)
+		}
+		info, ok := metrics["elasticsearch_cluster_master_node_info"]
+		if !ok {
+			t.Fatalf("[%s] Missing elasticsearch_cluster_master_node_info", ver)
+		}
+		labels := make(map[string]string)
+		for _, label := range info.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		for _, name := range []string{"node_id", "node_ip", "node_name", "host"} {
+			if labels[name] == "" {
+				t.Errorf("[%s] Missing master node label %s: %v", ver, name, labels)
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	shardInitializationBuckets = []float64{1, 5, 10, 30, 60, 300, 600, 1800, 3600}
+)
+
+// shardNumberKey identifies a shard of an index
+type shardNumberKey struct {
+	index, shard string
+}
+
+// shardKey identifies a shard copy on a node
+type shardKey struct {
+	index, shard, nodeID, primaryOrReplica string
+}
+
+// CatShards information struct
+type CatShards struct {
+	logger    log.Logger
+	client    *http.Client
+	url       *url.URL
+	catShards *CatShardsCache
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	initializationTime *prometheus.HistogramVec
+	replicaDocDrift    *prometheus.Desc
+
+	mu sync.Mutex
+	// initializing holds the first scrape each shard copy was seen initializing
+	initializing map[shardKey]time.Time
+}
+
+// NewCatShards defines CatShards Prometheus metrics
+func NewCatShards(logger log.Logger, client *http.Client, url *url.URL, catShards *CatShardsCache) *CatShards {
+	constLabels := constLabelsFromURL(url)
+	return &CatShards{
+		logger:    logger,
+		client:    client,
+		url:       url,
+		catShards: catShards,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "cat_shards", "up"),
+			Help:        "Was the last scrape of the ElasticSearch cat shards endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "cat_shards", "total_scrapes"),
+			Help:        "Current total ElasticSearch cat shards scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "cat_shards", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		initializationTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        prometheus.BuildFQName(namespace, "shard", "initialization_seconds"),
+			Help:        "Time shard copies spent initializing before they started, measured with the resolution of the scrape interval",
+			Buckets:     shardInitializationBuckets,
+			ConstLabels: constLabels,
+		}, []string{"primary_or_replica"}),
+		replicaDocDrift: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index_shard", "replica_doc_count_drift"),
+			"Difference between the highest document count of the started replicas and the document count of the primary of a shard, 0 for shards without started replicas",
+			[]string{"index", "shard_number"}, constLabels,
+		),
+
+		initializing: make(map[shardKey]time.Time),
+	}
+}
+
+// Describe add CatShards metrics descriptions
+func (c *CatShards) Describe(ch chan<- *prometheus.Desc) {
+	c.initializationTime.Describe(ch)
+	ch <- c.replicaDocDrift
+	ch <- c.up.Desc()
+	ch <- c.totalScrapes.Desc()
+	ch <- c.jsonParseFailures.Desc()
+}
+
+func (c *CatShards) fetchAndDecodeCatShards() (CatShardsResponse, error) {
+	return c.catShards.fetchAndDecodeCatShards(c.jsonParseFailures)
+}
+
+// trackInitializingShards remembers when shard copies were first seen initializing and observes
+// the initialization time once they started. Shard copies which disappeared while initializing,
+// e.g. because the recovery failed, are forgotten.
+func (c *CatShards) trackInitializingShards(shards CatShardsResponse, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	initializing := make(map[shardKey]time.Time)
+	for _, shard := range shards {
+		primaryOrReplica := "replica"
+		if shard.PriRep == "p" {
+			primaryOrReplica = "primary"
+		}
+		key := shardKey{shard.Index, shard.Shard, shard.NodeID, primaryOrReplica}
+		since, seen := c.initializing[key]
+
+		switch shard.State {
+		case "INITIALIZING":
+			if !seen {
+				since = now
+			}
+			initializing[key] = since
+		case "STARTED":
+			if seen {
+				c.initializationTime.WithLabelValues(primaryOrReplica).Observe(now.Sub(since).Seconds())
+			}
+		}
+	}
+	c.initializing = initializing
+}
+
+// replicaDocCountDrifts returns for every shard with a started primary the highest document count of
+// its started replicas minus the document count of the primary, 0 for shards without started replicas
+func replicaDocCountDrifts(shards CatShardsResponse) map[shardNumberKey]int64 {
+	primaries := make(map[shardNumberKey]int64)
+	replicas := make(map[shardNumberKey]int64)
+	for _, shard := range shards {
+		if shard.State != "STARTED" {
+			continue
+		}
+		docs, err := strconv.ParseInt(shard.Docs, 10, 64)
+		if err != nil {
+			continue
+		}
+		key := shardNumberKey{shard.Index, shard.Shard}
+		if shard.PriRep == "p" {
+			primaries[key] = docs
+		} else if current, ok := replicas[key]; !ok || docs > current {
+			replicas[key] = docs
+		}
+	}
+
+	drifts := make(map[shardNumberKey]int64, len(primaries))
+	for key, primaryDocs := range primaries {
+		if replicaDocs, ok := replicas[key]; ok {
+			drifts[key] = replicaDocs - primaryDocs
+		} else {
+			drifts[key] = 0
+		}
+	}
+	return drifts
+}
+
+// Collect gets CatShards metric values
+func (c *CatShards) Collect(ch chan<- prometheus.Metric) {
+	c.totalScrapes.Inc()
+	defer func() {
+		ch <- c.up
+		ch <- c.totalScrapes
+		ch <- c.jsonParseFailures
+	}()
+
+	catShardsResp, err := c.fetchAndDecodeCatShards()
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode cat shards",
+			"err", err,
+		)
+		return
+	}
+	c.up.Set(1)
+
+	c.trackInitializingShards(catShardsResp, time.Now())
+	c.initializationTime.Collect(ch)
+
+	for key, drift := range replicaDocCountDrifts(catShardsResp) {
+		ch <- prometheus.MustNewConstMetric(
+			c.replicaDocDrift,
+			prometheus.GaugeValue,
+			float64(drift),
+			key.index, key.shard,
+		)
+	}
+}
@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// catShardsMaxAge is how long a /_cat/shards response is reused, long enough to cover the
+// collectors of one scrape and shorter than any sensible scrape interval
+const catShardsMaxAge = 5 * time.Second
+
+// CatShardsCache fetches /_cat/shards on behalf of the indices settings, allocation explain
+// and cat shards collectors, so a scrape fetches the shards of a cluster only once
+type CatShardsCache struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+	maxAge time.Duration
+
+	mu      sync.Mutex
+	fetched time.Time
+	shards  CatShardsResponse
+	err     error
+}
+
+// NewCatShardsCache returns a CatShardsCache for the cluster at url
+func NewCatShardsCache(logger log.Logger, client *http.Client, url *url.URL) *CatShardsCache {
+	return &CatShardsCache{
+		logger: logger,
+		client: client,
+		url:    url,
+		maxAge: catShardsMaxAge,
+	}
+}
+
+// fetchAndDecodeCatShards returns the shards fetched within maxAge or fetches them again.
+// Decoding errors are counted in the jsonParseFailures of the collector which triggered the fetch.
+// The returned shards are shared and must not be modified.
+func (c *CatShardsCache) fetchAndDecodeCatShards(jsonParseFailures prometheus.Counter) (CatShardsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.fetched) < c.maxAge {
+		return c.shards, c.err
+	}
+
+	var csr CatShardsResponse
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_cat/shards")
+	u.RawQuery = "format=json&h=index,shard,prirep,state,id,docs"
+	c.err = getAndDecodeURL(c.logger, c.client, &u, &csr, jsonParseFailures)
+	c.shards = csr
+	c.fetched = time.Now()
+	return c.shards, c.err
+}
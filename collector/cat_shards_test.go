@@ -0,0 +1,158 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestCatShardsInitialization(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":1,"number_of_replicas":1}}'
+	//  docker run -d -e "discovery.seed_hosts=172.17.0.2" elasticsearch:VERSION (second node joins)
+	//  curl 'http://localhost:9200/_cat/shards?format=json&h=index,shard,prirep,state,id' (repeatedly)
+	scrapes := []string{
+		`[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ"},{"index":"twitter","shard":"0","prirep":"r","state":"UNASSIGNED","id":null}]`,
+		`[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ"},{"index":"twitter","shard":"0","prirep":"r","state":"INITIALIZING","id":"Jx0Vt0hTR0iVbVGRx1oBCA"},{"index":"logs","shard":"0","prirep":"p","state":"INITIALIZING","id":"9_P7yui4SQOkzGhTZCyjxQ"}]`,
+		`[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ"},{"index":"twitter","shard":"0","prirep":"r","state":"INITIALIZING","id":"Jx0Vt0hTR0iVbVGRx1oBCA"},{"index":"logs","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ"}]`,
+		`[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ"},{"index":"twitter","shard":"0","prirep":"r","state":"STARTED","id":"Jx0Vt0hTR0iVbVGRx1oBCA"},{"index":"logs","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ"}]`,
+	}
+	var scrape int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, scrapes[scrape])
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	// every iteration is a separate scrape
+	catShards := NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u)
+	catShards.maxAge = 0
+	c := NewCatShards(log.NewNopLogger(), http.DefaultClient, u, catShards)
+	start := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	for scrape = range scrapes {
+		csr, err := c.fetchAndDecodeCatShards()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cat shards: %s", err)
+		}
+		c.trackInitializingShards(csr, start.Add(time.Duration(scrape)*30*time.Second))
+	}
+	if len(c.initializing) != 0 {
+		t.Errorf("Started shards are still tracked as initializing: %v", c.initializing)
+	}
+
+	for primaryOrReplica, want := range map[string]float64{"primary": 30, "replica": 60} {
+		var m dto.Metric
+		if err := c.initializationTime.WithLabelValues(primaryOrReplica).(prometheus.Metric).Write(&m); err != nil {
+			t.Fatalf("Failed to read initialization time: %s", err)
+		}
+		if m.GetHistogram().GetSampleCount() != 1 || m.GetHistogram().GetSampleSum() != want {
+			t.Errorf("Wrong %s initialization time: count %d, sum %v", primaryOrReplica, m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum())
+		}
+	}
+}
+
+func TestCatShardsForgetFailedRecoveries(t *testing.T) {
+	c := NewCatShards(log.NewNopLogger(), http.DefaultClient, &url.URL{}, nil)
+	now := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	c.trackInitializingShards(CatShardsResponse{
+		{Index: "twitter", Shard: "0", PriRep: "r", State: "INITIALIZING", NodeID: "Jx0Vt0hTR0iVbVGRx1oBCA"},
+	}, now)
+	// The recovery failed and the replica is allocated to another node
+	c.trackInitializingShards(CatShardsResponse{
+		{Index: "twitter", Shard: "0", PriRep: "r", State: "INITIALIZING", NodeID: "bN4Pw0hIQl6sYo8fWvvZkQ"},
+	}, now.Add(time.Minute))
+	if len(c.initializing) != 1 {
+		t.Fatalf("Wrong number of initializing shards: %v", c.initializing)
+	}
+	if since := c.initializing[shardKey{"twitter", "0", "bN4Pw0hIQl6sYo8fWvvZkQ", "replica"}]; !since.Equal(now.Add(time.Minute)) {
+		t.Errorf("Wrong initialization start: %v", since)
+	}
+}
+
+func TestCatShardsReplicaDocCountDrift(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION (and a second node)
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":2,"number_of_replicas":2}}'
+	//  curl -XPUT http://localhost:9200/logs -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":1,"number_of_replicas":0}}'
+	//  curl 'http://localhost:9200/_cat/shards?format=json&h=index,shard,prirep,state,id,docs'
+	tcs := map[string]string{
+		"7.10.2": `[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ","docs":"120"},{"index":"twitter","shard":"0","prirep":"r","state":"STARTED","id":"Jx0Vt0hTR0iVbVGRx1oBCA","docs":"118"},{"index":"twitter","shard":"0","prirep":"r","state":"UNASSIGNED","id":null,"docs":null},{"index":"twitter","shard":"1","prirep":"p","state":"STARTED","id":"Jx0Vt0hTR0iVbVGRx1oBCA","docs":"95"},{"index":"twitter","shard":"1","prirep":"r","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ","docs":"95"},{"index":"twitter","shard":"1","prirep":"r","state":"INITIALIZING","id":"bN4Pw0hIQl6sYo8fWvvZkQ","docs":"12"},{"index":"logs","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ","docs":"5"}]`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewCatShards(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u))
+		csr, err := c.fetchAndDecodeCatShards()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cat shards: %s", err)
+		}
+		t.Logf("[%s] Cat Shards Response: %+v", ver, csr)
+
+		drifts := replicaDocCountDrifts(csr)
+		expected := map[shardNumberKey]int64{
+			{"twitter", "0"}: -2,
+			// initializing replicas are still recovering and are ignored
+			{"twitter", "1"}: 0,
+			// no replicas
+			{"logs", "0"}: 0,
+		}
+		if len(drifts) != len(expected) {
+			t.Fatalf("[%s] Wrong number of shards: %v", ver, drifts)
+		}
+		for key, want := range expected {
+			if drift, ok := drifts[key]; !ok || drift != want {
+				t.Errorf("[%s] Wrong drift of %v: got %d, expected %d", ver, key, drift, want)
+			}
+		}
+	}
+}
+
+func TestCatShardsCacheShared(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_cat/shards" {
+			requests++
+		}
+		fmt.Fprintln(w, `[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED","id":"9_P7yui4SQOkzGhTZCyjxQ","docs":"10"},{"index":"twitter","shard":"0","prirep":"r","state":"UNASSIGNED","id":null,"docs":null}]`)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	catShards := NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u)
+	is := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u, catShards)
+	a := NewAllocationExplain(log.NewNopLogger(), http.DefaultClient, u, catShards, 2)
+	c := NewCatShards(log.NewNopLogger(), http.DefaultClient, u, catShards)
+	for _, fetch := range []func() (CatShardsResponse, error){is.fetchAndDecodeCatShards, a.fetchAndDecodeCatShards, c.fetchAndDecodeCatShards} {
+		csr, err := fetch()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cat shards: %s", err)
+		}
+		if len(csr) != 2 {
+			t.Errorf("Wrong number of shards: %d", len(csr))
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Cat shards should be fetched once for all collectors, got %d requests", requests)
+	}
+}
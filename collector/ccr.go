@@ -0,0 +1,250 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type ccrFollowerMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(followerIndex CCRFollowIndexResponse) float64
+	Labels func(followerIndex CCRFollowIndexResponse) []string
+}
+
+var (
+	defaultCCRFollowerLabels      = []string{"leader_index", "follower_index"}
+	defaultCCRFollowerLabelValues = func(followerIndex CCRFollowIndexResponse) []string {
+		var leaderIndex string
+		if len(followerIndex.Shards) > 0 {
+			leaderIndex = followerIndex.Shards[0].LeaderIndex
+		}
+		return []string{leaderIndex, followerIndex.Index}
+	}
+)
+
+// CCR information struct
+type CCR struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	followerMetrics []*ccrFollowerMetric
+
+	autoFollowActive           *prometheus.Desc
+	autoFollowFollowed         *prometheus.Desc
+	autoFollowCreationFailures *prometheus.Desc
+}
+
+// NewCCR defines CCR Prometheus metrics
+func NewCCR(logger log.Logger, client *http.Client, url *url.URL) *CCR {
+	constLabels := constLabelsFromURL(url)
+	return &CCR{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "ccr", "up"),
+			Help:        "Was the last scrape of the ElasticSearch CCR stats endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ccr", "total_scrapes"),
+			Help:        "Current total ElasticSearch CCR scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ccr", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		autoFollowActive: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ccr_auto_follow", "active"),
+			"Whether the auto-follow pattern follows new leader indices (active=1, paused=0)",
+			[]string{"pattern_name"}, constLabels,
+		),
+		autoFollowFollowed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ccr_auto_follow", "patterns_followed_total"),
+			"Number of leader indices automatically followed by all auto-follow patterns",
+			nil, constLabels,
+		),
+		autoFollowCreationFailures: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ccr_auto_follow", "follower_creation_failures_total"),
+			"Number of leader indices all auto-follow patterns failed to follow",
+			nil, constLabels,
+		),
+		followerMetrics: []*ccrFollowerMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ccr", "follower_lag_ops"),
+					"Number of operations the follower index is behind the leader index",
+					defaultCCRFollowerLabels, constLabels,
+				),
+				Value: func(followerIndex CCRFollowIndexResponse) float64 {
+					var lag int64
+					for _, shard := range followerIndex.Shards {
+						lag += shard.LeaderGlobalCheckpoint - shard.FollowerGlobalCheckpoint
+					}
+					return float64(lag)
+				},
+				Labels: defaultCCRFollowerLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ccr", "follower_lag_time_seconds"),
+					"Time since the last read from the leader index in seconds, maximum across shards",
+					defaultCCRFollowerLabels, constLabels,
+				),
+				Value: func(followerIndex CCRFollowIndexResponse) float64 {
+					var maxMillis int64
+					for _, shard := range followerIndex.Shards {
+						if shard.TimeSinceLastReadMillis > maxMillis {
+							maxMillis = shard.TimeSinceLastReadMillis
+						}
+					}
+					return float64(maxMillis) / 1000
+				},
+				Labels: defaultCCRFollowerLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ccr", "outstanding_write_requests"),
+					"Number of outstanding write requests on the follower index",
+					defaultCCRFollowerLabels, constLabels,
+				),
+				Value: func(followerIndex CCRFollowIndexResponse) float64 {
+					var requests int64
+					for _, shard := range followerIndex.Shards {
+						requests += shard.OutstandingWriteRequests
+					}
+					return float64(requests)
+				},
+				Labels: defaultCCRFollowerLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ccr", "write_buffer_size_bytes"),
+					"Size of the operations queued for writing on the follower index in bytes",
+					defaultCCRFollowerLabels, constLabels,
+				),
+				Value: func(followerIndex CCRFollowIndexResponse) float64 {
+					var size int64
+					for _, shard := range followerIndex.Shards {
+						size += shard.WriteBufferSizeInBytes
+					}
+					return float64(size)
+				},
+				Labels: defaultCCRFollowerLabelValues,
+			},
+		},
+	}
+}
+
+// Describe add CCR metrics descriptions
+func (c *CCR) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.followerMetrics {
+		ch <- metric.Desc
+	}
+	ch <- c.autoFollowActive
+	ch <- c.autoFollowFollowed
+	ch <- c.autoFollowCreationFailures
+	ch <- c.up.Desc()
+	ch <- c.totalScrapes.Desc()
+	ch <- c.jsonParseFailures.Desc()
+}
+
+func (c *CCR) fetchAndDecodeCCRStats() (CCRStatsResponse, error) {
+	var csr CCRStatsResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_ccr/stats")
+	err := getAndDecodeURL(c.logger, c.client, &u, &csr, c.jsonParseFailures)
+	return csr, err
+}
+
+func (c *CCR) fetchAndDecodeAutoFollow() (CCRAutoFollowResponse, error) {
+	var cafr CCRAutoFollowResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_ccr/auto_follow")
+	err := getAndDecodeURL(c.logger, c.client, &u, &cafr, c.jsonParseFailures)
+	return cafr, err
+}
+
+// Collect gets CCR metric values
+func (c *CCR) Collect(ch chan<- prometheus.Metric) {
+	c.totalScrapes.Inc()
+	defer func() {
+		ch <- c.up
+		ch <- c.totalScrapes
+		ch <- c.jsonParseFailures
+	}()
+
+	ccrStatsResp, err := c.fetchAndDecodeCCRStats()
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode CCR stats",
+			"err", err,
+		)
+		return
+	}
+	c.up.Set(1)
+
+	for _, followerIndex := range ccrStatsResp.FollowStats.Indices {
+		for _, metric := range c.followerMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(followerIndex),
+				metric.Labels(followerIndex)...,
+			)
+		}
+	}
+
+	// Elasticsearch only reports the auto-follow stats summed up over all patterns
+	ch <- prometheus.MustNewConstMetric(
+		c.autoFollowFollowed,
+		prometheus.CounterValue,
+		float64(ccrStatsResp.AutoFollowStats.NumberOfSuccessfulFollowIndices),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.autoFollowCreationFailures,
+		prometheus.CounterValue,
+		float64(ccrStatsResp.AutoFollowStats.NumberOfFailedFollowIndices),
+	)
+
+	autoFollowResp, err := c.fetchAndDecodeAutoFollow()
+	if err != nil {
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode CCR auto-follow patterns",
+			"err", err,
+		)
+		return
+	}
+	for _, pattern := range autoFollowResp.Patterns {
+		var active float64
+		if pattern.IsActive() {
+			active = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.autoFollowActive,
+			prometheus.GaugeValue,
+			active,
+			pattern.Name,
+		)
+	}
+}
@@ -0,0 +1,62 @@
+package collector
+
+// CCRStatsResponse is a representation of the cross-cluster replication stats
+type CCRStatsResponse struct {
+	AutoFollowStats CCRAutoFollowStatsResponse `json:"auto_follow_stats"`
+	FollowStats     CCRFollowStatsResponse     `json:"follow_stats"`
+}
+
+// CCRAutoFollowStatsResponse is a representation of the CCR auto-follow stats, summed up over all auto-follow patterns
+type CCRAutoFollowStatsResponse struct {
+	NumberOfFailedFollowIndices              int64 `json:"number_of_failed_follow_indices"`
+	NumberOfFailedRemoteClusterStateRequests int64 `json:"number_of_failed_remote_cluster_state_requests"`
+	NumberOfSuccessfulFollowIndices          int64 `json:"number_of_successful_follow_indices"`
+}
+
+// CCRAutoFollowResponse is a representation of the CCR auto-follow patterns
+type CCRAutoFollowResponse struct {
+	Patterns []CCRAutoFollowPatternResponse `json:"patterns"`
+}
+
+// CCRAutoFollowPatternResponse is a representation of a single CCR auto-follow pattern
+type CCRAutoFollowPatternResponse struct {
+	Name    string `json:"name"`
+	Pattern struct {
+		// Active is only reported starting with 7.5, which added pausing auto-follow patterns
+		Active              *bool    `json:"active"`
+		RemoteCluster       string   `json:"remote_cluster"`
+		LeaderIndexPatterns []string `json:"leader_index_patterns"`
+	} `json:"pattern"`
+}
+
+// IsActive returns true unless the auto-follow pattern is paused
+func (p CCRAutoFollowPatternResponse) IsActive() bool {
+	return p.Pattern.Active == nil || *p.Pattern.Active
+}
+
+// CCRFollowStatsResponse is a representation of the CCR follower indices stats
+type CCRFollowStatsResponse struct {
+	Indices []CCRFollowIndexResponse `json:"indices"`
+}
+
+// CCRFollowIndexResponse is a representation of the stats of a single follower index
+type CCRFollowIndexResponse struct {
+	Index  string                   `json:"index"`
+	Shards []CCRFollowShardResponse `json:"shards"`
+}
+
+// CCRFollowShardResponse is a representation of the stats of a single follower shard
+type CCRFollowShardResponse struct {
+	RemoteCluster            string `json:"remote_cluster"`
+	LeaderIndex              string `json:"leader_index"`
+	FollowerIndex            string `json:"follower_index"`
+	ShardID                  int64  `json:"shard_id"`
+	LeaderGlobalCheckpoint   int64  `json:"leader_global_checkpoint"`
+	LeaderMaxSeqNo           int64  `json:"leader_max_seq_no"`
+	FollowerGlobalCheckpoint int64  `json:"follower_global_checkpoint"`
+	FollowerMaxSeqNo         int64  `json:"follower_max_seq_no"`
+	OutstandingReadRequests  int64  `json:"outstanding_read_requests"`
+	OutstandingWriteRequests int64  `json:"outstanding_write_requests"`
+	WriteBufferSizeInBytes   int64  `json:"write_buffer_size_in_bytes"`
+	TimeSinceLastReadMillis  int64  `json:"time_since_last_read_millis"`
+}
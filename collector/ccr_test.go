@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestCCRStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"persistent":{"cluster":{"remote":{"leader":{"seeds":["leader:9300"]}}}}}'
+	//  curl -XPUT http://localhost:9200/follower/_ccr/follow -H 'Content-Type: application/json' -d '{"remote_cluster":"leader","leader_index":"leader"}'
+	//  curl http://localhost:9200/_ccr/stats
+	tcs := map[string]string{
+		"7.6.2": `{"auto_follow_stats":{"number_of_failed_follow_indices":0,"number_of_failed_remote_cluster_state_requests":0,"number_of_successful_follow_indices":1,"recent_auto_follow_errors":[],"auto_followed_clusters":[]},"follow_stats":{"indices":[{"index":"follower","shards":[{"remote_cluster":"leader","leader_index":"leader","follower_index":"follower","shard_id":0,"leader_global_checkpoint":1024,"leader_max_seq_no":1536,"follower_global_checkpoint":768,"follower_max_seq_no":896,"last_requested_seq_no":897,"outstanding_read_requests":8,"outstanding_write_requests":2,"write_buffer_operation_count":64,"follower_mapping_version":4,"follower_settings_version":2,"follower_aliases_version":1,"total_read_time_millis":32768,"total_read_remote_exec_time_millis":16384,"successful_read_requests":32,"failed_read_requests":0,"operations_read":896,"bytes_read":32768,"total_write_time_millis":16384,"write_buffer_size_in_bytes":1536,"successful_write_requests":16,"failed_write_requests":0,"operations_written":832,"read_exceptions":[],"time_since_last_read_millis":8},{"remote_cluster":"leader","leader_index":"leader","follower_index":"follower","shard_id":1,"leader_global_checkpoint":512,"leader_max_seq_no":512,"follower_global_checkpoint":500,"follower_max_seq_no":500,"last_requested_seq_no":501,"outstanding_read_requests":1,"outstanding_write_requests":1,"write_buffer_operation_count":12,"follower_mapping_version":4,"follower_settings_version":2,"follower_aliases_version":1,"total_read_time_millis":1024,"total_read_remote_exec_time_millis":512,"successful_read_requests":8,"failed_read_requests":0,"operations_read":500,"bytes_read":16384,"total_write_time_millis":2048,"write_buffer_size_in_bytes":512,"successful_write_requests":8,"failed_write_requests":0,"operations_written":500,"read_exceptions":[],"time_since_last_read_millis":2500}]}]}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewCCR(log.NewNopLogger(), http.DefaultClient, u)
+		csr, err := c.fetchAndDecodeCCRStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode CCR stats: %s", err)
+		}
+		t.Logf("[%s] CCR Stats Response: %+v", ver, csr)
+		if len(csr.FollowStats.Indices) != 1 {
+			t.Fatalf("Wrong number of follower indices")
+		}
+		followerIndex := csr.FollowStats.Indices[0]
+		labels := defaultCCRFollowerLabelValues(followerIndex)
+		if labels[0] != "leader" || labels[1] != "follower" {
+			t.Errorf("Wrong follower labels: %v", labels)
+		}
+		// lag, lag time, outstanding writes and write buffer are aggregated across shards
+		expected := []float64{268, 2.5, 3, 2048}
+		for i, metric := range c.followerMetrics {
+			if v := metric.Value(followerIndex); v != expected[i] {
+				t.Errorf("Wrong value for metric %d: got %v, expected %v", i, v, expected[i])
+			}
+		}
+		if csr.AutoFollowStats.NumberOfSuccessfulFollowIndices != 1 || csr.AutoFollowStats.NumberOfFailedFollowIndices != 0 {
+			t.Errorf("Wrong auto-follow stats: %+v", csr.AutoFollowStats)
+		}
+	}
+}
+
+func TestCCRAutoFollow(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  curl -XPUT http://localhost:9200/_ccr/auto_follow/logs -H 'Content-Type: application/json' -d '{"remote_cluster":"leader","leader_index_patterns":["logs-*"],"follow_index_pattern":"{{leader_index}}-copy"}'
+	//  curl -XPUT http://localhost:9200/_ccr/auto_follow/metrics -H 'Content-Type: application/json' -d '{"remote_cluster":"leader","leader_index_patterns":["metrics-*"]}'
+	//  curl -XPOST http://localhost:9200/_ccr/auto_follow/metrics/pause
+	//  curl http://localhost:9200/_ccr/auto_follow
+	tcs := map[string]string{
+		"7.4.2":  `{"patterns":[{"name":"logs","pattern":{"remote_cluster":"leader","leader_index_patterns":["logs-*"],"follow_index_pattern":"{{leader_index}}-copy"}}]}`,
+		"7.10.2": `{"patterns":[{"name":"logs","pattern":{"active":true,"remote_cluster":"leader","leader_index_patterns":["logs-*"],"follow_index_pattern":"{{leader_index}}-copy"}},{"name":"metrics","pattern":{"active":false,"remote_cluster":"leader","leader_index_patterns":["metrics-*"]}}]}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewCCR(log.NewNopLogger(), http.DefaultClient, u)
+		cafr, err := c.fetchAndDecodeAutoFollow()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode CCR auto-follow patterns: %s", err)
+		}
+		t.Logf("[%s] CCR Auto Follow Response: %+v", ver, cafr)
+		if cafr.Patterns[0].Name != "logs" || !cafr.Patterns[0].IsActive() {
+			t.Errorf("Pattern logs should be active")
+		}
+		if ver == "7.4.2" {
+			continue
+		}
+		if len(cafr.Patterns) != 2 || cafr.Patterns[1].IsActive() {
+			t.Errorf("Pattern metrics should be paused")
+		}
+	}
+}
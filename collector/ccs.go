@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	defaultCCSRemoteLabels = []string{"remote_cluster"}
+)
+
+// CCS information struct
+type CCS struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	remoteSearches *prometheus.Desc
+	remoteFailures *prometheus.Desc
+	remoteLatency  *prometheus.Desc
+}
+
+// NewCCS defines cross-cluster search Prometheus metrics
+func NewCCS(logger log.Logger, client *http.Client, url *url.URL) *CCS {
+	constLabels := constLabelsFromURL(url)
+	return &CCS{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "ccs", "up"),
+			Help:        "Was the last scrape of the ElasticSearch cluster stats endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ccs", "total_scrapes"),
+			Help:        "Current total ElasticSearch CCS scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ccs", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		remoteSearches: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ccs", "search_remotes_total"),
+			"Total number of cross-cluster searches involving the cluster",
+			defaultCCSRemoteLabels, constLabels,
+		),
+		remoteFailures: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ccs", "search_remote_failure_total"),
+			"Total number of cross-cluster searches which skipped the cluster because it failed or was unavailable",
+			defaultCCSRemoteLabels, constLabels,
+		),
+		remoteLatency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ccs", "search_latency_seconds"),
+			"Latency of the cross-cluster searches involving the cluster, with the 90th percentile and the maximum as quantiles",
+			defaultCCSRemoteLabels, constLabels,
+		),
+	}
+}
+
+// Describe add CCS metrics descriptions
+func (c *CCS) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.remoteSearches
+	ch <- c.remoteFailures
+	ch <- c.remoteLatency
+	ch <- c.up.Desc()
+	ch <- c.totalScrapes.Desc()
+	ch <- c.jsonParseFailures.Desc()
+}
+
+func (c *CCS) fetchAndDecodeCCSStats() (CCSClusterStatsResponse, error) {
+	var csr CCSClusterStatsResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_cluster/stats")
+	u.RawQuery = "filter_path=ccs._search"
+	err := getAndDecodeURL(c.logger, c.client, &u, &csr, c.jsonParseFailures)
+	return csr, err
+}
+
+// Collect gets CCS metric values
+func (c *CCS) Collect(ch chan<- prometheus.Metric) {
+	c.totalScrapes.Inc()
+	defer func() {
+		ch <- c.up
+		ch <- c.totalScrapes
+		ch <- c.jsonParseFailures
+	}()
+
+	ccsStatsResp, err := c.fetchAndDecodeCCSStats()
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode CCS stats",
+			"err", err,
+		)
+		return
+	}
+	c.up.Set(1)
+
+	// versions without cross-cluster search telemetry do not report any searches
+	if ccsStatsResp.CCS.Search == nil {
+		return
+	}
+	for remoteCluster, stats := range ccsStatsResp.CCS.Search.Clusters {
+		ch <- prometheus.MustNewConstMetric(
+			c.remoteSearches,
+			prometheus.CounterValue,
+			float64(stats.Total),
+			remoteCluster,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.remoteFailures,
+			prometheus.CounterValue,
+			float64(stats.Skipped),
+			remoteCluster,
+		)
+		// only the average, 90th percentile and maximum are reported, so the sum is derived from the average
+		ch <- prometheus.MustNewConstSummary(
+			c.remoteLatency,
+			uint64(stats.Total),
+			stats.Took.Avg*float64(stats.Total)/1000,
+			map[float64]float64{
+				0.9: float64(stats.Took.P90) / 1000,
+				1:   float64(stats.Took.Max) / 1000,
+			},
+			remoteCluster,
+		)
+	}
+}
@@ -0,0 +1,32 @@
+package collector
+
+// CCSClusterStatsResponse is a representation of the cross-cluster search telemetry of the cluster stats API
+type CCSClusterStatsResponse struct {
+	CCS struct {
+		// Search is only reported by versions with cross-cluster search telemetry
+		Search *CCSSearchResponse `json:"_search"`
+	} `json:"ccs"`
+}
+
+// CCSSearchResponse is a representation of the cross-cluster searches coordinated by the cluster
+type CCSSearchResponse struct {
+	Total   int64 `json:"total"`
+	Success int64 `json:"success"`
+	Skipped int64 `json:"skipped"`
+	// Clusters holds the searches per remote cluster alias, the local cluster is reported as (local)
+	Clusters map[string]CCSSearchClusterResponse `json:"clusters"`
+}
+
+// CCSSearchClusterResponse is a representation of the cross-cluster searches involving a single cluster
+type CCSSearchClusterResponse struct {
+	Total   int64           `json:"total"`
+	Skipped int64           `json:"skipped"`
+	Took    CCSTookResponse `json:"took"`
+}
+
+// CCSTookResponse is a representation of the search latency in milliseconds
+type CCSTookResponse struct {
+	Max int64   `json:"max"`
+	Avg float64 `json:"avg"`
+	P90 int64   `json:"p90"`
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestCCS(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION (and a remote cluster)
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"persistent":{"cluster":{"remote":{"cluster_one":{"seeds":["172.17.0.3:9300"],"skip_unavailable":true}}}}}'
+	//  curl 'http://localhost:9200/twitter,cluster_one:twitter/_search' (repeatedly, while cluster_one restarts)
+	//  curl 'http://localhost:9200/_cluster/stats?filter_path=ccs._search'
+	tcs := map[string]string{
+		"7.17.0": `{}`,
+		"8.16.0": `{"ccs":{"_search":{"total":10,"success":9,"skipped":1,"took":{"max":120,"avg":45.0,"p90":100},"took_mrt_true":{"max":120,"avg":45.0,"p90":100},"took_mrt_false":{"max":0,"avg":0.0,"p90":0},"remotes_per_search_max":2,"remotes_per_search_avg":1.5,"failure_reasons":{"unknown":1},"features":{"mrt":10},"clients":{"unknown":10},"clusters":{"(local)":{"total":10,"skipped":0,"took":{"max":60,"avg":20.0,"p90":50}},"cluster_one":{"total":10,"skipped":1,"took":{"max":120,"avg":40.0,"p90":100}}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewCCS(log.NewNopLogger(), http.DefaultClient, u)
+		csr, err := c.fetchAndDecodeCCSStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode CCS stats: %s", err)
+		}
+		t.Logf("[%s] CCS Stats Response: %+v", ver, csr)
+		if ver == "7.17.0" {
+			if csr.CCS.Search != nil {
+				t.Errorf("Unexpected CCS telemetry before 8.x")
+			}
+			continue
+		}
+		if csr.CCS.Search == nil {
+			t.Fatalf("Missing CCS telemetry")
+		}
+		remote, ok := csr.CCS.Search.Clusters["cluster_one"]
+		if !ok || len(csr.CCS.Search.Clusters) != 2 {
+			t.Fatalf("Wrong clusters: %+v", csr.CCS.Search.Clusters)
+		}
+		if remote.Total != 10 || remote.Skipped != 1 || remote.Took.Avg != 40 || remote.Took.P90 != 100 || remote.Took.Max != 120 {
+			t.Errorf("Wrong remote cluster stats: %+v", remote)
+		}
+	}
+}
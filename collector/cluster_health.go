@@ -1,11 +1,14 @@
 package collector
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -19,6 +22,9 @@ const (
 var (
 	colors                     = []string{"green", "yellow", "red"}
 	defaultClusterHealthLabels = []string{"cluster"}
+
+	// errMasterNotDiscovered is returned while the cluster is electing a master node
+	errMasterNotDiscovered = errors.New("master not discovered")
 )
 
 type clusterHealthMetric struct {
@@ -45,18 +51,72 @@ type ClusterHealth struct {
 
 	metrics      []*clusterHealthMetric
 	statusMetric *clusterHealthStatusMetric
+
+	healthScoreFormula *HealthScoreFormula
+	healthScore        *prometheus.Desc
+
+	heapStats          bool
+	maxHeapUsedPercent *prometheus.Desc
+	avgHeapUsedPercent *prometheus.Desc
+
+	masterNode       *masterNodeTracker
+	masterNodeInfo   *prometheus.Desc
+	masterNotElected prometheus.Gauge
+
+	masterRetries      int
+	masterRetryBackoff time.Duration
 }
 
 // NewClusterHealth returns a new Collector exposing ClusterHealth stats.
-func NewClusterHealth(logger log.Logger, client *http.Client, url *url.URL) *ClusterHealth {
+// A nil healthScoreFormula falls back to DefaultHealthScoreFormula. While no master is elected
+// the cluster health is fetched again up to masterRetries times, waiting masterRetryBackoff in between.
+// With heapStats the maximum and average heap usage of the nodes are fetched as well.
+func NewClusterHealth(logger log.Logger, client *http.Client, url *url.URL, healthScoreFormula *HealthScoreFormula, masterRetries int, masterRetryBackoff time.Duration, heapStats bool) *ClusterHealth {
 	subsystem := "cluster_health"
 	constLabels := constLabelsFromURL(url)
 
+	if healthScoreFormula == nil {
+		// the default formula is known to be valid
+		healthScoreFormula, _ = ParseHealthScoreFormula(DefaultHealthScoreFormula)
+	}
+
 	return &ClusterHealth{
 		logger: logger,
 		client: client,
 		url:    url,
 
+		healthScoreFormula: healthScoreFormula,
+		masterRetries:      masterRetries,
+		masterRetryBackoff: masterRetryBackoff,
+		heapStats:          heapStats,
+		maxHeapUsedPercent: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "max_heap_used_percent"),
+			"Highest heap usage percentage across all nodes.",
+			defaultClusterHealthLabels, constLabels,
+		),
+		avgHeapUsedPercent: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "avg_heap_used_percent"),
+			"Average heap usage percentage of all nodes.",
+			defaultClusterHealthLabels, constLabels,
+		),
+		healthScore: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "score"),
+			"Composite cluster health score computed from the configured formula.",
+			defaultClusterHealthLabels, constLabels,
+		),
+		masterNode: newMasterNodeTracker("master_node_changes_total",
+			"Number of times the elected master node changed between scrapes.", constLabels),
+		masterNodeInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "master_node_info"),
+			"Constant metric with the currently elected master node as labels.",
+			[]string{"cluster", "node_id", "node_name"}, constLabels,
+		),
+		masterNotElected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "cluster", "master_not_elected"),
+			Help:        "Whether the cluster health endpoint reported that no master node is elected on the last scrape.",
+			ConstLabels: constLabels,
+		}),
+
 		up: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name:        prometheus.BuildFQName(namespace, subsystem, "up"),
 			Help:        "Was the last scrape of the ElasticSearch cluster health endpoint successful.",
@@ -219,18 +279,29 @@ func (c *ClusterHealth) Describe(ch chan<- *prometheus.Desc) {
 		ch <- metric.Desc
 	}
 	ch <- c.statusMetric.Desc
+	ch <- c.healthScore
+	ch <- c.maxHeapUsedPercent
+	ch <- c.avgHeapUsedPercent
+	ch <- c.masterNode.changes.Desc()
+	ch <- c.masterNodeInfo
+	ch <- c.masterNotElected.Desc()
 
 	ch <- c.up.Desc()
 	ch <- c.totalScrapes.Desc()
 	ch <- c.jsonParseFailures.Desc()
 }
 
-func (c *ClusterHealth) fetchAndDecodeClusterHealth() (clusterHealthResponse, error) {
+func (c *ClusterHealth) fetchAndDecodeClusterHealth(ctx context.Context) (clusterHealthResponse, error) {
 	var chr clusterHealthResponse
 
 	u := *c.url
 	u.Path = path.Join(u.Path, "/_cluster/health")
-	res, err := c.client.Get(u.String())
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return chr, err
+	}
+	req = req.WithContext(ctx)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return chr, fmt.Errorf("failed to get cluster health from %s://%s:%s%s: %s",
 			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
@@ -247,6 +318,10 @@ func (c *ClusterHealth) fetchAndDecodeClusterHealth() (clusterHealthResponse, er
 	}()
 
 	if res.StatusCode != http.StatusOK {
+		var er errorResponse
+		if json.NewDecoder(res.Body).Decode(&er) == nil && er.Error.Type == "master_not_discovered_exception" {
+			return chr, errMasterNotDiscovered
+		}
 		return chr, fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
 	}
 
@@ -258,6 +333,39 @@ func (c *ClusterHealth) fetchAndDecodeClusterHealth() (clusterHealthResponse, er
 	return chr, nil
 }
 
+// fetchClusterHealthWithRetry fetches the cluster health, retrying while the cluster elects a master
+// node so that short elections do not mark the cluster as down. All attempts and the backoff in
+// between share the timeout of the HTTP client, so the retries do not run past the scrape.
+func (c *ClusterHealth) fetchClusterHealthWithRetry() (clusterHealthResponse, error) {
+	ctx := context.Background()
+	if c.client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.client.Timeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		chr, err := c.fetchAndDecodeClusterHealth(ctx)
+		if err != errMasterNotDiscovered {
+			c.masterNotElected.Set(0)
+			return chr, err
+		}
+		c.masterNotElected.Set(1)
+		if attempt > c.masterRetries {
+			return chr, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(c.masterRetryBackoff).After(deadline) {
+			return chr, err
+		}
+		_ = level.Debug(c.logger).Log(
+			"msg", "no master node elected, retrying cluster health",
+			"attempt", attempt,
+			"backoff", c.masterRetryBackoff,
+		)
+		time.Sleep(c.masterRetryBackoff)
+	}
+}
+
 // Collect collects ClusterHealth metrics.
 func (c *ClusterHealth) Collect(ch chan<- prometheus.Metric) {
 	var err error
@@ -266,9 +374,11 @@ func (c *ClusterHealth) Collect(ch chan<- prometheus.Metric) {
 		ch <- c.up
 		ch <- c.totalScrapes
 		ch <- c.jsonParseFailures
+		ch <- c.masterNode.changes
+		ch <- c.masterNotElected
 	}()
 
-	clusterHealthResp, err := c.fetchAndDecodeClusterHealth()
+	clusterHealthResp, err := c.fetchClusterHealthWithRetry()
 	if err != nil {
 		c.up.Set(0)
 		_ = level.Warn(c.logger).Log(
@@ -296,4 +406,134 @@ func (c *ClusterHealth) Collect(ch chan<- prometheus.Metric) {
 			clusterHealthResp.ClusterName, color,
 		)
 	}
+
+	masterNode, err := fetchAndDecodeMasterNode(c.logger, c.client, c.url, c.jsonParseFailures)
+	if err != nil {
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode master node",
+			"err", err,
+		)
+	} else {
+		c.masterNode.track(masterNode.ID)
+		ch <- prometheus.MustNewConstMetric(
+			c.masterNodeInfo,
+			prometheus.GaugeValue,
+			1,
+			clusterHealthResp.ClusterName, masterNode.ID, masterNode.Node,
+		)
+	}
+
+	// the JVM stats are fetched once for the heap metrics and the health score
+	var maxHeapUsedPercent float64
+	if c.heapStats || c.healthScoreFormula.Uses("heap_used_percent") {
+		var avgHeapUsedPercent float64
+		maxHeapUsedPercent, avgHeapUsedPercent, err = c.fetchHeapUsedPercent()
+		if err != nil {
+			_ = level.Warn(c.logger).Log(
+				"msg", "failed to fetch and decode nodes jvm stats",
+				"err", err,
+			)
+			if c.healthScoreFormula.Uses("heap_used_percent") {
+				return
+			}
+		} else if c.heapStats {
+			ch <- prometheus.MustNewConstMetric(
+				c.maxHeapUsedPercent,
+				prometheus.GaugeValue,
+				maxHeapUsedPercent,
+				clusterHealthResp.ClusterName,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				c.avgHeapUsedPercent,
+				prometheus.GaugeValue,
+				avgHeapUsedPercent,
+				clusterHealthResp.ClusterName,
+			)
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.healthScore,
+		prometheus.GaugeValue,
+		c.healthScoreFormula.Eval(c.healthScoreVariables(clusterHealthResp, maxHeapUsedPercent)),
+		clusterHealthResp.ClusterName,
+	)
+}
+
+// healthScoreVariables builds the variables available to the health score formula, maxHeapUsedPercent
+// is only fetched when the formula references heap_used_percent or the heap metrics are enabled.
+func (c *ClusterHealth) healthScoreVariables(clusterHealth clusterHealthResponse, maxHeapUsedPercent float64) map[string]float64 {
+	vars := map[string]float64{
+		"number_of_nodes":                  float64(clusterHealth.NumberOfNodes),
+		"number_of_data_nodes":             float64(clusterHealth.NumberOfDataNodes),
+		"active_primary_shards":            float64(clusterHealth.ActivePrimaryShards),
+		"active_shards":                    float64(clusterHealth.ActiveShards),
+		"relocating_shards":                float64(clusterHealth.RelocatingShards),
+		"initializing_shards":              float64(clusterHealth.InitializingShards),
+		"unassigned_shards":                float64(clusterHealth.UnassignedShards),
+		"delayed_unassigned_shards":        float64(clusterHealth.DelayedUnassignedShards),
+		"pending_tasks":                    float64(clusterHealth.NumberOfPendingTasks),
+		"in_flight_fetch":                  float64(clusterHealth.NumberOfInFlightFetch),
+		"task_max_waiting_in_queue_millis": float64(clusterHealth.TaskMaxWaitingInQueueMillis),
+		"heap_used_percent":                maxHeapUsedPercent,
+	}
+	for _, color := range colors {
+		if clusterHealth.Status == color {
+			vars["status_"+color] = 1
+		} else {
+			vars["status_"+color] = 0
+		}
+	}
+	return vars
+}
+
+// fetchHeapUsedPercent returns the highest and the average heap usage percentage across all nodes
+func (c *ClusterHealth) fetchHeapUsedPercent() (float64, float64, error) {
+	var nsr nodeStatsResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_nodes/stats/jvm")
+	u.RawQuery = "filter_path=nodes.*.jvm.mem.heap_used_in_bytes,nodes.*.jvm.mem.heap_max_in_bytes"
+	res, err := c.client.Get(u.String())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get nodes jvm stats from %s://%s:%s%s: %s",
+			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
+	}
+
+	defer func() {
+		err = res.Body.Close()
+		if err != nil {
+			_ = level.Warn(c.logger).Log(
+				"msg", "failed to close http.Client",
+				"err", err,
+			)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&nsr); err != nil {
+		c.jsonParseFailures.Inc()
+		return 0, 0, err
+	}
+
+	var maxHeapUsedPercent, sumHeapUsedPercent float64
+	var nodes int
+	for _, node := range nsr.Nodes {
+		if node.JVM.Mem.HeapMax == 0 {
+			continue
+		}
+		heapUsedPercent := float64(node.JVM.Mem.HeapUsed) / float64(node.JVM.Mem.HeapMax) * 100
+		if heapUsedPercent > maxHeapUsedPercent {
+			maxHeapUsedPercent = heapUsedPercent
+		}
+		sumHeapUsedPercent += heapUsedPercent
+		nodes++
+	}
+	if nodes == 0 {
+		return 0, 0, nil
+	}
+	return maxHeapUsedPercent, sumHeapUsedPercent / float64(nodes), nil
 }
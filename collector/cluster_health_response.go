@@ -17,3 +17,18 @@ type clusterHealthResponse struct {
 	TaskMaxWaitingInQueueMillis int     `json:"task_max_waiting_in_queue_millis"`
 	ActiveShardsPercentAsNumber float64 `json:"active_shards_percent_as_number"`
 }
+
+// errorResponse is the body of a failed Elasticsearch request
+type errorResponse struct {
+	Error struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+type catMasterResponse struct {
+	ID   string `json:"id"`
+	Host string `json:"host"`
+	IP   string `json:"ip"`
+	Node string `json:"node"`
+}
@@ -1,13 +1,16 @@
 package collector
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/go-kit/kit/log"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestClusterHealth(t *testing.T) {
@@ -30,8 +33,8 @@ func TestClusterHealth(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to parse URL: %s", err)
 		}
-		c := NewClusterHealth(log.NewNopLogger(), http.DefaultClient, u)
-		chr, err := c.fetchAndDecodeClusterHealth()
+		c := NewClusterHealth(log.NewNopLogger(), http.DefaultClient, u, nil, 0, 0, false)
+		chr, err := c.fetchAndDecodeClusterHealth(context.Background())
 		if err != nil {
 			t.Fatalf("Failed to fetch or decode cluster health: %s", err)
 		}
@@ -58,3 +61,131 @@ func TestClusterHealth(t *testing.T) {
 		}
 	}
 }
+
+func TestClusterHealthMasterNode(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_cat/master?format=json
+	masters := []string{
+		`[{"id":"9_P7yui4SQOkzGhTZCyjxQ","host":"172.17.0.2","ip":"172.17.0.2","node":"node-0"}]`,
+		`[{"id":"9_P7yui4SQOkzGhTZCyjxQ","host":"172.17.0.2","ip":"172.17.0.2","node":"node-0"}]`,
+		`[{"id":"Jx0Vt0hTR0iVbVGRx1oBCA","host":"172.17.0.3","ip":"172.17.0.3","node":"node-1"}]`,
+	}
+	var scrape int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, masters[scrape])
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	c := NewClusterHealth(log.NewNopLogger(), http.DefaultClient, u, nil, 0, 0, false)
+	for scrape = range masters {
+		cmr, err := fetchAndDecodeMasterNode(c.logger, c.client, c.url, c.jsonParseFailures)
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode master node: %s", err)
+		}
+		c.masterNode.track(cmr.ID)
+	}
+	if c.masterNode.lastMasterNodeID != "Jx0Vt0hTR0iVbVGRx1oBCA" {
+		t.Errorf("Wrong last master node id")
+	}
+	var m dto.Metric
+	if err := c.masterNode.changes.Write(&m); err != nil {
+		t.Fatalf("Failed to read master node changes: %s", err)
+	}
+	if m.GetCounter().GetValue() != 1 {
+		t.Errorf("Wrong number of master node changes: %v", m.GetCounter().GetValue())
+	}
+}
+
+func TestClusterHealthMasterNotDiscovered(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "cluster.initial_master_nodes=node-0,node-1" elasticsearch:VERSION
+	//  curl http://localhost:9200/_cluster/health
+	masterNotDiscovered := `{"error":{"root_cause":[{"type":"master_not_discovered_exception","reason":null}],"type":"master_not_discovered_exception","reason":null},"status":503}`
+	health := `{"cluster_name":"elasticsearch","status":"green","timed_out":false,"number_of_nodes":1,"number_of_data_nodes":1,"active_primary_shards":0,"active_shards":0,"relocating_shards":0,"initializing_shards":0,"unassigned_shards":0,"delayed_unassigned_shards":0,"number_of_pending_tasks":0,"number_of_in_flight_fetch":0,"task_max_waiting_in_queue_millis":0,"active_shards_percent_as_number":100.0}`
+
+	// number of failed requests before the master is elected, and whether the scrape succeeds with 2 retries
+	tcs := map[int]bool{
+		0: true,
+		2: true,
+		3: false,
+	}
+	for failures, succeeds := range tcs {
+		var requests int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests <= failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprintln(w, masterNotDiscovered)
+				return
+			}
+			fmt.Fprintln(w, health)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewClusterHealth(log.NewNopLogger(), http.DefaultClient, u, nil, 2, time.Millisecond, false)
+		chr, err := c.fetchClusterHealthWithRetry()
+		var m dto.Metric
+		if err := c.masterNotElected.Write(&m); err != nil {
+			t.Fatalf("Failed to read master not elected: %s", err)
+		}
+		if !succeeds {
+			if err != errMasterNotDiscovered {
+				t.Errorf("[%d failures] Expected master not discovered error, got %v", failures, err)
+			}
+			if m.GetGauge().GetValue() != 1 {
+				t.Errorf("[%d failures] Master not elected should be 1", failures)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("[%d failures] Failed to fetch or decode cluster health: %s", failures, err)
+		}
+		if chr.Status != "green" {
+			t.Errorf("[%d failures] Wrong cluster health status", failures)
+		}
+		if m.GetGauge().GetValue() != 0 {
+			t.Errorf("[%d failures] Master not elected should be 0", failures)
+		}
+		if requests != failures+1 {
+			t.Errorf("[%d failures] Wrong number of requests: %d", failures, requests)
+		}
+	}
+}
+
+func TestClusterHealthMasterRetryTimeout(t *testing.T) {
+	masterNotDiscovered := `{"error":{"root_cause":[{"type":"master_not_discovered_exception","reason":null}],"type":"master_not_discovered_exception","reason":null},"status":503}`
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, masterNotDiscovered)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	// the backoff allows only one retry within the timeout
+	client := &http.Client{Timeout: 300 * time.Millisecond}
+	c := NewClusterHealth(log.NewNopLogger(), client, u, nil, 10, 200*time.Millisecond, false)
+	start := time.Now()
+	if _, err := c.fetchClusterHealthWithRetry(); err != errMasterNotDiscovered {
+		t.Errorf("Expected master not discovered error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > client.Timeout {
+		t.Errorf("Retries ran past the timeout: %s", elapsed)
+	}
+	if requests != 2 {
+		t.Errorf("Wrong number of requests: %d", requests)
+	}
+}
@@ -1,11 +1,11 @@
 package collector
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
+	"strings"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -13,6 +13,22 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+var (
+	routingAllocationModes = map[string]float64{
+		"all":           3,
+		"primaries":     2,
+		"new_primaries": 1,
+		"none":          0,
+	}
+
+	routingRebalanceModes = map[string]float64{
+		"all":       3,
+		"primaries": 2,
+		"replicas":  1,
+		"none":      0,
+	}
+)
+
 // ClusterSettings information struct
 type ClusterSettings struct {
 	logger log.Logger
@@ -21,7 +37,13 @@ type ClusterSettings struct {
 
 	up                              prometheus.Gauge
 	shardAllocationEnabled          prometheus.Gauge
+	routingAllocationEnabled        prometheus.Gauge
+	routingRebalanceEnabled         prometheus.Gauge
+	clusterConcurrentRebalance      prometheus.Gauge
 	totalScrapes, jsonParseFailures prometheus.Counter
+
+	snapshotMaxConcurrentOperations *prometheus.Desc
+	snapshotConcurrencyRatio        *prometheus.Desc
 }
 
 // NewClusterSettings defines Cluster Settings Prometheus metrics
@@ -47,11 +69,36 @@ func NewClusterSettings(logger log.Logger, client *http.Client, url *url.URL) *C
 			Help:        "Current mode of cluster wide shard routing allocation settings.",
 			ConstLabels: constLabels,
 		}),
+		routingAllocationEnabled: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "clustersettings_stats", "routing_allocation_enabled"),
+			Help:        "Cluster wide shard allocation mode (all=3, primaries=2, new_primaries=1, none=0).",
+			ConstLabels: constLabels,
+		}),
+		routingRebalanceEnabled: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "clustersettings_stats", "routing_rebalance_enabled"),
+			Help:        "Cluster wide shard rebalancing mode (all=3, primaries=2, replicas=1, none=0).",
+			ConstLabels: constLabels,
+		}),
+		clusterConcurrentRebalance: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "clustersettings_stats", "routing_allocation_cluster_concurrent_rebalance"),
+			Help:        "Number of concurrent shard rebalances allowed cluster wide, -1 for unlimited.",
+			ConstLabels: constLabels,
+		}),
 		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
 			Name:        prometheus.BuildFQName(namespace, "clustersettings_stats", "json_parse_failures"),
 			Help:        "Number of errors while parsing JSON.",
 			ConstLabels: constLabels,
 		}),
+		snapshotMaxConcurrentOperations: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "snapshot_max_concurrent_operations"),
+			"Maximum number of concurrent snapshot operations allowed cluster wide.",
+			nil, constLabels,
+		),
+		snapshotConcurrencyRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "snapshot_concurrency_ratio"),
+			"Ratio of the running snapshots to the maximum number of concurrent snapshot operations.",
+			nil, constLabels,
+		),
 	}
 }
 
@@ -60,47 +107,24 @@ func (cs *ClusterSettings) Describe(ch chan<- *prometheus.Desc) {
 	ch <- cs.up.Desc()
 	ch <- cs.totalScrapes.Desc()
 	ch <- cs.shardAllocationEnabled.Desc()
+	ch <- cs.routingAllocationEnabled.Desc()
+	ch <- cs.routingRebalanceEnabled.Desc()
+	ch <- cs.clusterConcurrentRebalance.Desc()
+	ch <- cs.snapshotMaxConcurrentOperations
+	ch <- cs.snapshotConcurrencyRatio
 	ch <- cs.jsonParseFailures.Desc()
 }
 
-func (cs *ClusterSettings) getAndParseURL(u *url.URL, data interface{}) error {
-	res, err := cs.client.Get(u.String())
-	if err != nil {
-		return fmt.Errorf("failed to get from %s://%s:%s%s: %s",
-			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
-	}
-
-	defer func() {
-		err = res.Body.Close()
-		if err != nil {
-			_ = level.Warn(cs.logger).Log(
-				"msg", "failed to close http.Client",
-				"err", err,
-			)
-		}
-	}()
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
-	}
-
-	if err := json.NewDecoder(res.Body).Decode(data); err != nil {
-		cs.jsonParseFailures.Inc()
-		return err
-	}
-	return nil
-}
-
 func (cs *ClusterSettings) fetchAndDecodeClusterSettingsStats() (ClusterSettingsResponse, error) {
 
 	u := *cs.url
 	u.Path = path.Join(u.Path, "/_cluster/settings")
 	q := u.Query()
 	q.Set("include_defaults", "true")
-	u.RawPath = q.Encode()
+	u.RawQuery = q.Encode()
 	var csfr ClusterSettingsFullResponse
 	var csr ClusterSettingsResponse
-	err := cs.getAndParseURL(&u, &csfr)
+	err := getAndDecodeURL(cs.logger, cs.client, &u, &csfr, cs.jsonParseFailures)
 	if err != nil {
 		return csr, err
 	}
@@ -117,6 +141,16 @@ func (cs *ClusterSettings) fetchAndDecodeClusterSettingsStats() (ClusterSettings
 	return csr, err
 }
 
+func (cs *ClusterSettings) fetchAndDecodeSnapshotsStatus() (SnapshotsStatusResponse, error) {
+	var ssr SnapshotsStatusResponse
+
+	u := *cs.url
+	// without a repository only the running snapshots of all repositories are returned
+	u.Path = path.Join(u.Path, "/_snapshot/_status")
+	err := getAndDecodeURL(cs.logger, cs.client, &u, &ssr, cs.jsonParseFailures)
+	return ssr, err
+}
+
 // Collect gets cluster settings  metric values
 func (cs *ClusterSettings) Collect(ch chan<- prometheus.Metric) {
 
@@ -126,11 +160,17 @@ func (cs *ClusterSettings) Collect(ch chan<- prometheus.Metric) {
 		ch <- cs.totalScrapes
 		ch <- cs.jsonParseFailures
 		ch <- cs.shardAllocationEnabled
+		ch <- cs.routingAllocationEnabled
+		ch <- cs.routingRebalanceEnabled
+		ch <- cs.clusterConcurrentRebalance
 	}()
 
 	csr, err := cs.fetchAndDecodeClusterSettingsStats()
 	if err != nil {
 		cs.shardAllocationEnabled.Set(0)
+		cs.routingAllocationEnabled.Set(0)
+		cs.routingRebalanceEnabled.Set(0)
+		cs.clusterConcurrentRebalance.Set(0)
 		cs.up.Set(0)
 		_ = level.Warn(cs.logger).Log(
 			"msg", "failed to fetch and decode cluster settings stats",
@@ -148,4 +188,51 @@ func (cs *ClusterSettings) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	cs.shardAllocationEnabled.Set(float64(shardAllocationMap[csr.Cluster.Routing.Allocation.Enabled]))
+
+	// the modes are reported in upper case by some versions
+	cs.routingAllocationEnabled.Set(routingAllocationModes[strings.ToLower(csr.Cluster.Routing.Allocation.Enabled)])
+	cs.routingRebalanceEnabled.Set(routingRebalanceModes[strings.ToLower(csr.Cluster.Routing.Rebalance.Enabled)])
+
+	concurrentRebalance, err := strconv.ParseFloat(csr.Cluster.Routing.Allocation.ClusterConcurrentRebalance, 64)
+	if err != nil {
+		_ = level.Warn(cs.logger).Log(
+			"msg", "failed to parse cluster.routing.allocation.cluster_concurrent_rebalance",
+			"err", err,
+		)
+	}
+	cs.clusterConcurrentRebalance.Set(concurrentRebalance)
+
+	// only reported starting with 7.9
+	if csr.Snapshot.MaxConcurrentOperations == "" {
+		return
+	}
+	maxConcurrentOperations, err := strconv.ParseFloat(csr.Snapshot.MaxConcurrentOperations, 64)
+	if err != nil {
+		_ = level.Warn(cs.logger).Log(
+			"msg", "failed to parse snapshot.max_concurrent_operations",
+			"err", err,
+		)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		cs.snapshotMaxConcurrentOperations,
+		prometheus.GaugeValue,
+		maxConcurrentOperations,
+	)
+
+	snapshotsStatusResp, err := cs.fetchAndDecodeSnapshotsStatus()
+	if err != nil {
+		_ = level.Warn(cs.logger).Log(
+			"msg", "failed to fetch and decode running snapshots",
+			"err", err,
+		)
+		return
+	}
+	if maxConcurrentOperations > 0 {
+		ch <- prometheus.MustNewConstMetric(
+			cs.snapshotConcurrencyRatio,
+			prometheus.GaugeValue,
+			float64(len(snapshotsStatusResp.Snapshots))/maxConcurrentOperations,
+		)
+	}
 }
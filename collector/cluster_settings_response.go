@@ -9,7 +9,8 @@ type ClusterSettingsFullResponse struct {
 
 // ClusterSettingsResponse is a representation of a Elasticsearch Cluster Settings
 type ClusterSettingsResponse struct {
-	Cluster Cluster `json:"cluster"`
+	Cluster  Cluster          `json:"cluster"`
+	Snapshot SnapshotSettings `json:"snapshot"`
 }
 
 // Cluster is a representation of a Elasticsearch Cluster Settings
@@ -20,9 +21,22 @@ type Cluster struct {
 // Routing is a representation of a Elasticsearch Cluster shard routing configuration
 type Routing struct {
 	Allocation Allocation `json:"allocation"`
+	Rebalance  Rebalance  `json:"rebalance"`
 }
 
 // Allocation is a representation of a Elasticsearch Cluster shard routing allocation settings
 type Allocation struct {
+	Enabled                    string `json:"enable"`
+	ClusterConcurrentRebalance string `json:"cluster_concurrent_rebalance"`
+}
+
+// Rebalance is a representation of a Elasticsearch Cluster shard rebalancing settings
+type Rebalance struct {
 	Enabled string `json:"enable"`
 }
+
+// SnapshotSettings is a representation of a Elasticsearch Cluster snapshot settings
+type SnapshotSettings struct {
+	// MaxConcurrentOperations is only reported starting with 7.9
+	MaxConcurrentOperations string `json:"max_concurrent_operations"`
+}
@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestClusterSettingsStats(t *testing.T) {
@@ -39,6 +42,122 @@ func TestClusterSettingsStats(t *testing.T) {
 			if nsr.Cluster.Routing.Allocation.Enabled != "ALL" {
 				t.Errorf("Wrong setting for cluster routing allocation enabled")
 			}
+			if nsr.Cluster.Routing.Rebalance.Enabled != "ALL" {
+				t.Errorf("Wrong setting for cluster routing rebalance enabled")
+			}
+			if ver == "5.4.2" && nsr.Cluster.Routing.Allocation.ClusterConcurrentRebalance != "2" {
+				t.Errorf("Wrong setting for cluster concurrent rebalance")
+			}
+		}
+	}
+}
+
+func TestClusterSettingsRouting(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"transient":{"cluster.routing.allocation.enable":"none","cluster.routing.rebalance.enable":"primaries","cluster.routing.allocation.cluster_concurrent_rebalance":"-1"}}'
+	//  curl http://localhost:9200/_cluster/settings?include_defaults=true
+	tcs := map[string]string{
+		"7.6.2": `{"persistent":{},"transient":{"cluster":{"routing":{"rebalance":{"enable":"primaries"},"allocation":{"enable":"none","cluster_concurrent_rebalance":"-1"}}}},"defaults":{"cluster":{"routing":{"rebalance":{"enable":"all"},"allocation":{"node_concurrent_incoming_recoveries":"2","node_initial_primaries_recoveries":"4","same_shard":{"host":"false"},"total_shards_per_node":"-1","type":"balanced","disk":{"threshold_enabled":"true","watermark":{"low":"85%","flood_stage":"95%","high":"90%"},"include_relocations":"true","reroute_interval":"60s"},"awareness":{"attributes":[]},"balance":{"index":"0.55","threshold":"1.0","shard":"0.45"},"enable":"all","node_concurrent_outgoing_recoveries":"2","allow_rebalance":"indices_all_active","cluster_concurrent_rebalance":"2","node_concurrent_recoveries":"2"}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewClusterSettings(log.NewNopLogger(), http.DefaultClient, u)
+		ch := make(chan prometheus.Metric)
+		go func() {
+			c.Collect(ch)
+			close(ch)
+		}()
+		for range ch {
+		}
+
+		for name, tc := range map[string]struct {
+			gauge    prometheus.Gauge
+			expected float64
+		}{
+			"routing_allocation_enabled":                      {c.routingAllocationEnabled, 0},
+			"routing_rebalance_enabled":                       {c.routingRebalanceEnabled, 2},
+			"routing_allocation_cluster_concurrent_rebalance": {c.clusterConcurrentRebalance, -1},
+		} {
+			var m dto.Metric
+			if err := tc.gauge.Write(&m); err != nil {
+				t.Fatalf("Failed to write metric: %s", err)
+			}
+			if v := m.GetGauge().GetValue(); v != tc.expected {
+				t.Errorf("[%s] Wrong value for %s: got %v, expected %v", ver, name, v, tc.expected)
+			}
+		}
+	}
+}
+
+func TestClusterSettingsSnapshotConcurrency(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"persistent":{"snapshot.max_concurrent_operations":4}}'
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl -XPUT http://localhost:9200/_snapshot/test1/snapshot_1 (repeated for snapshot_2)
+	//  curl http://localhost:9200/_cluster/settings?include_defaults=true
+	//  curl http://localhost:9200/_snapshot/_status
+	tcs := map[string][]string{
+		"7.10.2": {
+			`{"persistent":{"snapshot":{"max_concurrent_operations":"4"}},"transient":{},"defaults":{"cluster":{"routing":{"rebalance":{"enable":"all"},"allocation":{"enable":"all","cluster_concurrent_rebalance":"2"}}},"snapshot":{"max_concurrent_operations":"1000","refresh_repo_uuid_on_restore":"true"}}}`,
+			`{"snapshots":[{"snapshot":"snapshot_1","repository":"test1","uuid":"iO1Hm2xfQp6QkW3Te4Jo9Q","state":"STARTED","include_global_state":true,"shards_stats":{"initializing":0,"started":1,"finalizing":0,"done":0,"failed":0,"total":1},"stats":{"incremental":{"file_count":10,"size_in_bytes":1000},"total":{"file_count":10,"size_in_bytes":1000},"start_time_in_millis":1585908000000,"time_in_millis":1000},"indices":{}},{"snapshot":"snapshot_2","repository":"test1","uuid":"tc8oVxE4Tk2WAqCHvnk0yA","state":"STARTED","include_global_state":true,"shards_stats":{"initializing":1,"started":0,"finalizing":0,"done":0,"failed":0,"total":1},"stats":{"incremental":{"file_count":0,"size_in_bytes":0},"total":{"file_count":0,"size_in_bytes":0},"start_time_in_millis":1585908001000,"time_in_millis":0},"indices":{}}]}`,
+		},
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/_cluster/settings":
+				if r.URL.Query().Get("include_defaults") != "true" {
+					t.Errorf("Cluster settings requested without defaults")
+				}
+				fmt.Fprintln(w, out[0])
+			case "/_snapshot/_status":
+				fmt.Fprintln(w, out[1])
+			default:
+				http.Error(w, "", http.StatusNotFound)
+			}
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewClusterSettings(log.NewNopLogger(), http.DefaultClient, u)
+		ch := make(chan prometheus.Metric, 20)
+		c.Collect(ch)
+		close(ch)
+
+		expected := map[string]float64{
+			"elasticsearch_cluster_snapshot_max_concurrent_operations": 4,
+			"elasticsearch_cluster_snapshot_concurrency_ratio":         0.5,
+		}
+		for metric := range ch {
+			for name, want := range expected {
+				if !strings.Contains(metric.Desc().String(), `"`+name+`"`) {
+					continue
+				}
+				var m dto.Metric
+				if err := metric.Write(&m); err != nil {
+					t.Fatalf("Failed to write metric: %s", err)
+				}
+				if v := m.GetGauge().GetValue(); v != want {
+					t.Errorf("[%s] Wrong value for %s: got %v, expected %v", ver, name, v, want)
+				}
+				delete(expected, name)
+			}
+		}
+		if len(expected) != 0 {
+			t.Errorf("[%s] Missing metrics: %v", ver, expected)
 		}
 	}
 }
@@ -0,0 +1,243 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	defaultClusterStatsLabels = []string{"cluster"}
+)
+
+type clusterStatsMetric struct {
+	Type  prometheus.ValueType
+	Desc  *prometheus.Desc
+	Value func(clusterStats clusterStatsResponse) float64
+}
+
+// ClusterStats information struct
+type ClusterStats struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	metrics          []*clusterStatsMetric
+	nodes            *prometheus.Desc
+	primaryStoreSize *prometheus.Desc
+}
+
+// NewClusterStats defines ClusterStats Prometheus metrics
+func NewClusterStats(logger log.Logger, client *http.Client, url *url.URL) *ClusterStats {
+	subsystem := "cluster"
+	constLabels := constLabelsFromURL(url)
+
+	return &ClusterStats{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "cluster_stats", "up"),
+			Help:        "Was the last scrape of the ElasticSearch cluster stats endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "cluster_stats", "total_scrapes"),
+			Help:        "Current total ElasticSearch cluster stats scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "cluster_stats", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		nodes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "nodes_total"),
+			"Number of nodes in the cluster with the role, nodes with several roles are counted for each of them",
+			append(defaultClusterStatsLabels, "role"), constLabels,
+		),
+		primaryStoreSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "primary_store_size_bytes"),
+			"Size of the primary shards of all indices in bytes",
+			defaultClusterStatsLabels, constLabels,
+		),
+		metrics: []*clusterStatsMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "indices_total"),
+					"Number of indices in the cluster",
+					defaultClusterStatsLabels, constLabels,
+				),
+				Value: func(clusterStats clusterStatsResponse) float64 {
+					return float64(clusterStats.Indices.Count)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "shards_total"),
+					"Number of assigned primary and replica shards in the cluster",
+					defaultClusterStatsLabels, constLabels,
+				),
+				Value: func(clusterStats clusterStatsResponse) float64 {
+					return float64(clusterStats.Indices.Shards.Total)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "docs_total"),
+					"Number of documents in the assigned primary and replica shards of the cluster",
+					defaultClusterStatsLabels, constLabels,
+				),
+				Value: func(clusterStats clusterStatsResponse) float64 {
+					return float64(clusterStats.Indices.Docs.Count)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "store_size_bytes"),
+					"Size of the assigned primary and replica shards of the cluster in bytes",
+					defaultClusterStatsLabels, constLabels,
+				),
+				Value: func(clusterStats clusterStatsResponse) float64 {
+					return float64(clusterStats.Indices.Store.SizeInBytes)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "memory_total_bytes"),
+					"Physical memory of all nodes in bytes",
+					defaultClusterStatsLabels, constLabels,
+				),
+				Value: func(clusterStats clusterStatsResponse) float64 {
+					return float64(clusterStats.Nodes.OS.Mem.TotalInBytes)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "jvm_heap_max_bytes"),
+					"Maximum heap of all nodes in bytes",
+					defaultClusterStatsLabels, constLabels,
+				),
+				Value: func(clusterStats clusterStatsResponse) float64 {
+					return float64(clusterStats.Nodes.JVM.Mem.HeapMaxInBytes)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, subsystem, "jvm_mem_used_bytes"),
+					"Heap used by all nodes in bytes",
+					defaultClusterStatsLabels, constLabels,
+				),
+				Value: func(clusterStats clusterStatsResponse) float64 {
+					return float64(clusterStats.Nodes.JVM.Mem.HeapUsedInBytes)
+				},
+			},
+		},
+	}
+}
+
+// Describe add ClusterStats metrics descriptions
+func (c *ClusterStats) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric.Desc
+	}
+	ch <- c.nodes
+	ch <- c.primaryStoreSize
+	ch <- c.up.Desc()
+	ch <- c.totalScrapes.Desc()
+	ch <- c.jsonParseFailures.Desc()
+}
+
+func (c *ClusterStats) fetchAndDecodeClusterStats() (clusterStatsResponse, error) {
+	var csr clusterStatsResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_cluster/stats")
+	u.RawQuery = "filter_path=cluster_name,indices.count,indices.shards.total,indices.docs.count,indices.store.size_in_bytes,nodes.count,nodes.os.mem.total_in_bytes,nodes.jvm.mem"
+	err := getAndDecodeURL(c.logger, c.client, &u, &csr, c.jsonParseFailures)
+	return csr, err
+}
+
+// fetchAndDecodePrimaryStoreStats fetches the primary store size, which the cluster stats do not report
+func (c *ClusterStats) fetchAndDecodePrimaryStoreStats() (primaryStoreStatsResponse, error) {
+	var pssr primaryStoreStatsResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_stats/store")
+	u.RawQuery = "filter_path=_all.primaries.store.size_in_bytes"
+	err := getAndDecodeURL(c.logger, c.client, &u, &pssr, c.jsonParseFailures)
+	return pssr, err
+}
+
+// Collect gets ClusterStats metric values
+func (c *ClusterStats) Collect(ch chan<- prometheus.Metric) {
+	c.totalScrapes.Inc()
+	defer func() {
+		ch <- c.up
+		ch <- c.totalScrapes
+		ch <- c.jsonParseFailures
+	}()
+
+	clusterStatsResp, err := c.fetchAndDecodeClusterStats()
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode cluster stats",
+			"err", err,
+		)
+		return
+	}
+	c.up.Set(1)
+
+	for _, metric := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(
+			metric.Desc,
+			metric.Type,
+			metric.Value(clusterStatsResp),
+			clusterStatsResp.ClusterName,
+		)
+	}
+	for role, count := range clusterStatsResp.Nodes.Count {
+		// the total is already exported by the cluster health as the number of nodes
+		if role == "total" {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.nodes,
+			prometheus.GaugeValue,
+			float64(count),
+			clusterStatsResp.ClusterName, role,
+		)
+	}
+
+	// the primary store size is skipped when its fetch fails, the cluster stats stay valid
+	primaryStoreStats, err := c.fetchAndDecodePrimaryStoreStats()
+	if err != nil {
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode primary store stats",
+			"err", err,
+		)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.primaryStoreSize,
+		prometheus.GaugeValue,
+		float64(primaryStoreStats.All.Primaries.Store.SizeInBytes),
+		clusterStatsResp.ClusterName,
+	)
+}
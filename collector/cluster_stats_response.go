@@ -0,0 +1,50 @@
+package collector
+
+// clusterStatsResponse is a representation of the cluster stats API
+type clusterStatsResponse struct {
+	ClusterName string                      `json:"cluster_name"`
+	Indices     clusterStatsIndicesResponse `json:"indices"`
+	Nodes       clusterStatsNodesResponse   `json:"nodes"`
+}
+
+// clusterStatsIndicesResponse is a representation of the index aggregates of the cluster stats
+type clusterStatsIndicesResponse struct {
+	Count  int64 `json:"count"`
+	Shards struct {
+		Total int64 `json:"total"`
+	} `json:"shards"`
+	Docs struct {
+		Count int64 `json:"count"`
+	} `json:"docs"`
+	Store struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"store"`
+}
+
+// clusterStatsNodesResponse is a representation of the node aggregates of the cluster stats
+type clusterStatsNodesResponse struct {
+	// Count holds the number of nodes in total and per role, the roles depend on the version
+	Count map[string]int64 `json:"count"`
+	OS    struct {
+		Mem struct {
+			TotalInBytes int64 `json:"total_in_bytes"`
+		} `json:"mem"`
+	} `json:"os"`
+	JVM struct {
+		Mem struct {
+			HeapUsedInBytes int64 `json:"heap_used_in_bytes"`
+			HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+		} `json:"mem"`
+	} `json:"jvm"`
+}
+
+// primaryStoreStatsResponse is a representation of the store size of the primaries of all indices
+type primaryStoreStatsResponse struct {
+	All struct {
+		Primaries struct {
+			Store struct {
+				SizeInBytes int64 `json:"size_in_bytes"`
+			} `json:"store"`
+		} `json:"primaries"`
+	} `json:"_all"`
+}
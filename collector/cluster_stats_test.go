@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestClusterStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION (and a second node)
+	//  curl -XPUT http://localhost:9200/twitter/_doc/1 -H 'Content-Type: application/json' -d '{"user":"kimchy"}'
+	//  curl 'http://localhost:9200/_cluster/stats?filter_path=cluster_name,indices.count,indices.shards.total,indices.docs.count,indices.store.size_in_bytes,nodes.count,nodes.os.mem.total_in_bytes,nodes.jvm.mem'
+	//  curl 'http://localhost:9200/_stats/store?filter_path=_all.primaries.store.size_in_bytes'
+	tcs := map[string]map[string]string{
+		"7.10.2": {
+			"/_cluster/stats": `{"cluster_name":"elasticsearch","indices":{"count":2,"shards":{"total":4},"docs":{"count":20},"store":{"size_in_bytes":41250}},"nodes":{"count":{"total":2,"coordinating_only":0,"data":2,"ingest":2,"master":2,"ml":2,"remote_cluster_client":2,"transform":2,"voting_only":0},"os":{"mem":{"total_in_bytes":16656789504}},"jvm":{"mem":{"heap_used_in_bytes":512000000,"heap_max_in_bytes":2147483648}}}}`,
+			"/_stats/store":   `{"_all":{"primaries":{"store":{"size_in_bytes":20625}}}}`,
+		},
+	}
+	for ver, out := range tcs {
+		ts, u := newFixtureServer(t, out)
+		defer ts.Close()
+		c := NewClusterStats(log.NewNopLogger(), http.DefaultClient, u)
+		csr, err := c.fetchAndDecodeClusterStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cluster stats: %s", err)
+		}
+		t.Logf("[%s] Cluster Stats Response: %+v", ver, csr)
+
+		expected := map[string]float64{
+			"elasticsearch_cluster_indices_total":      2,
+			"elasticsearch_cluster_shards_total":       4,
+			"elasticsearch_cluster_docs_total":         20,
+			"elasticsearch_cluster_store_size_bytes":   41250,
+			"elasticsearch_cluster_memory_total_bytes": 16656789504,
+			"elasticsearch_cluster_jvm_heap_max_bytes": 2147483648,
+			"elasticsearch_cluster_jvm_mem_used_bytes": 512000000,
+		}
+		for _, metric := range c.metrics {
+			var found bool
+			for name, want := range expected {
+				if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+					continue
+				}
+				found = true
+				if v := metric.Value(csr); v != want {
+					t.Errorf("[%s] Wrong value for %s: got %v, expected %v", ver, name, v, want)
+				}
+			}
+			if !found {
+				t.Errorf("[%s] Unexpected metric %s", ver, metric.Desc)
+			}
+		}
+		if csr.Nodes.Count["data"] != 2 || csr.Nodes.Count["voting_only"] != 0 {
+			t.Errorf("[%s] Wrong node counts: %v", ver, csr.Nodes.Count)
+		}
+
+		pssr, err := c.fetchAndDecodePrimaryStoreStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode primary store stats: %s", err)
+		}
+		if pssr.All.Primaries.Store.SizeInBytes != 20625 {
+			t.Errorf("[%s] Wrong primary store size: %d", ver, pssr.All.Primaries.Store.SizeInBytes)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ComponentTemplates information struct
+type ComponentTemplates struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	indexTemplateRefs *prometheus.Desc
+}
+
+// NewComponentTemplates defines ComponentTemplates Prometheus metrics
+func NewComponentTemplates(logger log.Logger, client *http.Client, url *url.URL) *ComponentTemplates {
+	constLabels := constLabelsFromURL(url)
+	return &ComponentTemplates{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "component_template", "up"),
+			Help:        "Was the last scrape of the ElasticSearch component and index templates endpoints successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "component_template", "total_scrapes"),
+			Help:        "Current total ElasticSearch component template scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "component_template", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		indexTemplateRefs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "component_template", "index_template_refs"),
+			"Number of index templates composed of the component template, 0 for unused component templates",
+			[]string{"component_template"}, constLabels,
+		),
+	}
+}
+
+// Describe add ComponentTemplates metrics descriptions
+func (c *ComponentTemplates) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.indexTemplateRefs
+	ch <- c.up.Desc()
+	ch <- c.totalScrapes.Desc()
+	ch <- c.jsonParseFailures.Desc()
+}
+
+func (c *ComponentTemplates) fetchAndDecodeComponentTemplates() (ComponentTemplatesResponse, error) {
+	var ctr ComponentTemplatesResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_component_template")
+	err := getAndDecodeURL(c.logger, c.client, &u, &ctr, c.jsonParseFailures)
+	return ctr, err
+}
+
+func (c *ComponentTemplates) fetchAndDecodeIndexTemplates() (IndexTemplatesResponse, error) {
+	var itr IndexTemplatesResponse
+
+	u := *c.url
+	u.Path = path.Join(u.Path, "/_index_template")
+	err := getAndDecodeURL(c.logger, c.client, &u, &itr, c.jsonParseFailures)
+	return itr, err
+}
+
+// Collect gets ComponentTemplates metric values
+func (c *ComponentTemplates) Collect(ch chan<- prometheus.Metric) {
+	c.totalScrapes.Inc()
+	defer func() {
+		ch <- c.up
+		ch <- c.totalScrapes
+		ch <- c.jsonParseFailures
+	}()
+
+	componentTemplatesResp, err := c.fetchAndDecodeComponentTemplates()
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode component templates",
+			"err", err,
+		)
+		return
+	}
+	indexTemplatesResp, err := c.fetchAndDecodeIndexTemplates()
+	if err != nil {
+		c.up.Set(0)
+		_ = level.Warn(c.logger).Log(
+			"msg", "failed to fetch and decode index templates",
+			"err", err,
+		)
+		return
+	}
+	c.up.Set(1)
+
+	for name, refs := range componentTemplateRefs(componentTemplatesResp, indexTemplatesResp) {
+		ch <- prometheus.MustNewConstMetric(
+			c.indexTemplateRefs,
+			prometheus.GaugeValue,
+			float64(refs),
+			name,
+		)
+	}
+}
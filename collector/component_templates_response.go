@@ -0,0 +1,42 @@
+package collector
+
+// ComponentTemplatesResponse is a representation of the component templates API
+type ComponentTemplatesResponse struct {
+	ComponentTemplates []ComponentTemplateResponse `json:"component_templates"`
+}
+
+// ComponentTemplateResponse is a representation of a single component template, its content is ignored
+type ComponentTemplateResponse struct {
+	Name string `json:"name"`
+}
+
+// IndexTemplatesResponse is a representation of the composable index templates API
+type IndexTemplatesResponse struct {
+	IndexTemplates []IndexTemplateResponse `json:"index_templates"`
+}
+
+// IndexTemplateResponse is a representation of a single composable index template
+type IndexTemplateResponse struct {
+	Name          string `json:"name"`
+	IndexTemplate struct {
+		IndexPatterns []string `json:"index_patterns"`
+		ComposedOf    []string `json:"composed_of"`
+	} `json:"index_template"`
+}
+
+// componentTemplateRefs returns for every component template the number of index templates composed of it
+func componentTemplateRefs(components ComponentTemplatesResponse, indexTemplates IndexTemplatesResponse) map[string]int {
+	refs := make(map[string]int, len(components.ComponentTemplates))
+	for _, component := range components.ComponentTemplates {
+		refs[component.Name] = 0
+	}
+	for _, indexTemplate := range indexTemplates.IndexTemplates {
+		for _, component := range indexTemplate.IndexTemplate.ComposedOf {
+			// index templates may reference missing component templates starting with 8.7
+			if _, ok := refs[component]; ok {
+				refs[component]++
+			}
+		}
+	}
+	return refs
+}
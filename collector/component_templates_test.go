@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestComponentTemplates(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_component_template/logs-mappings -H 'Content-Type: application/json' -d '{"template":{"mappings":{"properties":{"@timestamp":{"type":"date"}}}}}'
+	//  curl -XPUT http://localhost:9200/_component_template/logs-settings -H 'Content-Type: application/json' -d '{"template":{"settings":{"number_of_shards":1}}}'
+	//  curl -XPUT http://localhost:9200/_component_template/unused -H 'Content-Type: application/json' -d '{"template":{"settings":{"number_of_replicas":0}}}'
+	//  curl -XPUT http://localhost:9200/_index_template/logs -H 'Content-Type: application/json' -d '{"index_patterns":["logs-*"],"composed_of":["logs-mappings","logs-settings"]}'
+	//  curl -XPUT http://localhost:9200/_index_template/audit -H 'Content-Type: application/json' -d '{"index_patterns":["audit-*"],"composed_of":["logs-mappings"]}'
+	//  curl http://localhost:9200/_component_template
+	//  curl http://localhost:9200/_index_template
+	tcs := map[string]map[string]string{
+		"7.10.2": {
+			"/_component_template": `{"component_templates":[{"name":"logs-mappings","component_template":{"template":{"mappings":{"properties":{"@timestamp":{"type":"date"}}}}}},{"name":"logs-settings","component_template":{"template":{"settings":{"index":{"number_of_shards":"1"}}}}},{"name":"unused","component_template":{"template":{"settings":{"index":{"number_of_replicas":"0"}}}}}]}`,
+			"/_index_template":     `{"index_templates":[{"name":"logs","index_template":{"index_patterns":["logs-*"],"composed_of":["logs-mappings","logs-settings"]}},{"name":"audit","index_template":{"index_patterns":["audit-*"],"composed_of":["logs-mappings"]}},{"name":"metrics","index_template":{"index_patterns":["metrics-*"],"composed_of":[]}}]}`,
+		},
+	}
+	for ver, out := range tcs {
+		ts, u := newFixtureServer(t, out)
+		defer ts.Close()
+		c := NewComponentTemplates(log.NewNopLogger(), http.DefaultClient, u)
+		ctr, err := c.fetchAndDecodeComponentTemplates()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode component templates: %s", err)
+		}
+		itr, err := c.fetchAndDecodeIndexTemplates()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode index templates: %s", err)
+		}
+		t.Logf("[%s] Component Templates Response: %+v, Index Templates Response: %+v", ver, ctr, itr)
+
+		refs := componentTemplateRefs(ctr, itr)
+		expected := map[string]int{"logs-mappings": 2, "logs-settings": 1, "unused": 0}
+		if len(refs) != len(expected) {
+			t.Fatalf("[%s] Wrong number of component templates: %v", ver, refs)
+		}
+		for name, want := range expected {
+			if refs[name] != want {
+				t.Errorf("[%s] Wrong number of references to %s: got %d, expected %d", ver, name, refs[name], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type dataStreamMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(dataStream DataStreamDataResponse, dataStreamStats DataStreamStatsDataResponse) float64
+	Labels func(dataStream DataStreamDataResponse) []string
+}
+
+var (
+	dataStreamStatuses = map[string]float64{
+		"green":  2,
+		"yellow": 1,
+		"red":    0,
+	}
+	// dataStreamStatusUnknown is the value of statuses missing from dataStreamStatuses
+	dataStreamStatusUnknown = -1.0
+
+	defaultDataStreamLabels      = []string{"name", "template"}
+	defaultDataStreamLabelValues = func(dataStream DataStreamDataResponse) []string {
+		return []string{dataStream.Name, dataStream.Template}
+	}
+)
+
+// DataStream information struct
+type DataStream struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	dataStreamMetrics []*dataStreamMetric
+}
+
+// NewDataStream defines DataStream Prometheus metrics
+func NewDataStream(logger log.Logger, client *http.Client, url *url.URL) *DataStream {
+	constLabels := constLabelsFromURL(url)
+	return &DataStream{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "data_stream", "up"),
+			Help:        "Was the last scrape of the ElasticSearch data stream endpoints successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "data_stream", "total_scrapes"),
+			Help:        "Current total ElasticSearch data stream scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "data_stream", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		dataStreamMetrics: []*dataStreamMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "data_stream", "backing_indices"),
+					"Number of backing indices of the data stream",
+					defaultDataStreamLabels, constLabels,
+				),
+				Value: func(dataStream DataStreamDataResponse, dataStreamStats DataStreamStatsDataResponse) float64 {
+					return float64(len(dataStream.Indices))
+				},
+				Labels: defaultDataStreamLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "data_stream", "store_size_bytes"),
+					"Store size of all backing indices of the data stream in bytes",
+					defaultDataStreamLabels, constLabels,
+				),
+				Value: func(dataStream DataStreamDataResponse, dataStreamStats DataStreamStatsDataResponse) float64 {
+					return float64(dataStreamStats.StoreSizeBytes)
+				},
+				Labels: defaultDataStreamLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "data_stream", "status"),
+					"Health status of the data stream (green=2, yellow=1, red=0, unknown=-1)",
+					defaultDataStreamLabels, constLabels,
+				),
+				Value: func(dataStream DataStreamDataResponse, dataStreamStats DataStreamStatsDataResponse) float64 {
+					return stateValue(dataStreamStatuses, strings.ToLower(dataStream.Status), dataStreamStatusUnknown)
+				},
+				Labels: defaultDataStreamLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "data_stream", "generation"),
+					"Current generation of the data stream, incremented on every rollover",
+					defaultDataStreamLabels, constLabels,
+				),
+				Value: func(dataStream DataStreamDataResponse, dataStreamStats DataStreamStatsDataResponse) float64 {
+					return float64(dataStream.Generation)
+				},
+				Labels: defaultDataStreamLabelValues,
+			},
+		},
+	}
+}
+
+// Describe add DataStream metrics descriptions
+func (ds *DataStream) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range ds.dataStreamMetrics {
+		ch <- metric.Desc
+	}
+	ch <- ds.up.Desc()
+	ch <- ds.totalScrapes.Desc()
+	ch <- ds.jsonParseFailures.Desc()
+}
+
+func (ds *DataStream) fetchAndDecodeDataStreams() (DataStreamResponse, error) {
+	var dsr DataStreamResponse
+
+	u := *ds.url
+	u.Path = path.Join(u.Path, "/_data_stream")
+	u.RawQuery = "expand_wildcards=all"
+	err := getAndDecodeURL(ds.logger, ds.client, &u, &dsr, ds.jsonParseFailures)
+	return dsr, err
+}
+
+func (ds *DataStream) fetchAndDecodeDataStreamStats() (DataStreamStatsResponse, error) {
+	var dssr DataStreamStatsResponse
+
+	u := *ds.url
+	u.Path = path.Join(u.Path, "/_data_stream/_stats")
+	u.RawQuery = "expand_wildcards=all"
+	err := getAndDecodeURL(ds.logger, ds.client, &u, &dssr, ds.jsonParseFailures)
+	return dssr, err
+}
+
+// Collect gets DataStream metric values
+func (ds *DataStream) Collect(ch chan<- prometheus.Metric) {
+	ds.totalScrapes.Inc()
+	defer func() {
+		ch <- ds.up
+		ch <- ds.totalScrapes
+		ch <- ds.jsonParseFailures
+	}()
+
+	dataStreamResp, err := ds.fetchAndDecodeDataStreams()
+	if err != nil {
+		ds.up.Set(0)
+		_ = level.Warn(ds.logger).Log(
+			"msg", "failed to fetch and decode data streams",
+			"err", err,
+		)
+		return
+	}
+
+	dataStreamStatsResp, err := ds.fetchAndDecodeDataStreamStats()
+	if err != nil {
+		ds.up.Set(0)
+		_ = level.Warn(ds.logger).Log(
+			"msg", "failed to fetch and decode data stream stats",
+			"err", err,
+		)
+		return
+	}
+	ds.up.Set(1)
+
+	dataStreamStats := make(map[string]DataStreamStatsDataResponse, len(dataStreamStatsResp.DataStreams))
+	for _, stats := range dataStreamStatsResp.DataStreams {
+		dataStreamStats[stats.DataStream] = stats
+	}
+
+	for _, dataStream := range dataStreamResp.DataStreams {
+		for _, metric := range ds.dataStreamMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(dataStream, dataStreamStats[dataStream.Name]),
+				metric.Labels(dataStream)...,
+			)
+		}
+	}
+}
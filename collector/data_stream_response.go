@@ -0,0 +1,37 @@
+package collector
+
+// DataStreamResponse is a representation of the data streams
+type DataStreamResponse struct {
+	DataStreams []DataStreamDataResponse `json:"data_streams"`
+}
+
+// DataStreamDataResponse is a representation of a single data stream
+type DataStreamDataResponse struct {
+	Name       string                    `json:"name"`
+	Generation int64                     `json:"generation"`
+	Status     string                    `json:"status"`
+	Template   string                    `json:"template"`
+	Indices    []DataStreamIndexResponse `json:"indices"`
+}
+
+// DataStreamIndexResponse is a representation of a data stream backing index
+type DataStreamIndexResponse struct {
+	IndexName string `json:"index_name"`
+	IndexUUID string `json:"index_uuid"`
+}
+
+// DataStreamStatsResponse is a representation of the data stream stats
+type DataStreamStatsResponse struct {
+	DataStreamCount     int64                         `json:"data_stream_count"`
+	BackingIndices      int64                         `json:"backing_indices"`
+	TotalStoreSizeBytes int64                         `json:"total_store_size_bytes"`
+	DataStreams         []DataStreamStatsDataResponse `json:"data_streams"`
+}
+
+// DataStreamStatsDataResponse is a representation of the stats of a single data stream
+type DataStreamStatsDataResponse struct {
+	DataStream       string `json:"data_stream"`
+	BackingIndices   int64  `json:"backing_indices"`
+	StoreSizeBytes   int64  `json:"store_size_bytes"`
+	MaximumTimestamp int64  `json:"maximum_timestamp"`
+}
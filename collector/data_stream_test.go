@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestDataStream(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_index_template/logs-template -H 'Content-Type: application/json' -d '{"index_patterns":["logs-*"],"data_stream":{}}'
+	//  curl -XPOST http://localhost:9200/logs-app/_doc -H 'Content-Type: application/json' -d '{"@timestamp":"2020-09-01T00:00:00Z"}'
+	//  curl -XPOST http://localhost:9200/logs-app/_rollover
+	//  curl http://localhost:9200/_data_stream?expand_wildcards=all
+	//  curl http://localhost:9200/_data_stream/_stats?expand_wildcards=all
+	tcs := map[string][]string{
+		"7.9.0": {
+			`{"data_streams":[{"name":"logs-app","timestamp_field":{"name":"@timestamp"},"indices":[{"index_name":".ds-logs-app-000001","index_uuid":"3dUT1Ce1TGWWbvKvCQk9oQ"},{"index_name":".ds-logs-app-000002","index_uuid":"7DVUx1gBTXWXQbmr3ZkxlA"}],"generation":2,"status":"YELLOW","template":"logs-template"}]}`,
+			`{"_shards":{"total":4,"successful":2,"failed":0},"data_stream_count":1,"backing_indices":2,"total_store_size_bytes":8830,"data_streams":[{"data_stream":"logs-app","backing_indices":2,"store_size_bytes":8830,"maximum_timestamp":1598918400000}]}`,
+		},
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/_data_stream/_stats" {
+				fmt.Fprintln(w, out[1])
+				return
+			}
+			fmt.Fprintln(w, out[0])
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		ds := NewDataStream(log.NewNopLogger(), http.DefaultClient, u)
+		dsr, err := ds.fetchAndDecodeDataStreams()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode data streams: %s", err)
+		}
+		t.Logf("[%s] Data Stream Response: %+v", ver, dsr)
+		if len(dsr.DataStreams) != 1 {
+			t.Fatalf("Wrong number of data streams")
+		}
+		dataStream := dsr.DataStreams[0]
+		if dataStream.Name != "logs-app" || dataStream.Template != "logs-template" {
+			t.Errorf("Wrong data stream name or template")
+		}
+		if len(dataStream.Indices) != 2 {
+			t.Errorf("Wrong number of backing indices")
+		}
+		if dataStream.Generation != 2 {
+			t.Errorf("Wrong data stream generation")
+		}
+		if dataStream.Status != "YELLOW" {
+			t.Errorf("Wrong data stream status")
+		}
+		for _, status := range []struct {
+			status string
+			want   float64
+		}{{"YELLOW", 1}, {"UNKNOWN", dataStreamStatusUnknown}} {
+			dataStream.Status = status.status
+			if v := ds.dataStreamMetrics[2].Value(dataStream, DataStreamStatsDataResponse{}); v != status.want {
+				t.Errorf("Wrong value for data stream status %s: %v", status.status, v)
+			}
+		}
+
+		dssr, err := ds.fetchAndDecodeDataStreamStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode data stream stats: %s", err)
+		}
+		t.Logf("[%s] Data Stream Stats Response: %+v", ver, dssr)
+		if len(dssr.DataStreams) != 1 {
+			t.Fatalf("Wrong number of data stream stats")
+		}
+		if dssr.DataStreams[0].StoreSizeBytes != 8830 {
+			t.Errorf("Wrong data stream store size")
+		}
+	}
+}
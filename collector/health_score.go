@@ -0,0 +1,237 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultHealthScoreFormula maps the cluster status alone to a score (green=100, yellow=50, red=0)
+const DefaultHealthScoreFormula = "status_green*100 + status_yellow*50"
+
+// healthScoreVariables lists the variables a health score formula may reference
+var healthScoreVariables = map[string]bool{
+	"status_green":                     true,
+	"status_yellow":                    true,
+	"status_red":                       true,
+	"number_of_nodes":                  true,
+	"number_of_data_nodes":             true,
+	"active_primary_shards":            true,
+	"active_shards":                    true,
+	"relocating_shards":                true,
+	"initializing_shards":              true,
+	"unassigned_shards":                true,
+	"delayed_unassigned_shards":        true,
+	"pending_tasks":                    true,
+	"in_flight_fetch":                  true,
+	"task_max_waiting_in_queue_millis": true,
+	"heap_used_percent":                true,
+}
+
+// HealthScoreFormula is a parsed arithmetic expression used to compute the cluster health score.
+// It supports numbers, the variables in healthScoreVariables, + - * /, parentheses and the
+// functions max(a, b, ...) and min(a, b, ...).
+type HealthScoreFormula struct {
+	expr      string
+	root      healthScoreNode
+	variables map[string]bool
+}
+
+type healthScoreNode func(vars map[string]float64) float64
+
+// ParseHealthScoreFormula parses a health score formula expression
+func ParseHealthScoreFormula(expr string) (*HealthScoreFormula, error) {
+	p := &healthScoreParser{input: expr, variables: map[string]bool{}}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in health score formula", p.tokens[p.pos])
+	}
+	return &HealthScoreFormula{expr: expr, root: root, variables: p.variables}, nil
+}
+
+// Uses returns true if the formula references the given variable
+func (f *HealthScoreFormula) Uses(variable string) bool {
+	return f.variables[variable]
+}
+
+// Eval computes the formula with the given variable values, missing variables count as 0
+func (f *HealthScoreFormula) Eval(vars map[string]float64) float64 {
+	return f.root(vars)
+}
+
+// String returns the original formula expression
+func (f *HealthScoreFormula) String() string {
+	return f.expr
+}
+
+type healthScoreParser struct {
+	input     string
+	tokens    []string
+	pos       int
+	variables map[string]bool
+}
+
+func (p *healthScoreParser) tokenize() error {
+	runes := []rune(p.input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/(),", r):
+			p.tokens = append(p.tokens, string(r))
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			p.tokens = append(p.tokens, string(runes[start:i]))
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			p.tokens = append(p.tokens, string(runes[start:i]))
+		default:
+			return fmt.Errorf("invalid character %q in health score formula", r)
+		}
+	}
+	if len(p.tokens) == 0 {
+		return fmt.Errorf("empty health score formula")
+	}
+	return nil
+}
+
+func (p *healthScoreParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *healthScoreParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// parseExpr parses additions and subtractions
+func (p *healthScoreParser) parseExpr() (healthScoreNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "+" || p.peek() == "-" {
+		op := p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(vars map[string]float64) float64 { return l(vars) + right(vars) }
+		} else {
+			left = func(vars map[string]float64) float64 { return l(vars) - right(vars) }
+		}
+	}
+	return left, nil
+}
+
+// parseTerm parses multiplications and divisions
+func (p *healthScoreParser) parseTerm() (healthScoreNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "*" || p.peek() == "/" {
+		op := p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "*" {
+			left = func(vars map[string]float64) float64 { return l(vars) * right(vars) }
+		} else {
+			left = func(vars map[string]float64) float64 { return l(vars) / right(vars) }
+		}
+	}
+	return left, nil
+}
+
+// parseFactor parses numbers, variables, function calls, parentheses and unary minus
+func (p *healthScoreParser) parseFactor() (healthScoreNode, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return nil, fmt.Errorf("unexpected end of health score formula")
+	case t == "-":
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]float64) float64 { return -operand(vars) }, nil
+	case t == "(":
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis in health score formula")
+		}
+		return inner, nil
+	case unicode.IsDigit(rune(t[0])) || t[0] == '.':
+		v, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q in health score formula", t)
+		}
+		return func(map[string]float64) float64 { return v }, nil
+	case t == "max" || t == "min":
+		return p.parseFunction(t)
+	case healthScoreVariables[t]:
+		p.variables[t] = true
+		return func(vars map[string]float64) float64 { return vars[t] }, nil
+	}
+	return nil, fmt.Errorf("unknown identifier %q in health score formula", t)
+}
+
+func (p *healthScoreParser) parseFunction(name string) (healthScoreNode, error) {
+	if p.next() != "(" {
+		return nil, fmt.Errorf("expected ( after %s in health score formula", name)
+	}
+	var args []healthScoreNode
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+	if p.next() != ")" {
+		return nil, fmt.Errorf("missing closing parenthesis after %s arguments in health score formula", name)
+	}
+	pick := math.Max
+	if name == "min" {
+		pick = math.Min
+	}
+	return func(vars map[string]float64) float64 {
+		v := args[0](vars)
+		for _, arg := range args[1:] {
+			v = pick(v, arg(vars))
+		}
+		return v
+	}, nil
+}
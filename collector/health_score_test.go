@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestHealthScoreFormula(t *testing.T) {
+	vars := map[string]float64{
+		"status_yellow":     1,
+		"unassigned_shards": 3,
+		"pending_tasks":     2,
+		"heap_used_percent": 80,
+	}
+	tcs := map[string]float64{
+		DefaultHealthScoreFormula: 50,
+		"status_green*100 + status_yellow*50 - unassigned_shards*5 - pending_tasks - max(0, heap_used_percent - 75)*2": 23,
+		"-(1 + 2) * 3":  -9,
+		"min(4, 2, 8)":  2,
+		"10 / 4":        2.5,
+		"status_red":    0,
+		"max(0, -.5)":   0,
+		"((1))+(2*(3))": 7,
+	}
+	for expr, expected := range tcs {
+		f, err := ParseHealthScoreFormula(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse formula %q: %s", expr, err)
+		}
+		if v := f.Eval(vars); v != expected {
+			t.Errorf("Wrong value for formula %q: got %v, expected %v", expr, v, expected)
+		}
+	}
+
+	for _, expr := range []string{"", "status_green +", "unknown_var", "max(1, 2", "(1", "1 2", "status_green % 2"} {
+		if _, err := ParseHealthScoreFormula(expr); err == nil {
+			t.Errorf("Expected error for formula %q", expr)
+		}
+	}
+
+	f, _ := ParseHealthScoreFormula(DefaultHealthScoreFormula)
+	if f.Uses("heap_used_percent") {
+		t.Errorf("Default formula should not use heap_used_percent")
+	}
+}
+
+func TestClusterHealthScoreHeapUsedPercent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"cluster_name":"elasticsearch","nodes":{"a":{"name":"a","jvm":{"mem":{"heap_used_in_bytes":60,"heap_max_in_bytes":100}}},"b":{"name":"b","jvm":{"mem":{"heap_used_in_bytes":90,"heap_max_in_bytes":100}}}}}`)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	f, err := ParseHealthScoreFormula("100 - heap_used_percent")
+	if err != nil {
+		t.Fatalf("Failed to parse formula: %s", err)
+	}
+	c := NewClusterHealth(log.NewNopLogger(), http.DefaultClient, u, f, 0, 0, false)
+	maxHeapUsedPercent, avgHeapUsedPercent, err := c.fetchHeapUsedPercent()
+	if err != nil {
+		t.Fatalf("Failed to fetch heap used percent: %s", err)
+	}
+	if avgHeapUsedPercent != 75 {
+		t.Errorf("Wrong avg heap used percent: %v", avgHeapUsedPercent)
+	}
+	vars := c.healthScoreVariables(clusterHealthResponse{Status: "green"}, maxHeapUsedPercent)
+	if vars["heap_used_percent"] != 90 {
+		t.Errorf("Wrong max heap used percent: %v", vars["heap_used_percent"])
+	}
+	if vars["status_green"] != 1 || vars["status_yellow"] != 0 {
+		t.Errorf("Wrong status variables")
+	}
+	if v := f.Eval(vars); v != 10 {
+		t.Errorf("Wrong health score: %v", v)
+	}
+}
@@ -0,0 +1,150 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// hotThreadsNodeHeader matches the header of the section of each node, starting with ::: {name}{id}
+	hotThreadsNodeHeader = regexp.MustCompile(`^:::\s*\{([^}]*)\}\{([^}]*)\}`)
+	// hotThreadsThreadHeader matches the header of each hot thread, like
+	// 15.2% (76.1ms out of 500ms) cpu usage by thread 'elasticsearch[node-0][search][T#3]'
+	hotThreadsThreadHeader = regexp.MustCompile(`^\s*[0-9.]+% \(.*\) \w+ usage by thread `)
+)
+
+// hotThreadsNode is the number of hot threads reported for a node
+type hotThreadsNode struct {
+	Name    string
+	ID      string
+	Threads int
+}
+
+// parseHotThreads counts the hot threads of each node in the text response of the hot threads API
+func parseHotThreads(r io.Reader) ([]hotThreadsNode, error) {
+	var nodes []hotThreadsNode
+	scanner := bufio.NewScanner(r)
+	// stack traces may contain very long lines
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := hotThreadsNodeHeader.FindStringSubmatch(line); m != nil {
+			nodes = append(nodes, hotThreadsNode{Name: m[1], ID: m[2]})
+			continue
+		}
+		if len(nodes) > 0 && hotThreadsThreadHeader.MatchString(line) {
+			nodes[len(nodes)-1].Threads++
+		}
+	}
+	return nodes, scanner.Err()
+}
+
+// HotThreads information struct
+type HotThreads struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up           prometheus.Gauge
+	totalScrapes prometheus.Counter
+
+	count *prometheus.Desc
+}
+
+// NewHotThreads defines HotThreads Prometheus metrics
+func NewHotThreads(logger log.Logger, client *http.Client, url *url.URL) *HotThreads {
+	constLabels := constLabelsFromURL(url)
+	return &HotThreads{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "hot_threads", "up"),
+			Help:        "Was the last scrape of the ElasticSearch hot threads endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "hot_threads", "total_scrapes"),
+			Help:        "Current total ElasticSearch hot threads scrapes.",
+			ConstLabels: constLabels,
+		}),
+		count: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "hot_threads_count"),
+			"Number of threads reported as hot by the node, at most 3",
+			[]string{"node_id", "name"}, constLabels,
+		),
+	}
+}
+
+// Describe add HotThreads metrics descriptions
+func (h *HotThreads) Describe(ch chan<- *prometheus.Desc) {
+	ch <- h.count
+	ch <- h.up.Desc()
+	ch <- h.totalScrapes.Desc()
+}
+
+func (h *HotThreads) fetchAndParseHotThreads() ([]hotThreadsNode, error) {
+	u := *h.url
+	u.Path = path.Join(u.Path, "/_nodes/hot_threads")
+	u.RawQuery = "type=cpu&threads=3"
+	res, err := h.client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get from %s://%s:%s%s: %s",
+			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
+	}
+
+	defer func() {
+		err = res.Body.Close()
+		if err != nil {
+			_ = level.Warn(h.logger).Log(
+				"msg", "failed to close http.Client",
+				"err", err,
+			)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
+	}
+
+	return parseHotThreads(res.Body)
+}
+
+// Collect gets HotThreads metric values
+func (h *HotThreads) Collect(ch chan<- prometheus.Metric) {
+	h.totalScrapes.Inc()
+	defer func() {
+		ch <- h.up
+		ch <- h.totalScrapes
+	}()
+
+	nodes, err := h.fetchAndParseHotThreads()
+	if err != nil {
+		h.up.Set(0)
+		_ = level.Warn(h.logger).Log(
+			"msg", "failed to fetch and parse hot threads",
+			"err", err,
+		)
+		return
+	}
+	h.up.Set(1)
+
+	for _, node := range nodes {
+		ch <- prometheus.MustNewConstMetric(
+			h.count,
+			prometheus.GaugeValue,
+			float64(node.Threads),
+			node.ID, node.Name,
+		)
+	}
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestHotThreads(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION (two nodes)
+	//  curl "http://localhost:9200/_nodes/hot_threads?type=cpu&threads=3"
+	tcs := map[string]string{
+		"7.6.2": `::: {node-0}{9_P7yui4SQOkzGhTZCyjxQ}{hoXMLZB0RWKfR9UPPUCxXX}{127.0.0.1}{127.0.0.1:9300}{dilm}{ml.machine_memory=17179869184, xpack.installed=true, ml.max_open_jobs=20}
+   Hot threads at 2020-04-01T10:00:00.000Z, interval=500ms, busiestThreads=3, ignoreIdleThreads=true:
+   
+   87.4% (437.1ms out of 500ms) cpu usage by thread 'elasticsearch[node-0][search][T#3]'
+     10/10 snapshots sharing following 25 elements
+       app//org.apache.lucene.search.BooleanScorer.scoreWindow(BooleanScorer.java:319)
+       app//org.apache.lucene.search.BooleanScorer.score(BooleanScorer.java:370)
+       java.base@13.0.2/java.lang.Thread.run(Thread.java:830)
+   
+   42.1% (210.5ms out of 500ms) cpu usage by thread 'elasticsearch[node-0][write][T#1]'
+     3/10 snapshots sharing following 18 elements
+       app//org.elasticsearch.index.engine.InternalEngine.index(InternalEngine.java:896)
+       java.base@13.0.2/java.lang.Thread.run(Thread.java:830)
+     unique snapshot
+       app//org.elasticsearch.index.shard.IndexShard.applyIndexOperation(IndexShard.java:769)
+   
+::: {node-1}{Jx0Vt0hTR0iVbVGRx1oBCA}{fIbSuP3KQtm5Kx_MUvbJyg}{127.0.0.2}{127.0.0.2:9300}{dilm}{ml.machine_memory=17179869184, xpack.installed=true, ml.max_open_jobs=20}
+   Hot threads at 2020-04-01T10:00:00.000Z, interval=500ms, busiestThreads=3, ignoreIdleThreads=true:
+   
+`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("type") != "cpu" || r.URL.Query().Get("threads") != "3" {
+				t.Errorf("Wrong hot threads query %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		h := NewHotThreads(log.NewNopLogger(), http.DefaultClient, u)
+		nodes, err := h.fetchAndParseHotThreads()
+		if err != nil {
+			t.Fatalf("Failed to fetch or parse hot threads: %s", err)
+		}
+		t.Logf("[%s] Hot Threads: %+v", ver, nodes)
+		expected := []hotThreadsNode{
+			{Name: "node-0", ID: "9_P7yui4SQOkzGhTZCyjxQ", Threads: 2},
+			{Name: "node-1", ID: "Jx0Vt0hTR0iVbVGRx1oBCA", Threads: 0},
+		}
+		if len(nodes) != len(expected) {
+			t.Fatalf("Wrong number of nodes: %d", len(nodes))
+		}
+		for i, node := range nodes {
+			if node != expected[i] {
+				t.Errorf("Wrong hot threads for node %d: got %+v, expected %+v", i, node, expected[i])
+			}
+		}
+	}
+}
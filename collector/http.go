@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// httpStatusError is returned by getAndDecodeURL when Elasticsearch answers
+// with a status other than 200 OK
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP Request failed with code %d", e.StatusCode)
+}
+
+// getAndDecodeURL gets u and decodes the JSON response body into data,
+// counting decoding errors in jsonParseFailures
+func getAndDecodeURL(logger log.Logger, client *http.Client, u *url.URL, data interface{}, jsonParseFailures prometheus.Counter) error {
+	res, err := client.Get(u.String())
+	if err != nil {
+		return fmt.Errorf("failed to get from %s://%s:%s%s: %s",
+			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
+	}
+
+	defer func() {
+		err = res.Body.Close()
+		if err != nil {
+			_ = level.Warn(logger).Log(
+				"msg", "failed to close http.Client",
+				"err", err,
+			)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return &httpStatusError{StatusCode: res.StatusCode}
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(data); err != nil {
+		jsonParseFailures.Inc()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// newFixtureServer serves the JSON fixtures in out by URL path and answers
+// 404 for any other path, the caller has to close the server
+func newFixtureServer(t *testing.T, out map[string]string) (*httptest.Server, *url.URL) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := out[r.URL.Path]
+		if !ok {
+			http.Error(w, "", http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, body)
+	}))
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		ts.Close()
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	return ts, u
+}
+
+func TestGetAndDecodeURL(t *testing.T) {
+	ts, u := newFixtureServer(t, map[string]string{
+		"/ok":      `{"name":"node-0"}`,
+		"/invalid": `{"name":`,
+	})
+	defer ts.Close()
+	jsonParseFailures := prometheus.NewCounter(prometheus.CounterOpts{Name: "json_parse_failures"})
+
+	var data struct {
+		Name string `json:"name"`
+	}
+	u.Path = "/ok"
+	if err := getAndDecodeURL(log.NewNopLogger(), http.DefaultClient, u, &data, jsonParseFailures); err != nil {
+		t.Fatalf("Failed to fetch or decode %s: %s", u.Path, err)
+	}
+	if data.Name != "node-0" {
+		t.Errorf("Wrong name: %q", data.Name)
+	}
+
+	u.Path = "/missing"
+	err := getAndDecodeURL(log.NewNopLogger(), http.DefaultClient, u, &data, jsonParseFailures)
+	if statusErr, ok := err.(*httpStatusError); !ok || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a 404 status error, got %v", err)
+	}
+
+	u.Path = "/invalid"
+	if err := getAndDecodeURL(log.NewNopLogger(), http.DefaultClient, u, &data, jsonParseFailures); err == nil {
+		t.Errorf("Expected a decoding error")
+	}
+	m := &dto.Metric{}
+	if err := jsonParseFailures.Write(m); err != nil {
+		t.Fatalf("Failed to write metric: %s", err)
+	}
+	if m.GetCounter().GetValue() != 1 {
+		t.Errorf("Wrong number of JSON parse failures: %v", m.GetCounter().GetValue())
+	}
+}
@@ -0,0 +1,251 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ilmPhaseAgeBuckets are 1h, 1d, 1w and 30d
+	ilmPhaseAgeBuckets = []float64{3600, 86400, 604800, 2592000}
+
+	defaultILMPhaseLabels = []string{"policy", "phase"}
+	defaultILMStepLabels  = []string{"policy", "phase", "action", "step"}
+)
+
+// ilmPhaseKey identifies the policy and phase an index is aggregated under
+type ilmPhaseKey struct {
+	policy string
+	phase  string
+}
+
+// ilmStepKey identifies the policy, phase, action and step an index is currently executing
+type ilmStepKey struct {
+	policy string
+	phase  string
+	action string
+	step   string
+}
+
+// ilmPhaseAgeHistogram holds the data of a constant histogram
+type ilmPhaseAgeHistogram struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+// ILM information struct
+type ILM struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	phaseAge            *prometheus.Desc
+	stepIndexCount      *prometheus.Desc
+	orphanedIndexCount  *prometheus.Desc
+	unmanagedIndexCount *prometheus.Desc
+}
+
+// NewILM defines ILM Prometheus metrics
+func NewILM(logger log.Logger, client *http.Client, url *url.URL) *ILM {
+	constLabels := constLabelsFromURL(url)
+	return &ILM{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "ilm", "up"),
+			Help:        "Was the last scrape of the ElasticSearch ILM explain endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ilm", "total_scrapes"),
+			Help:        "Current total ElasticSearch ILM scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ilm", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		phaseAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ilm", "phase_age_seconds"),
+			"Time managed indices have spent in their current ILM phase in seconds",
+			defaultILMPhaseLabels, constLabels,
+		),
+		stepIndexCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ilm", "action_index_count"),
+			"Number of managed indices currently executing the ILM step",
+			defaultILMStepLabels, constLabels,
+		),
+		orphanedIndexCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ilm", "orphaned_policy_index_count"),
+			"Number of indices set to an ILM policy which does not exist",
+			nil, constLabels,
+		),
+		unmanagedIndexCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ilm", "unmanaged_index_count"),
+			"Number of indices without an ILM policy, excluding system indices",
+			nil, constLabels,
+		),
+	}
+}
+
+// Describe add ILM metrics descriptions
+func (i *ILM) Describe(ch chan<- *prometheus.Desc) {
+	ch <- i.phaseAge
+	ch <- i.stepIndexCount
+	ch <- i.orphanedIndexCount
+	ch <- i.unmanagedIndexCount
+	ch <- i.up.Desc()
+	ch <- i.totalScrapes.Desc()
+	ch <- i.jsonParseFailures.Desc()
+}
+
+func (i *ILM) fetchAndDecodeILMExplain() (ILMExplainResponse, error) {
+	var ier ILMExplainResponse
+
+	u := *i.url
+	u.Path = path.Join(u.Path, "/_all/_ilm/explain")
+	u.RawQuery = "only_managed=true"
+	err := getAndDecodeURL(i.logger, i.client, &u, &ier, i.jsonParseFailures)
+	return ier, err
+}
+
+func (i *ILM) fetchAndDecodeLifecycleSettings() (IndicesSettingsResponse, error) {
+	var isr IndicesSettingsResponse
+
+	u := *i.url
+	u.Path = path.Join(u.Path, "/_all/_settings/index.lifecycle.name")
+	err := getAndDecodeURL(i.logger, i.client, &u, &isr, i.jsonParseFailures)
+	return isr, err
+}
+
+func (i *ILM) fetchAndDecodeILMPolicies() (ILMPoliciesResponse, error) {
+	var ipr ILMPoliciesResponse
+
+	u := *i.url
+	u.Path = path.Join(u.Path, "/_ilm/policy")
+	err := getAndDecodeURL(i.logger, i.client, &u, &ipr, i.jsonParseFailures)
+	return ipr, err
+}
+
+// ilmPhaseAgeHistograms aggregates the time spent in the current phase of each managed index by policy and phase
+func ilmPhaseAgeHistograms(indices map[string]ILMExplainIndexResponse, now time.Time) map[ilmPhaseKey]*ilmPhaseAgeHistogram {
+	histograms := make(map[ilmPhaseKey]*ilmPhaseAgeHistogram)
+	for _, index := range indices {
+		if !index.Managed || index.Phase == "" {
+			continue
+		}
+		key := ilmPhaseKey{policy: index.Policy, phase: index.Phase}
+		histogram, ok := histograms[key]
+		if !ok {
+			histogram = &ilmPhaseAgeHistogram{buckets: make(map[float64]uint64, len(ilmPhaseAgeBuckets))}
+			for _, bucket := range ilmPhaseAgeBuckets {
+				histogram.buckets[bucket] = 0
+			}
+			histograms[key] = histogram
+		}
+
+		age := now.Sub(time.Unix(0, index.PhaseTimeMillis*int64(time.Millisecond))).Seconds()
+		histogram.count++
+		histogram.sum += age
+		for _, bucket := range ilmPhaseAgeBuckets {
+			if age <= bucket {
+				histogram.buckets[bucket]++
+			}
+		}
+	}
+	return histograms
+}
+
+// ilmStepIndexCounts counts the managed indices by the policy, phase, action and step they are executing
+func ilmStepIndexCounts(indices map[string]ILMExplainIndexResponse) map[ilmStepKey]int {
+	counts := make(map[ilmStepKey]int)
+	for _, index := range indices {
+		if !index.Managed || index.Phase == "" {
+			continue
+		}
+		counts[ilmStepKey{policy: index.Policy, phase: index.Phase, action: index.Action, step: index.Step}]++
+	}
+	return counts
+}
+
+// Collect gets ILM metric values
+func (i *ILM) Collect(ch chan<- prometheus.Metric) {
+	i.totalScrapes.Inc()
+	defer func() {
+		ch <- i.up
+		ch <- i.totalScrapes
+		ch <- i.jsonParseFailures
+	}()
+
+	ilmExplainResp, err := i.fetchAndDecodeILMExplain()
+	if err != nil {
+		i.up.Set(0)
+		_ = level.Warn(i.logger).Log(
+			"msg", "failed to fetch and decode ILM explain",
+			"err", err,
+		)
+		return
+	}
+	i.up.Set(1)
+
+	for key, histogram := range ilmPhaseAgeHistograms(ilmExplainResp.Indices, time.Now()) {
+		ch <- prometheus.MustNewConstHistogram(
+			i.phaseAge,
+			histogram.count,
+			histogram.sum,
+			histogram.buckets,
+			key.policy, key.phase,
+		)
+	}
+
+	for key, count := range ilmStepIndexCounts(ilmExplainResp.Indices) {
+		ch <- prometheus.MustNewConstMetric(
+			i.stepIndexCount,
+			prometheus.GaugeValue,
+			float64(count),
+			key.policy, key.phase, key.action, key.step,
+		)
+	}
+
+	// the policy index counts are skipped when either fetch fails, the explain metrics stay valid
+	settings, err := i.fetchAndDecodeLifecycleSettings()
+	if err != nil {
+		_ = level.Warn(i.logger).Log(
+			"msg", "failed to fetch and decode index lifecycle settings",
+			"err", err,
+		)
+		return
+	}
+	policies, err := i.fetchAndDecodeILMPolicies()
+	if err != nil {
+		_ = level.Warn(i.logger).Log(
+			"msg", "failed to fetch and decode ILM policies",
+			"err", err,
+		)
+		return
+	}
+	orphaned, unmanaged := ilmPolicyIndexCounts(settings, policies)
+	ch <- prometheus.MustNewConstMetric(
+		i.orphanedIndexCount,
+		prometheus.GaugeValue,
+		float64(orphaned),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		i.unmanagedIndexCount,
+		prometheus.GaugeValue,
+		float64(unmanaged),
+	)
+}
@@ -0,0 +1,49 @@
+package collector
+
+import "strings"
+
+// ILMExplainResponse is a representation of the index lifecycle management explain API
+type ILMExplainResponse struct {
+	Indices map[string]ILMExplainIndexResponse `json:"indices"`
+}
+
+// ILMExplainIndexResponse is a representation of the lifecycle state of a single index
+type ILMExplainIndexResponse struct {
+	Index            string `json:"index"`
+	Managed          bool   `json:"managed"`
+	Policy           string `json:"policy"`
+	Phase            string `json:"phase"`
+	PhaseTimeMillis  int64  `json:"phase_time_millis"`
+	Action           string `json:"action"`
+	ActionTimeMillis int64  `json:"action_time_millis"`
+	Step             string `json:"step"`
+	StepTimeMillis   int64  `json:"step_time_millis"`
+}
+
+// ILMPoliciesResponse is a representation of the lifecycle policies API, keyed by policy name
+type ILMPoliciesResponse map[string]ILMPolicyResponse
+
+// ILMPolicyResponse is a representation of a single lifecycle policy, its phases are ignored
+type ILMPolicyResponse struct {
+	Version      int64  `json:"version"`
+	ModifiedDate string `json:"modified_date"`
+}
+
+// ilmPolicyIndexCounts returns the number of indices set to a lifecycle policy which does not exist
+// and the number of indices without a lifecycle policy. System indices, starting with a dot, are
+// managed by Elasticsearch itself and are left out of the unmanaged indices.
+func ilmPolicyIndexCounts(settings IndicesSettingsResponse, policies ILMPoliciesResponse) (orphaned, unmanaged int) {
+	for name, index := range settings {
+		policy := index.Settings.IndexInfo.Lifecycle.Name
+		if policy == "" {
+			if !strings.HasPrefix(name, ".") {
+				unmanaged++
+			}
+			continue
+		}
+		if _, ok := policies[policy]; !ok {
+			orphaned++
+		}
+	}
+	return orphaned, unmanaged
+}
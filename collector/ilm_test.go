@@ -0,0 +1,122 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestILMExplain(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_ilm/policy/logs -H 'Content-Type: application/json' -d '{"policy":{"phases":{"hot":{"actions":{"rollover":{"max_age":"1d"}}},"delete":{"min_age":"30d","actions":{"delete":{}}}}}}'
+	//  curl -XPUT http://localhost:9200/logs-000001 -H 'Content-Type: application/json' -d '{"settings":{"index.lifecycle.name":"logs","index.lifecycle.rollover_alias":"logs"},"aliases":{"logs":{"is_write_index":true}}}'
+	//  curl http://localhost:9200/_all/_ilm/explain?only_managed=true
+	tcs := map[string]string{
+		"7.6.2": `{"indices":{"logs-000001":{"index":"logs-000001","managed":true,"policy":"logs","lifecycle_date_millis":1585735200000,"age":"2h","phase":"hot","phase_time_millis":1585735200000,"action":"rollover","action_time_millis":1585735200000,"step":"check-rollover-ready","step_time_millis":1585735200000,"phase_execution":{"policy":"logs","phase_definition":{"min_age":"0ms","actions":{"rollover":{"max_age":"1d"}}},"version":1,"modified_date_in_millis":1585735100000}},"logs-000002":{"index":"logs-000002","managed":true,"policy":"logs","lifecycle_date_millis":1585735200000,"age":"2h","phase":"hot","phase_time_millis":1585641600000,"action":"rollover","action_time_millis":1585641600000,"step":"check-rollover-ready","step_time_millis":1585641600000},"metrics-000001":{"index":"metrics-000001","managed":true,"policy":"metrics","lifecycle_date_millis":1583143200000,"age":"30d","phase":"delete","phase_time_millis":1583143200000,"action":"delete","action_time_millis":1583143200000,"step":"wait-for-shard-history-leases","step_time_millis":1583143200000}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewILM(log.NewNopLogger(), http.DefaultClient, u)
+		ier, err := i.fetchAndDecodeILMExplain()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode ILM explain: %s", err)
+		}
+		t.Logf("[%s] ILM Explain Response: %+v", ver, ier)
+		if len(ier.Indices) != 3 {
+			t.Fatalf("Wrong number of managed indices")
+		}
+
+		// two hours after logs-000001 entered the hot phase
+		now := time.Unix(1585742400, 0)
+		histograms := ilmPhaseAgeHistograms(ier.Indices, now)
+		if len(histograms) != 2 {
+			t.Fatalf("Wrong number of policy and phase combinations")
+		}
+		hot := histograms[ilmPhaseKey{policy: "logs", phase: "hot"}]
+		if hot == nil || hot.count != 2 {
+			t.Fatalf("Wrong number of indices in the logs hot phase")
+		}
+		if hot.sum != 7200+100800 {
+			t.Errorf("Wrong sum of phase ages: %v", hot.sum)
+		}
+		if hot.buckets[3600] != 0 || hot.buckets[86400] != 1 || hot.buckets[604800] != 2 {
+			t.Errorf("Wrong phase age buckets: %v", hot.buckets)
+		}
+		del := histograms[ilmPhaseKey{policy: "metrics", phase: "delete"}]
+		if del == nil || del.buckets[2592000] != 0 {
+			t.Errorf("Index in the delete phase for 30 days should exceed all buckets")
+		}
+
+		counts := ilmStepIndexCounts(ier.Indices)
+		if len(counts) != 2 {
+			t.Fatalf("Wrong number of policy, phase, action and step combinations")
+		}
+		if counts[ilmStepKey{policy: "logs", phase: "hot", action: "rollover", step: "check-rollover-ready"}] != 2 {
+			t.Errorf("Wrong number of indices waiting for rollover: %v", counts)
+		}
+		if counts[ilmStepKey{policy: "metrics", phase: "delete", action: "delete", step: "wait-for-shard-history-leases"}] != 1 {
+			t.Errorf("Wrong number of indices waiting for deletion: %v", counts)
+		}
+	}
+}
+
+func TestILMPolicyIndexCounts(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_ilm/policy/logs -H 'Content-Type: application/json' -d '{"policy":{"phases":{"hot":{"actions":{"rollover":{"max_age":"1d"}}}}}}'
+	//  curl -XPUT http://localhost:9200/logs-000001 -H 'Content-Type: application/json' -d '{"settings":{"index.lifecycle.name":"logs"}}'
+	//  curl -XPUT http://localhost:9200/metrics-000001 -H 'Content-Type: application/json' -d '{"settings":{"index.lifecycle.name":"metrics"}}'
+	//  curl -XPUT http://localhost:9200/twitter
+	//  curl http://localhost:9200/_all/_settings/index.lifecycle.name
+	//  curl http://localhost:9200/_ilm/policy
+	tcs := map[string]map[string]string{
+		"7.10.2": {
+			"/_all/_settings/index.lifecycle.name": `{"logs-000001":{"settings":{"index":{"lifecycle":{"name":"logs"}}}},"metrics-000001":{"settings":{"index":{"lifecycle":{"name":"metrics"}}}},"twitter":{"settings":{}},".kibana_1":{"settings":{}}}`,
+			"/_ilm/policy":                         `{"logs":{"version":1,"modified_date":"2021-04-01T10:00:00.000Z","policy":{"phases":{"hot":{"min_age":"0ms","actions":{"rollover":{"max_age":"1d"}}}}}},"ilm-history-ilm-policy":{"version":1,"modified_date":"2021-04-01T09:00:00.000Z","policy":{"phases":{"hot":{"min_age":"0ms","actions":{"rollover":{"max_size":"50gb","max_age":"30d"}}},"delete":{"min_age":"90d","actions":{"delete":{"delete_searchable_snapshot":true}}}}}}}`,
+		},
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out[r.URL.Path])
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewILM(log.NewNopLogger(), http.DefaultClient, u)
+		settings, err := i.fetchAndDecodeLifecycleSettings()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode index lifecycle settings: %s", err)
+		}
+		policies, err := i.fetchAndDecodeILMPolicies()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode ILM policies: %s", err)
+		}
+		t.Logf("[%s] Settings Response: %+v, ILM Policies Response: %+v", ver, settings, policies)
+
+		orphaned, unmanaged := ilmPolicyIndexCounts(settings, policies)
+		if orphaned != 1 {
+			t.Errorf("[%s] Wrong number of indices with a missing policy: %d", ver, orphaned)
+		}
+		// the .kibana_1 system index is not counted
+		if unmanaged != 1 {
+			t.Errorf("[%s] Wrong number of unmanaged indices: %d", ver, unmanaged)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type indexHealthMetric struct {
+	Type  prometheus.ValueType
+	Desc  *prometheus.Desc
+	Value func(index CatIndexResponse) float64
+}
+
+var (
+	indexHealthStatuses = map[string]float64{
+		"green":  2,
+		"yellow": 1,
+		"red":    0,
+	}
+
+	indexStatuses = map[string]float64{
+		"open":  1,
+		"close": 0,
+	}
+	// indexStatusUnknown is the value of statuses missing from indexStatuses
+	indexStatusUnknown = -1.0
+)
+
+// IndexHealth information struct
+type IndexHealth struct {
+	logger      log.Logger
+	client      *http.Client
+	url         *url.URL
+	indexFilter *regexp.Regexp
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	health       *prometheus.Desc
+	indexMetrics []*indexHealthMetric
+}
+
+// NewIndexHealth defines IndexHealth Prometheus metrics. If indexFilter is set only matching indices are exported.
+func NewIndexHealth(logger log.Logger, client *http.Client, url *url.URL, indexFilter *regexp.Regexp) *IndexHealth {
+	constLabels := constLabelsFromURL(url)
+	return &IndexHealth{
+		logger:      logger,
+		client:      client,
+		url:         url,
+		indexFilter: indexFilter,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_health", "up"),
+			Help:        "Was the last scrape of the ElasticSearch cat indices endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_health", "total_scrapes"),
+			Help:        "Current total ElasticSearch cat indices scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_health", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		health: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index", "health"),
+			"Health of the index (green=2, yellow=1, red=0)",
+			[]string{"index"}, constLabels,
+		),
+		indexMetrics: []*indexHealthMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index", "status"),
+					"Status of the index (open=1, close=0, unknown=-1)",
+					[]string{"index"}, constLabels,
+				),
+				Value: func(index CatIndexResponse) float64 {
+					return stateValue(indexStatuses, index.Status, indexStatusUnknown)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index", "primary_shards"),
+					"Number of primary shards of the index",
+					[]string{"index"}, constLabels,
+				),
+				Value: func(index CatIndexResponse) float64 {
+					return float64(index.PrimaryShards)
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index", "replica_shards"),
+					"Number of replicas configured for each primary shard of the index",
+					[]string{"index"}, constLabels,
+				),
+				Value: func(index CatIndexResponse) float64 {
+					return float64(index.ReplicaShards)
+				},
+			},
+		},
+	}
+}
+
+// Describe add IndexHealth metrics descriptions
+func (ih *IndexHealth) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ih.health
+	for _, metric := range ih.indexMetrics {
+		ch <- metric.Desc
+	}
+	ch <- ih.up.Desc()
+	ch <- ih.totalScrapes.Desc()
+	ch <- ih.jsonParseFailures.Desc()
+}
+
+func (ih *IndexHealth) fetchAndDecodeCatIndices() (CatIndicesResponse, error) {
+	var cir CatIndicesResponse
+
+	u := *ih.url
+	u.Path = path.Join(u.Path, "/_cat/indices")
+	u.RawQuery = "format=json&h=index,health,status,pri,rep,docs.count,docs.deleted"
+	err := getAndDecodeURL(ih.logger, ih.client, &u, &cir, ih.jsonParseFailures)
+	return cir, err
+}
+
+// Collect gets IndexHealth metric values
+func (ih *IndexHealth) Collect(ch chan<- prometheus.Metric) {
+	ih.totalScrapes.Inc()
+	defer func() {
+		ch <- ih.up
+		ch <- ih.totalScrapes
+		ch <- ih.jsonParseFailures
+	}()
+
+	catIndicesResp, err := ih.fetchAndDecodeCatIndices()
+	if err != nil {
+		ih.up.Set(0)
+		_ = level.Warn(ih.logger).Log(
+			"msg", "failed to fetch and decode cat indices",
+			"err", err,
+		)
+		return
+	}
+	ih.up.Set(1)
+
+	for _, index := range catIndicesResp {
+		if ih.indexFilter != nil && !ih.indexFilter.MatchString(index.Index) {
+			continue
+		}
+		// closed indices have no health before 7.2
+		if health, ok := indexHealthStatuses[index.Health]; ok {
+			ch <- prometheus.MustNewConstMetric(
+				ih.health,
+				prometheus.GaugeValue,
+				health,
+				index.Index,
+			)
+		}
+		for _, metric := range ih.indexMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(index),
+				index.Index,
+			)
+		}
+	}
+}
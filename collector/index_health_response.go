@@ -0,0 +1,13 @@
+package collector
+
+// CatIndicesResponse is a representation of the cat indices API
+type CatIndicesResponse []CatIndexResponse
+
+// CatIndexResponse is a representation of a single index in the cat indices API
+type CatIndexResponse struct {
+	Index         string `json:"index"`
+	Health        string `json:"health"`
+	Status        string `json:"status"`
+	PrimaryShards int64  `json:"pri,string"`
+	ReplicaShards int64  `json:"rep,string"`
+}
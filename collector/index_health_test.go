@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestIndexHealth(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter
+	//  curl -XPUT http://localhost:9200/logs -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":3,"number_of_replicas":0}}'
+	//  curl -XPUT http://localhost:9200/archive
+	//  curl -XPOST http://localhost:9200/archive/_close
+	//  curl 'http://localhost:9200/_cat/indices?format=json&h=index,health,status,pri,rep,docs.count,docs.deleted'
+	tcs := map[string]string{
+		"6.8.8": `[{"index":"twitter","health":"yellow","status":"open","pri":"5","rep":"1","docs.count":"12","docs.deleted":"0"},{"index":"logs","health":"green","status":"open","pri":"3","rep":"0","docs.count":"100","docs.deleted":"2"},{"index":"archive","health":"","status":"close","pri":"5","rep":"1","docs.count":null,"docs.deleted":null}]`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		ih := NewIndexHealth(log.NewNopLogger(), http.DefaultClient, u, nil)
+		cir, err := ih.fetchAndDecodeCatIndices()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cat indices: %s", err)
+		}
+		t.Logf("[%s] Cat Indices Response: %+v", ver, cir)
+		if len(cir) != 3 {
+			t.Fatalf("Wrong number of indices")
+		}
+		logs := cir[1]
+		if indexHealthStatuses[logs.Health] != 2 {
+			t.Errorf("Wrong health for logs")
+		}
+		expected := []float64{1, 3, 0}
+		for i, metric := range ih.indexMetrics {
+			if v := metric.Value(logs); v != expected[i] {
+				t.Errorf("Wrong value for index metric %d: got %v, expected %v", i, v, expected[i])
+			}
+		}
+		archive := cir[2]
+		if _, ok := indexHealthStatuses[archive.Health]; ok {
+			t.Errorf("Closed index should have no health")
+		}
+		if v := ih.indexMetrics[0].Value(archive); v != 0 {
+			t.Errorf("Wrong status for archive: %v", v)
+		}
+		archive.Status = "frozen"
+		if v := ih.indexMetrics[0].Value(archive); v != indexStatusUnknown {
+			t.Errorf("Wrong value for unknown status: %v", v)
+		}
+
+		// only the metrics of logs plus up, total_scrapes and json_parse_failures
+		ih = NewIndexHealth(log.NewNopLogger(), http.DefaultClient, u, regexp.MustCompile("^logs$"))
+		ch := make(chan prometheus.Metric)
+		go func() {
+			ih.Collect(ch)
+			close(ch)
+		}()
+		var count int
+		for range ch {
+			count++
+		}
+		if count != 7 {
+			t.Errorf("Wrong number of metrics with index filter: %d", count)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// IndexZones information struct
+type IndexZones struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	attribute string
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	zonesCovered *prometheus.Desc
+	zones        *prometheus.Desc
+}
+
+// NewIndexZones defines IndexZones Prometheus metrics. The zone of a node is the value of its
+// attribute node attribute, the one used for shard allocation awareness.
+func NewIndexZones(logger log.Logger, client *http.Client, url *url.URL, attribute string) *IndexZones {
+	constLabels := constLabelsFromURL(url)
+	return &IndexZones{
+		logger:    logger,
+		client:    client,
+		url:       url,
+		attribute: attribute,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_zones", "up"),
+			Help:        "Was the last scrape of the ElasticSearch routing table and node attributes successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_zones", "total_scrapes"),
+			Help:        "Current total ElasticSearch index zones scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "index_zones", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		zonesCovered: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index_shard", "zones_covered"),
+			"Number of zones hosting at least one started shard copy of the index",
+			[]string{"index"}, constLabels,
+		),
+		zones: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "zones"),
+			"Number of distinct zones of the nodes in the cluster",
+			[]string{"cluster"}, constLabels,
+		),
+	}
+}
+
+// Describe add IndexZones metrics descriptions
+func (z *IndexZones) Describe(ch chan<- *prometheus.Desc) {
+	ch <- z.zonesCovered
+	ch <- z.zones
+	ch <- z.up.Desc()
+	ch <- z.totalScrapes.Desc()
+	ch <- z.jsonParseFailures.Desc()
+}
+
+func (z *IndexZones) fetchAndDecodeRoutingTable() (routingTableResponse, error) {
+	var rtr routingTableResponse
+
+	u := *z.url
+	u.Path = path.Join(u.Path, "/_cluster/state/routing_table")
+	u.RawQuery = "filter_path=cluster_name,routing_table.indices.*.shards.*.state,routing_table.indices.*.shards.*.node"
+	err := getAndDecodeURL(z.logger, z.client, &u, &rtr, z.jsonParseFailures)
+	return rtr, err
+}
+
+func (z *IndexZones) fetchAndDecodeNodeAttributes() (nodeAttributesResponse, error) {
+	var nar nodeAttributesResponse
+
+	u := *z.url
+	u.Path = path.Join(u.Path, "/_nodes")
+	u.RawQuery = "filter_path=nodes.*.name,nodes.*.attributes"
+	err := getAndDecodeURL(z.logger, z.client, &u, &nar, z.jsonParseFailures)
+	return nar, err
+}
+
+// Collect gets IndexZones metric values
+func (z *IndexZones) Collect(ch chan<- prometheus.Metric) {
+	z.totalScrapes.Inc()
+	defer func() {
+		ch <- z.up
+		ch <- z.totalScrapes
+		ch <- z.jsonParseFailures
+	}()
+
+	routingTable, err := z.fetchAndDecodeRoutingTable()
+	if err != nil {
+		z.up.Set(0)
+		_ = level.Warn(z.logger).Log(
+			"msg", "failed to fetch and decode routing table",
+			"err", err,
+		)
+		return
+	}
+	nodes, err := z.fetchAndDecodeNodeAttributes()
+	if err != nil {
+		z.up.Set(0)
+		_ = level.Warn(z.logger).Log(
+			"msg", "failed to fetch and decode node attributes",
+			"err", err,
+		)
+		return
+	}
+	z.up.Set(1)
+
+	ch <- prometheus.MustNewConstMetric(
+		z.zones,
+		prometheus.GaugeValue,
+		float64(clusterZones(nodes, z.attribute)),
+		routingTable.ClusterName,
+	)
+	for index, covered := range indexZonesCovered(routingTable, nodes, z.attribute) {
+		ch <- prometheus.MustNewConstMetric(
+			z.zonesCovered,
+			prometheus.GaugeValue,
+			float64(covered),
+			index,
+		)
+	}
+}
@@ -0,0 +1,61 @@
+package collector
+
+// routingTableResponse is a representation of the routing table of the cluster state API
+type routingTableResponse struct {
+	ClusterName  string `json:"cluster_name"`
+	RoutingTable struct {
+		Indices map[string]routingTableIndexResponse `json:"indices"`
+	} `json:"routing_table"`
+}
+
+// routingTableIndexResponse holds the copies of every shard of an index, keyed by shard number
+type routingTableIndexResponse struct {
+	Shards map[string][]routingTableShardResponse `json:"shards"`
+}
+
+// routingTableShardResponse is a single shard copy, Node is empty while the copy is unassigned
+type routingTableShardResponse struct {
+	State string `json:"state"`
+	Node  string `json:"node"`
+}
+
+// nodeAttributesResponse is a representation of the attributes of the nodes info API, keyed by node ID
+type nodeAttributesResponse struct {
+	Nodes map[string]struct {
+		Name       string            `json:"name"`
+		Attributes map[string]string `json:"attributes"`
+	} `json:"nodes"`
+}
+
+// indexZonesCovered returns for every index the number of zones hosting at least one started copy of
+// its shards. Nodes without the zone attribute are not counted as a zone.
+func indexZonesCovered(routingTable routingTableResponse, nodes nodeAttributesResponse, attribute string) map[string]int {
+	covered := make(map[string]int, len(routingTable.RoutingTable.Indices))
+	for name, index := range routingTable.RoutingTable.Indices {
+		zones := make(map[string]bool)
+		for _, copies := range index.Shards {
+			for _, shard := range copies {
+				// relocating copies are still served by their source node
+				if shard.State != "STARTED" && shard.State != "RELOCATING" {
+					continue
+				}
+				if zone := nodes.Nodes[shard.Node].Attributes[attribute]; zone != "" {
+					zones[zone] = true
+				}
+			}
+		}
+		covered[name] = len(zones)
+	}
+	return covered
+}
+
+// clusterZones returns the number of distinct zones of the nodes
+func clusterZones(nodes nodeAttributesResponse, attribute string) int {
+	zones := make(map[string]bool)
+	for _, node := range nodes.Nodes {
+		if zone := node.Attributes[attribute]; zone != "" {
+			zones[zone] = true
+		}
+	}
+	return len(zones)
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestIndexZones(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "node.attr.zone=zone-a" -e "cluster.routing.allocation.awareness.attributes=zone" elasticsearch:VERSION
+	//  (second node with node.attr.zone=zone-b, third node without a zone)
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":2,"number_of_replicas":1}}'
+	//  curl -XPUT http://localhost:9200/logs -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":1,"number_of_replicas":2}}'
+	//  curl 'http://localhost:9200/_cluster/state/routing_table?filter_path=cluster_name,routing_table.indices.*.shards.*.state,routing_table.indices.*.shards.*.node'
+	//  curl 'http://localhost:9200/_nodes?filter_path=nodes.*.name,nodes.*.attributes'
+	tcs := map[string]map[string]string{
+		"7.10.2": {
+			"/_cluster/state/routing_table": `{"cluster_name":"elasticsearch","routing_table":{"indices":{"twitter":{"shards":{"0":[{"state":"STARTED","primary":true,"node":"9_P7yui4SQOkzGhTZCyjxQ"},{"state":"STARTED","primary":false,"node":"Jx0Vt0hTR0iVbVGRx1oBCA"}],"1":[{"state":"STARTED","primary":true,"node":"Jx0Vt0hTR0iVbVGRx1oBCA"},{"state":"INITIALIZING","primary":false,"node":"9_P7yui4SQOkzGhTZCyjxQ"}]}},"logs":{"shards":{"0":[{"state":"STARTED","primary":true,"node":"9_P7yui4SQOkzGhTZCyjxQ"},{"state":"STARTED","primary":false,"node":"bN4Pw0hIQl6sYo8fWvvZkQ"},{"state":"UNASSIGNED","primary":false,"node":null}]}}}}}`,
+			"/_nodes":                       `{"nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0","attributes":{"zone":"zone-a","xpack.installed":"true"}},"Jx0Vt0hTR0iVbVGRx1oBCA":{"name":"node-1","attributes":{"zone":"zone-b","xpack.installed":"true"}},"bN4Pw0hIQl6sYo8fWvvZkQ":{"name":"node-2","attributes":{"xpack.installed":"true"}}}}`,
+		},
+	}
+	for ver, out := range tcs {
+		ts, u := newFixtureServer(t, out)
+		defer ts.Close()
+		z := NewIndexZones(log.NewNopLogger(), http.DefaultClient, u, "zone")
+		routingTable, err := z.fetchAndDecodeRoutingTable()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode routing table: %s", err)
+		}
+		nodes, err := z.fetchAndDecodeNodeAttributes()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node attributes: %s", err)
+		}
+		t.Logf("[%s] Routing Table Response: %+v", ver, routingTable)
+
+		if zones := clusterZones(nodes, "zone"); zones != 2 {
+			t.Errorf("[%s] Wrong number of cluster zones: %d", ver, zones)
+		}
+		expected := map[string]int{
+			"twitter": 2,
+			// the replica on the node without a zone does not count
+			"logs": 1,
+		}
+		covered := indexZonesCovered(routingTable, nodes, "zone")
+		if len(covered) != len(expected) {
+			t.Fatalf("[%s] Wrong number of indices: %v", ver, covered)
+		}
+		for index, want := range expected {
+			if covered[index] != want {
+				t.Errorf("[%s] Wrong zones covered by %s: got %d, expected %d", ver, index, covered[index], want)
+			}
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package collector
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -38,6 +40,8 @@ type Indices struct {
 	client          *http.Client
 	url             *url.URL
 	shards          bool
+	aggregateOnly   bool
+	verboseSegments bool
 	clusterInfoCh   chan *clusterinfo.Response
 	lastClusterInfo *clusterinfo.Response
 
@@ -45,12 +49,23 @@ type Indices struct {
 	totalScrapes      prometheus.Counter
 	jsonParseFailures prometheus.Counter
 
-	indexMetrics []*indexMetric
-	shardMetrics []*shardMetric
+	indexMetrics      []*indexMetric
+	shardMetrics      []*shardMetric
+	searchSlowMetrics []*indexMetric
+	searchTimedOut    *indexMetric
+	flushPeriodic     *indexMetric
+	allIndicesMetrics []*indexMetric
+	allBulkMetrics    []*indexMetric
+
+	maxSegmentSize *prometheus.Desc
+
+	buffer *metricsBuffer
 }
 
-// NewIndices defines Indices Prometheus metrics
-func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards bool) *Indices {
+// NewIndices defines Indices Prometheus metrics. With aggregateOnly only the stats aggregated over
+// all indices are fetched, avoiding the cardinality of the per index metrics. With verboseSegments
+// the segments of every shard are fetched to report the size of the largest segment of each index.
+func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards bool, aggregateOnly bool, verboseSegments bool) *Indices {
 	constLabels := constLabelsFromURL(url)
 
 	indexLabels := labels{
@@ -81,12 +96,28 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 		},
 	}
 
+	clusterLabels := labels{
+		keys: func(...string) []string {
+			return []string{"cluster"}
+		},
+		values: func(lastClusterinfo *clusterinfo.Response, s ...string) []string {
+			if lastClusterinfo != nil {
+				return append(s, lastClusterinfo.ClusterName)
+			}
+			// this shouldn't happen, as the clusterinfo Retriever has a blocking
+			// Run method. It blocks until the first clusterinfo call has succeeded
+			return append(s, "unknown_cluster")
+		},
+	}
+
 	indices := &Indices{
-		logger:        logger,
-		client:        client,
-		url:           url,
-		shards:        shards,
-		clusterInfoCh: make(chan *clusterinfo.Response),
+		logger:          logger,
+		client:          client,
+		url:             url,
+		shards:          shards,
+		aggregateOnly:   aggregateOnly,
+		verboseSegments: verboseSegments,
+		clusterInfoCh:   make(chan *clusterinfo.Response),
 		lastClusterInfo: &clusterinfo.Response{
 			ClusterName: "unknown_cluster",
 		},
@@ -396,6 +427,30 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 				},
 				Labels: indexLabels,
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indices", "segment_index_writer_max_memory_bytes_primary"),
+					"Maximum size of index writer with only primary shards on all nodes in bytes",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Primaries.Segments.IndexWriterMaxMemoryInBytes)
+				},
+				Labels: indexLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indices", "segment_index_writer_max_memory_bytes_total"),
+					"Maximum size of index writer with all shards on all nodes in bytes",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Segments.IndexWriterMaxMemoryInBytes)
+				},
+				Labels: indexLabels,
+			},
 			{
 				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
@@ -444,6 +499,21 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 				},
 				Labels: indexLabels,
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index", "average_segment_size_bytes"),
+					"Average store size of the segments of the index with all shards on all nodes in bytes",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					if indexStats.Total.Segments.Count == 0 {
+						return 0
+					}
+					return float64(indexStats.Total.Store.SizeInBytes) / float64(indexStats.Total.Segments.Count)
+				},
+				Labels: indexLabels,
+			},
 			{
 				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
@@ -805,9 +875,9 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 				Labels: indexLabels,
 			},
 			{
-				Type: prometheus.CounterValue,
+				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
-					prometheus.BuildFQName(namespace, "index_stats", "query_cache_memory_bytes_total"),
+					prometheus.BuildFQName(namespace, "index_stats", "query_cache_memory_bytes"),
 					"Total query cache memory bytes",
 					indexLabels.keys(), constLabels,
 				),
@@ -877,9 +947,9 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 				Labels: indexLabels,
 			},
 			{
-				Type: prometheus.CounterValue,
+				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
-					prometheus.BuildFQName(namespace, "index_stats", "request_cache_memory_bytes_total"),
+					prometheus.BuildFQName(namespace, "index_stats", "request_cache_memory_bytes"),
 					"Total request cache memory bytes",
 					indexLabels.keys(), constLabels,
 				),
@@ -925,9 +995,9 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 				Labels: indexLabels,
 			},
 			{
-				Type: prometheus.CounterValue,
+				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
-					prometheus.BuildFQName(namespace, "index_stats", "fielddata_memory_bytes_total"),
+					prometheus.BuildFQName(namespace, "index_stats", "fielddata_memory_bytes"),
 					"Total fielddata memory bytes",
 					indexLabels.keys(), constLabels,
 				),
@@ -948,6 +1018,164 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 				},
 				Labels: indexLabels,
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "translog_operations"),
+					"Current number of operations in the translog",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Translog.Operations)
+				},
+				Labels: indexLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "translog_size_bytes"),
+					"Current size of the translog in bytes",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Translog.SizeInBytes)
+				},
+				Labels: indexLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "translog_uncommitted_operations"),
+					"Current number of operations in the translog not yet committed to Lucene",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Translog.UncommittedOperations)
+				},
+				Labels: indexLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "translog_uncommitted_size_bytes"),
+					"Current size of the translog operations not yet committed to Lucene in bytes",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Translog.UncommittedSizeInBytes)
+				},
+				Labels: indexLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "translog_earliest_last_modified_age_seconds"),
+					"Age of the oldest translog generation in seconds",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Translog.EarliestLastModifiedAge) / 1000
+				},
+				Labels: indexLabels,
+			},
+		},
+		allIndicesMetrics: []*indexMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_query_cache_memory_bytes"),
+					"Query cache memory bytes of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.QueryCache.MemorySizeInBytes)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_request_cache_memory_bytes"),
+					"Request cache memory bytes of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.RequestCache.MemorySizeInBytes)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_fielddata_memory_bytes"),
+					"Fielddata memory bytes of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Fielddata.MemorySizeInBytes)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_docs"),
+					"Count of documents of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Docs.Count)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_store_size_bytes"),
+					"Size in bytes of all shards of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Store.SizeInBytes)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_indexing_index_total"),
+					"Total indexing index count of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Indexing.IndexTotal)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_search_query_total"),
+					"Total number of queries of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Search.QueryTotal)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "all_search_query_time_seconds_total"),
+					"Total search query time of all indices in seconds",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Search.QueryTimeInMillis) / 1000
+				},
+				Labels: clusterLabels,
+			},
 		},
 		shardMetrics: []*shardMetric{
 			{
@@ -975,6 +1203,88 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 				Labels: shardLabels,
 			},
 		},
+		searchSlowMetrics: []*indexMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index_stats", "search_slow_total"),
+					"Total number of searches exceeding the slow log threshold, only exported when reported by the cluster",
+					indexLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(*indexStats.Total.Search.SlowTotal)
+				},
+				Labels: indexLabels,
+			},
+		},
+		searchTimedOut: &indexMetric{
+			Type: prometheus.CounterValue,
+			Desc: prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, "index_stats", "search_timed_out_total"),
+				"Total number of searches which hit their timeout and returned partial results, only exported when reported by the cluster",
+				indexLabels.keys(), constLabels,
+			),
+			Value: func(indexStats IndexStatsIndexResponse) float64 {
+				timedOut, _ := indexStats.Total.Search.timedOutTotal()
+				return float64(timedOut)
+			},
+			Labels: indexLabels,
+		},
+		flushPeriodic: &indexMetric{
+			Type: prometheus.CounterValue,
+			Desc: prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, "index_stats", "flush_periodic_total"),
+				"Total number of flushes triggered by the translog reaching its flush threshold size, only exported when reported by the cluster",
+				indexLabels.keys(), constLabels,
+			),
+			Value: func(indexStats IndexStatsIndexResponse) float64 {
+				return float64(*indexStats.Total.Flush.Periodic)
+			},
+			Labels: indexLabels,
+		},
+		allBulkMetrics: []*indexMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "cluster", "bulk_total_operations"),
+					"Total number of bulk shard operations of all indices",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Bulk.TotalOperations)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "cluster", "bulk_total_size_bytes"),
+					"Total size of the bulk shard operations of all indices in bytes",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Bulk.TotalSizeInBytes)
+				},
+				Labels: clusterLabels,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "cluster", "bulk_avg_size_bytes"),
+					"Average size of the bulk shard operations of all indices in bytes",
+					clusterLabels.keys(), constLabels,
+				),
+				Value: func(indexStats IndexStatsIndexResponse) float64 {
+					return float64(indexStats.Total.Bulk.AvgSizeInBytes)
+				},
+				Labels: clusterLabels,
+			},
+		},
+		maxSegmentSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index", "max_segment_size_bytes"),
+			"Size of the largest segment of any shard of the index in bytes",
+			indexLabels.keys(), constLabels,
+		),
 	}
 
 	// start go routine to fetch clusterinfo updates and save them to lastClusterinfo
@@ -988,6 +1298,11 @@ func NewIndices(logger log.Logger, client *http.Client, url *url.URL, shards boo
 		}
 		_ = level.Debug(logger).Log("msg", "exiting cluster info receive loop")
 	}()
+	indices.buffer = newMetricsBuffer(indices.collect, prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        prometheus.BuildFQName(namespace, "index_stats", "last_refresh_timestamp_seconds"),
+		Help:        "Time of the last successful background refresh of the index metrics in seconds since epoch.",
+		ConstLabels: constLabels,
+	}))
 	return indices
 }
 
@@ -1007,6 +1322,19 @@ func (i *Indices) Describe(ch chan<- *prometheus.Desc) {
 	for _, metric := range i.indexMetrics {
 		ch <- metric.Desc
 	}
+	for _, metric := range i.searchSlowMetrics {
+		ch <- metric.Desc
+	}
+	ch <- i.searchTimedOut.Desc
+	ch <- i.flushPeriodic.Desc
+	for _, metric := range i.allIndicesMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range i.allBulkMetrics {
+		ch <- metric.Desc
+	}
+	ch <- i.maxSegmentSize
+	i.buffer.Describe(ch)
 	ch <- i.up.Desc()
 	ch <- i.totalScrapes.Desc()
 	ch <- i.jsonParseFailures.Desc()
@@ -1017,7 +1345,11 @@ func (i *Indices) fetchAndDecodeIndexStats() (indexStatsResponse, error) {
 
 	u := *i.url
 	u.Path = path.Join(u.Path, "/_all/_stats")
-	if i.shards {
+	switch {
+	case i.aggregateOnly:
+		// skip the per index breakdown
+		u.RawQuery = "filter_path=_all.total"
+	case i.shards:
 		u.RawQuery = "level=shards"
 	}
 
@@ -1049,8 +1381,54 @@ func (i *Indices) fetchAndDecodeIndexStats() (indexStatsResponse, error) {
 	return isr, nil
 }
 
-// Collect gets Indices metric values
+func (i *Indices) fetchAndDecodeIndexSegments() (indexSegmentsResponse, error) {
+	var isr indexSegmentsResponse
+
+	u := *i.url
+	u.Path = path.Join(u.Path, "/_all/_segments")
+
+	res, err := i.client.Get(u.String())
+	if err != nil {
+		return isr, fmt.Errorf("failed to get index segments from %s://%s:%s%s: %s",
+			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
+	}
+
+	defer func() {
+		err = res.Body.Close()
+		if err != nil {
+			_ = level.Warn(i.logger).Log(
+				"msg", "failed to close http.Client",
+				"err", err,
+			)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return isr, fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&isr); err != nil {
+		i.jsonParseFailures.Inc()
+		return isr, err
+	}
+
+	return isr, nil
+}
+
+// Run refreshes the index metrics in the background every interval until ctx is cancelled.
+// Collect then serves the last complete set of metrics instead of querying the cluster, so the
+// expensive index stats are fetched independently of the scrape interval.
+func (i *Indices) Run(ctx context.Context, interval time.Duration) {
+	i.buffer.Run(ctx, interval)
+}
+
+// Collect gets Indices metric values, from the buffer filled by Run if available
 func (i *Indices) Collect(ch chan<- prometheus.Metric) {
+	i.buffer.Collect(ch)
+}
+
+// collect fetches the index stats and sends the index metrics, it returns the error of the index stats request
+func (i *Indices) collect(ch chan<- prometheus.Metric) error {
 	i.totalScrapes.Inc()
 	defer func() {
 		ch <- i.up
@@ -1066,11 +1444,33 @@ func (i *Indices) Collect(ch chan<- prometheus.Metric) {
 			"msg", "failed to fetch and decode index stats",
 			"err", err,
 		)
-		return
+		return err
 	}
 	i.totalScrapes.Inc()
 	i.up.Set(1)
 
+	// Aggregated stats of all indices
+	for _, metric := range i.allIndicesMetrics {
+		ch <- prometheus.MustNewConstMetric(
+			metric.Desc,
+			metric.Type,
+			metric.Value(indexStatsResp.All),
+			metric.Labels.values(i.lastClusterInfo)...,
+		)
+	}
+
+	// Bulk stats are skipped when the version does not report them
+	if indexStatsResp.All.Total.Bulk != nil {
+		for _, metric := range i.allBulkMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(indexStatsResp.All),
+				metric.Labels.values(i.lastClusterInfo)...,
+			)
+		}
+	}
+
 	// Index stats
 	for indexName, indexStats := range indexStatsResp.Indices {
 		for _, metric := range i.indexMetrics {
@@ -1082,6 +1482,35 @@ func (i *Indices) Collect(ch chan<- prometheus.Metric) {
 			)
 
 		}
+		// Slow search stats are skipped when the version does not report them
+		if indexStats.Total.Search.SlowTotal != nil {
+			for _, metric := range i.searchSlowMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(indexStats),
+					metric.Labels.values(i.lastClusterInfo, indexName)...,
+				)
+			}
+		}
+		// Timed out searches are skipped as well when the version does not report them
+		if _, ok := indexStats.Total.Search.timedOutTotal(); ok {
+			ch <- prometheus.MustNewConstMetric(
+				i.searchTimedOut.Desc,
+				i.searchTimedOut.Type,
+				i.searchTimedOut.Value(indexStats),
+				i.searchTimedOut.Labels.values(i.lastClusterInfo, indexName)...,
+			)
+		}
+		// Periodic flushes are reported since Elasticsearch 6.3
+		if indexStats.Total.Flush.Periodic != nil {
+			ch <- prometheus.MustNewConstMetric(
+				i.flushPeriodic.Desc,
+				i.flushPeriodic.Type,
+				i.flushPeriodic.Value(indexStats),
+				i.flushPeriodic.Labels.values(i.lastClusterInfo, indexName)...,
+			)
+		}
 		if i.shards {
 			for _, metric := range i.shardMetrics {
 				// gaugeVec := prometheus.NewGaugeVec(metric.Opts, metric.Labels)
@@ -1098,4 +1527,29 @@ func (i *Indices) Collect(ch chan<- prometheus.Metric) {
 			}
 		}
 	}
+
+	if i.verboseSegments && !i.aggregateOnly {
+		i.collectSegments(ch)
+	}
+	return nil
+}
+
+func (i *Indices) collectSegments(ch chan<- prometheus.Metric) {
+	indexSegmentsResp, err := i.fetchAndDecodeIndexSegments()
+	if err != nil {
+		_ = level.Warn(i.logger).Log(
+			"msg", "failed to fetch and decode index segments",
+			"err", err,
+		)
+		return
+	}
+
+	for indexName, indexSegments := range indexSegmentsResp.Indices {
+		ch <- prometheus.MustNewConstMetric(
+			i.maxSegmentSize,
+			prometheus.GaugeValue,
+			float64(indexSegments.MaxSegmentSize()),
+			indexName, i.lastClusterInfo.ClusterName,
+		)
+	}
 }
@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type indexMappingMetric struct {
+	Type  prometheus.ValueType
+	Desc  *prometheus.Desc
+	Value func(indexMapping IndexMappingsResponse) float64
+}
+
+// IndicesMappings information struct
+type IndicesMappings struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	indexMappingMetrics    []*indexMappingMetric
+	deprecatedTypeMappings *prometheus.Desc
+
+	mu sync.Mutex
+	// typelessOnly is set once the cluster rejected include_type_name, either because it
+	// predates 6.7 or because mapping types are gone (8.x)
+	typelessOnly bool
+}
+
+// NewIndicesMappings defines Indices Mappings Prometheus metrics
+func NewIndicesMappings(logger log.Logger, client *http.Client, url *url.URL) *IndicesMappings {
+	constLabels := constLabelsFromURL(url)
+	return &IndicesMappings{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "indices_mappings_stats", "up"),
+			Help:        "Was the last scrape of the ElasticSearch Indices Mappings endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "indices_mappings_stats", "total_scrapes"),
+			Help:        "Current total ElasticSearch Indices Mappings scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "indices_mappings_stats", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		deprecatedTypeMappings: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "indices", "deprecated_type_mappings_total"),
+			"Number of indices with a mapping type other than _doc, which must be reindexed before upgrading to 8.x",
+			nil, constLabels,
+		),
+		indexMappingMetrics: []*indexMappingMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index", "field_count"),
+					"Number of mapped leaf fields in the index mapping, including multi-fields",
+					[]string{"index"}, constLabels,
+				),
+				Value: func(indexMapping IndexMappingsResponse) float64 {
+					return float64(countFields(indexMapping.Mappings))
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "index", "mapping_total_bytes"),
+					"Size of the JSON serialization of the index mapping in bytes",
+					[]string{"index"}, constLabels,
+				),
+				Value: func(indexMapping IndexMappingsResponse) float64 {
+					return float64(mappingSize(indexMapping.Mappings))
+				},
+			},
+		},
+	}
+}
+
+// Describe add Indices Mappings metrics descriptions
+func (im *IndicesMappings) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range im.indexMappingMetrics {
+		ch <- metric.Desc
+	}
+	ch <- im.deprecatedTypeMappings
+	ch <- im.up.Desc()
+	ch <- im.totalScrapes.Desc()
+	ch <- im.jsonParseFailures.Desc()
+}
+
+// fetchAndDecodeIndicesMappings fetches the mappings in the typed format where supported, so the
+// mapping types of indices created by 6.x are also visible on 7.x
+func (im *IndicesMappings) fetchAndDecodeIndicesMappings() (IndicesMappingsResponse, error) {
+	var imr IndicesMappingsResponse
+
+	u := *im.url
+	u.Path = path.Join(u.Path, "/_mapping")
+
+	im.mu.Lock()
+	typelessOnly := im.typelessOnly
+	im.mu.Unlock()
+
+	if !typelessOnly {
+		typed := u
+		typed.RawQuery = "include_type_name=true"
+		err := getAndDecodeURL(im.logger, im.client, &typed, &imr, im.jsonParseFailures)
+		if err == nil {
+			return imr, nil
+		}
+		if statusErr, ok := err.(*httpStatusError); !ok || statusErr.StatusCode != http.StatusBadRequest {
+			return imr, err
+		}
+		_ = level.Debug(im.logger).Log(
+			"msg", "cluster rejected include_type_name, falling back to typeless mappings",
+			"err", err,
+		)
+		im.mu.Lock()
+		im.typelessOnly = true
+		im.mu.Unlock()
+	}
+
+	imr = IndicesMappingsResponse{}
+	err := getAndDecodeURL(im.logger, im.client, &u, &imr, im.jsonParseFailures)
+	return imr, err
+}
+
+// Collect gets Indices Mappings metric values
+func (im *IndicesMappings) Collect(ch chan<- prometheus.Metric) {
+	im.totalScrapes.Inc()
+	defer func() {
+		ch <- im.up
+		ch <- im.totalScrapes
+		ch <- im.jsonParseFailures
+	}()
+
+	indicesMappingsResp, err := im.fetchAndDecodeIndicesMappings()
+	if err != nil {
+		im.up.Set(0)
+		_ = level.Warn(im.logger).Log(
+			"msg", "failed to fetch and decode indices mappings",
+			"err", err,
+		)
+		return
+	}
+	im.up.Set(1)
+
+	var deprecatedTypeMappings int
+	for indexName, indexMapping := range indicesMappingsResp {
+		for _, metric := range im.indexMappingMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(indexMapping),
+				indexName,
+			)
+		}
+		if hasDeprecatedMappingType(indexMapping.Mappings) {
+			deprecatedTypeMappings++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(
+		im.deprecatedTypeMappings,
+		prometheus.GaugeValue,
+		float64(deprecatedTypeMappings),
+	)
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// IndicesMappingsResponse is a representation of the mappings of every index
+type IndicesMappingsResponse map[string]IndexMappingsResponse
+
+// IndexMappingsResponse is a representation of the mappings of a single index.
+// Mappings is kept generic since its shape depends on the fields of the index.
+type IndexMappingsResponse struct {
+	Mappings map[string]interface{} `json:"mappings"`
+}
+
+// countFields returns the number of mapped leaf fields of a mapping, recursing into object
+// fields and multi-fields. Meta fields (_id, _source, ...) are not counted. Mappings with a
+// type level (6.x and older) are handled by recursing into every type.
+func countFields(mapping map[string]interface{}) int {
+	properties, ok := mapping["properties"].(map[string]interface{})
+	if !ok {
+		var count int
+		for _, typeMapping := range mapping {
+			if m, ok := typeMapping.(map[string]interface{}); ok {
+				count += countFields(m)
+			}
+		}
+		return count
+	}
+
+	var count int
+	for name, property := range properties {
+		if len(name) > 0 && name[0] == '_' {
+			continue
+		}
+		field, ok := property.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := field["properties"]; ok {
+			count += countFields(field)
+		} else {
+			count++
+		}
+		// multi-fields, e.g. the keyword sub-field of a text field, are mapped as separate fields
+		if fields, ok := field["fields"].(map[string]interface{}); ok {
+			count += countFields(map[string]interface{}{"properties": fields})
+		}
+	}
+	return count
+}
+
+// mappingSize returns the size in bytes of the compact JSON serialization of a mapping
+func mappingSize(mapping map[string]interface{}) int {
+	b, err := json.Marshal(mapping)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// mappingParameters are the root level parameters of a typeless mapping, which are not mapping types
+var mappingParameters = map[string]bool{
+	"properties":             true,
+	"dynamic":                true,
+	"dynamic_templates":      true,
+	"dynamic_date_formats":   true,
+	"date_detection":         true,
+	"numeric_detection":      true,
+	"runtime":                true,
+	"enabled":                true,
+	"_source":                true,
+	"_routing":               true,
+	"_meta":                  true,
+	"_all":                   true,
+	"_field_names":           true,
+	"_size":                  true,
+	"_data_stream_timestamp": true,
+}
+
+// mappingTypes returns the names of the mapping types of a mapping in the typed format,
+// returned by 6.x and by 7.x with include_type_name. Typeless mappings have no types.
+func mappingTypes(mapping map[string]interface{}) []string {
+	if _, ok := mapping["properties"]; ok {
+		return nil
+	}
+	var types []string
+	for name, typeMapping := range mapping {
+		if mappingParameters[name] {
+			continue
+		}
+		if _, ok := typeMapping.(map[string]interface{}); ok {
+			types = append(types, name)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// hasDeprecatedMappingType returns true if the mapping uses a mapping type other than _doc,
+// which can't be read by 8.x
+func hasDeprecatedMappingType(mapping map[string]interface{}) bool {
+	for _, name := range mappingTypes(mapping) {
+		if name != "_doc" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestIndicesMappings(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"mappings":{"_source":{"enabled":true},"properties":{"message":{"type":"text","fields":{"keyword":{"type":"keyword"}}},"user":{"properties":{"name":{"type":"keyword"},"address":{"properties":{"city":{"type":"keyword"},"zip":{"type":"keyword"}}}}},"created":{"type":"date"}}}}'
+	//  curl -XPUT http://localhost:9200/empty
+	//  curl http://localhost:9200/_mapping
+	tcs := map[string]string{
+		"7.6.2": `{"twitter":{"mappings":{"_source":{"enabled":true},"properties":{"created":{"type":"date"},"message":{"type":"text","fields":{"keyword":{"type":"keyword"}}},"user":{"properties":{"address":{"properties":{"city":{"type":"keyword"},"zip":{"type":"keyword"}}},"name":{"type":"keyword"}}}}}},"empty":{"mappings":{}}}`,
+		"6.8.8": `{"twitter":{"mappings":{"_doc":{"_source":{"enabled":true},"properties":{"created":{"type":"date"},"message":{"type":"text","fields":{"keyword":{"type":"keyword"}}},"user":{"properties":{"address":{"properties":{"city":{"type":"keyword"},"zip":{"type":"keyword"}}},"name":{"type":"keyword"}}}}}}},"empty":{"mappings":{}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		im := NewIndicesMappings(log.NewNopLogger(), http.DefaultClient, u)
+		imr, err := im.fetchAndDecodeIndicesMappings()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices mappings: %s", err)
+		}
+		t.Logf("[%s] Indices Mappings Response: %+v", ver, imr)
+		if len(imr) != 2 {
+			t.Fatalf("Wrong number of indices")
+		}
+		if count := countFields(imr["twitter"].Mappings); count != 6 {
+			t.Errorf("Wrong field count for twitter: %d", count)
+		}
+		if count := countFields(imr["empty"].Mappings); count != 0 {
+			t.Errorf("Wrong field count for empty: %d", count)
+		}
+		if size := mappingSize(imr["empty"].Mappings); size != 2 {
+			t.Errorf("Wrong mapping size for empty: %d", size)
+		}
+		if size := mappingSize(imr["twitter"].Mappings); size <= mappingSize(imr["empty"].Mappings) {
+			t.Errorf("Wrong mapping size for twitter: %d", size)
+		}
+	}
+}
+
+func TestIndicesMappingsDeprecatedTypes(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/tweets -H 'Content-Type: application/json' -d '{"mappings":{"tweet":{"properties":{"message":{"type":"text"}}}}}' (6.8.8)
+	//  curl -XPUT http://localhost:9200/logs -H 'Content-Type: application/json' -d '{"mappings":{"_doc":{"properties":{"message":{"type":"text"}}}}}' (6.8.8)
+	//  curl -XPUT http://localhost:9200/empty
+	//  curl 'http://localhost:9200/_mapping?include_type_name=true'
+	tcs := map[string]struct {
+		typed, typeless string
+		deprecated      int
+	}{
+		"6.8.8": {
+			typed:      `{"tweets":{"mappings":{"tweet":{"_source":{"enabled":true},"properties":{"message":{"type":"text"}}}}},"logs":{"mappings":{"_doc":{"properties":{"message":{"type":"text"}}}}},"empty":{"mappings":{}}}`,
+			deprecated: 1,
+		},
+		"7.6.2": {
+			typed:      `{"tweets":{"mappings":{"tweet":{"properties":{"message":{"type":"text"}}}}},"logs":{"mappings":{"_doc":{"dynamic":"strict","properties":{"message":{"type":"text"}}}}},"empty":{"mappings":{}}}`,
+			deprecated: 1,
+		},
+		"8.1.0": {
+			typeless:   `{"logs":{"mappings":{"dynamic":"strict","_source":{"enabled":true},"properties":{"message":{"type":"text"}}}},"empty":{"mappings":{}}}`,
+			deprecated: 0,
+		},
+	}
+	for ver, tc := range tcs {
+		var typedRequests int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("include_type_name") == "true" {
+				typedRequests++
+				if tc.typed == "" {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"error":{"type":"illegal_argument_exception","reason":"request [/_mapping] contains unrecognized parameter: [include_type_name]"},"status":400}`)
+					return
+				}
+				fmt.Fprint(w, tc.typed)
+				return
+			}
+			fmt.Fprint(w, tc.typeless)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		im := NewIndicesMappings(log.NewNopLogger(), http.DefaultClient, u)
+		for i := 0; i < 2; i++ {
+			imr, err := im.fetchAndDecodeIndicesMappings()
+			if err != nil {
+				t.Fatalf("[%s] Failed to fetch or decode indices mappings: %s", ver, err)
+			}
+			var deprecated int
+			for _, indexMapping := range imr {
+				if hasDeprecatedMappingType(indexMapping.Mappings) {
+					deprecated++
+				}
+			}
+			if deprecated != tc.deprecated {
+				t.Errorf("[%s] Wrong number of indices with deprecated mapping types: %d", ver, deprecated)
+			}
+			if count := countFields(imr["logs"].Mappings); count != 1 {
+				t.Errorf("[%s] Wrong field count for logs: %d", ver, count)
+			}
+		}
+		if tc.typed == "" && typedRequests != 1 {
+			t.Errorf("[%s] Typed mappings should only be requested until rejected, got %d requests", ver, typedRequests)
+		}
+	}
+}
+
+func TestIndicesMappingsTransientFailure(t *testing.T) {
+	typed := `{"logs":{"mappings":{"_doc":{"properties":{"message":{"type":"text"}}}}}}`
+	var typedRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include_type_name") != "true" {
+			t.Errorf("Typeless mappings should not be requested after a transient failure")
+		}
+		typedRequests++
+		if typedRequests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"type":"master_not_discovered_exception","reason":null},"status":503}`)
+			return
+		}
+		fmt.Fprint(w, typed)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	im := NewIndicesMappings(log.NewNopLogger(), http.DefaultClient, u)
+	if _, err := im.fetchAndDecodeIndicesMappings(); err == nil {
+		t.Fatalf("Expected the transient failure to be returned")
+	}
+	if _, err := im.fetchAndDecodeIndicesMappings(); err != nil {
+		t.Fatalf("Failed to fetch or decode indices mappings: %s", err)
+	}
+	if typedRequests != 2 {
+		t.Errorf("Typed mappings should be requested again after a transient failure, got %d requests", typedRequests)
+	}
+}
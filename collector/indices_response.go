@@ -39,6 +39,8 @@ type IndexStatsIndexDetailResponse struct {
 	Translog     IndexStatsIndexTranslogResponse     `json:"translog"`
 	RequestCache IndexStatsIndexRequestCacheResponse `json:"request_cache"`
 	Recovery     IndexStatsIndexRecoveryResponse     `json:"recovery"`
+	// Bulk is only reported starting with 8.0
+	Bulk *IndexStatsIndexBulkResponse `json:"bulk"`
 }
 
 // IndexStatsIndexShardsDetailResponse defines index stats index shard details information structure
@@ -105,6 +107,25 @@ type IndexStatsIndexSearchResponse struct {
 	SuggestTotal        int64 `json:"suggest_total"`
 	SuggestTimeInMillis int64 `json:"suggest_time_in_millis"`
 	SuggestCurrent      int64 `json:"suggest_current"`
+
+	// SlowTotal is not reported by every version, so it is only set when present in the response
+	SlowTotal *int64 `json:"slow_total"`
+	// TimedOut and TimedOutLegacy are the timed out searches, reported as timed_out or timedout
+	// depending on the version, and only set when present in the response
+	TimedOut       *int64 `json:"timed_out"`
+	TimedOutLegacy *int64 `json:"timedout"`
+}
+
+// timedOutTotal returns the number of timed out searches under either field name and whether
+// the version reports them
+func (s IndexStatsIndexSearchResponse) timedOutTotal() (int64, bool) {
+	if s.TimedOut != nil {
+		return *s.TimedOut, true
+	}
+	if s.TimedOutLegacy != nil {
+		return *s.TimedOutLegacy, true
+	}
+	return 0, false
 }
 
 // IndexStatsIndexMergesResponse defines index stats index merges information structure
@@ -130,8 +151,9 @@ type IndexStatsIndexRefreshResponse struct {
 
 // IndexStatsIndexFlushResponse defines index stats index flush information structure
 type IndexStatsIndexFlushResponse struct {
-	Total             int64 `json:"total"`
-	TotalTimeInMillis int64 `json:"total_time_in_millis"`
+	Total             int64  `json:"total"`
+	Periodic          *int64 `json:"periodic"`
+	TotalTimeInMillis int64  `json:"total_time_in_millis"`
 }
 
 // IndexStatsIndexWarmerResponse defines index stats index warmer information structure
@@ -165,24 +187,28 @@ type IndexStatsIndexCompletionResponse struct {
 
 // IndexStatsIndexSegmentsResponse defines index stats index segments information structure
 type IndexStatsIndexSegmentsResponse struct {
-	Count                     int64 `json:"count"`
-	MemoryInBytes             int64 `json:"memory_in_bytes"`
-	TermsMemoryInBytes        int64 `json:"terms_memory_in_bytes"`
-	StoredFieldsMemoryInBytes int64 `json:"stored_fields_memory_in_bytes"`
-	TermVectorsMemoryInBytes  int64 `json:"term_vectors_memory_in_bytes"`
-	NormsMemoryInBytes        int64 `json:"norms_memory_in_bytes"`
-	PointsMemoryInBytes       int64 `json:"points_memory_in_bytes"`
-	DocValuesMemoryInBytes    int64 `json:"doc_values_memory_in_bytes"`
-	IndexWriterMemoryInBytes  int64 `json:"index_writer_memory_in_bytes"`
-	VersionMapMemoryInBytes   int64 `json:"version_map_memory_in_bytes"`
-	FixedBitSetMemoryInBytes  int64 `json:"fixed_bit_set_memory_in_bytes"`
-	MaxUnsafeAutoIDTimestamp  int64 `json:"max_unsafe_auto_id_timestamp"`
+	Count                       int64 `json:"count"`
+	MemoryInBytes               int64 `json:"memory_in_bytes"`
+	TermsMemoryInBytes          int64 `json:"terms_memory_in_bytes"`
+	StoredFieldsMemoryInBytes   int64 `json:"stored_fields_memory_in_bytes"`
+	TermVectorsMemoryInBytes    int64 `json:"term_vectors_memory_in_bytes"`
+	NormsMemoryInBytes          int64 `json:"norms_memory_in_bytes"`
+	PointsMemoryInBytes         int64 `json:"points_memory_in_bytes"`
+	DocValuesMemoryInBytes      int64 `json:"doc_values_memory_in_bytes"`
+	IndexWriterMemoryInBytes    int64 `json:"index_writer_memory_in_bytes"`
+	IndexWriterMaxMemoryInBytes int64 `json:"index_writer_max_memory_in_bytes"`
+	VersionMapMemoryInBytes     int64 `json:"version_map_memory_in_bytes"`
+	FixedBitSetMemoryInBytes    int64 `json:"fixed_bit_set_memory_in_bytes"`
+	MaxUnsafeAutoIDTimestamp    int64 `json:"max_unsafe_auto_id_timestamp"`
 }
 
 // IndexStatsIndexTranslogResponse defines index stats index translog information structure
 type IndexStatsIndexTranslogResponse struct {
-	Operations  int64 `json:"operations"`
-	SizeInBytes int64 `json:"size_in_bytes"`
+	Operations              int64 `json:"operations"`
+	SizeInBytes             int64 `json:"size_in_bytes"`
+	UncommittedOperations   int64 `json:"uncommitted_operations"`
+	UncommittedSizeInBytes  int64 `json:"uncommitted_size_in_bytes"`
+	EarliestLastModifiedAge int64 `json:"earliest_last_modified_age"`
 }
 
 // IndexStatsIndexRequestCacheResponse defines index stats index request cache information structure
@@ -199,3 +225,47 @@ type IndexStatsIndexRecoveryResponse struct {
 	CurrentAsTarget      int64 `json:"current_as_target"`
 	ThrottleTimeInMillis int64 `json:"throttle_time_in_millis"`
 }
+
+// indexSegmentsResponse is a representation of the Elasticsearch Indices Segments API
+type indexSegmentsResponse struct {
+	Indices map[string]IndexSegmentsIndexResponse `json:"indices"`
+}
+
+// IndexSegmentsIndexResponse defines the segments of the shards of an index
+type IndexSegmentsIndexResponse struct {
+	Shards map[string][]IndexSegmentsShardResponse `json:"shards"`
+}
+
+// IndexSegmentsShardResponse defines the segments of a shard copy
+type IndexSegmentsShardResponse struct {
+	Segments map[string]IndexSegmentsSegmentResponse `json:"segments"`
+}
+
+// IndexSegmentsSegmentResponse defines the information of a single segment
+type IndexSegmentsSegmentResponse struct {
+	SizeInBytes int64 `json:"size_in_bytes"`
+}
+
+// MaxSegmentSize returns the size of the largest segment of any shard copy of the index
+func (i IndexSegmentsIndexResponse) MaxSegmentSize() int64 {
+	var max int64
+	for _, shards := range i.Shards {
+		for _, shard := range shards {
+			for _, segment := range shard.Segments {
+				if segment.SizeInBytes > max {
+					max = segment.SizeInBytes
+				}
+			}
+		}
+	}
+	return max
+}
+
+// IndexStatsIndexBulkResponse defines index stats index bulk information structure
+type IndexStatsIndexBulkResponse struct {
+	TotalOperations   int64 `json:"total_operations"`
+	TotalTimeInMillis int64 `json:"total_time_in_millis"`
+	TotalSizeInBytes  int64 `json:"total_size_in_bytes"`
+	AvgTimeInMillis   int64 `json:"avg_time_in_millis"`
+	AvgSizeInBytes    int64 `json:"avg_size_in_bytes"`
+}
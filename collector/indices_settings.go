@@ -1,11 +1,11 @@
 package collector
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -14,22 +14,37 @@ import (
 
 // IndicesSettings information struct
 type IndicesSettings struct {
-	logger log.Logger
-	client *http.Client
-	url    *url.URL
+	logger    log.Logger
+	client    *http.Client
+	url       *url.URL
+	catShards *CatShardsCache
 
 	up                              prometheus.Gauge
 	readOnlyIndices                 prometheus.Gauge
+	searchThrottledIndices          prometheus.Gauge
+	totalIndices                    prometheus.Gauge
+	creationRate                    prometheus.Gauge
 	totalScrapes, jsonParseFailures prometheus.Counter
+
+	indexSearchThrottled    *prometheus.Desc
+	indexConfiguredReplicas *prometheus.Desc
+	indexAssignedReplicas   *prometheus.Desc
+	clusterLevelBlocks      *prometheus.Desc
+	clusterLevelBlockInfo   *prometheus.Desc
+
+	mu                 sync.Mutex
+	indexCountSeen     bool
+	previousIndexCount int
 }
 
 // NewIndicesSettings defines Indices Settings Prometheus metrics
-func NewIndicesSettings(logger log.Logger, client *http.Client, url *url.URL) *IndicesSettings {
+func NewIndicesSettings(logger log.Logger, client *http.Client, url *url.URL, catShards *CatShardsCache) *IndicesSettings {
 	constLabels := constLabelsFromURL(url)
 	return &IndicesSettings{
-		logger: logger,
-		client: client,
-		url:    url,
+		logger:    logger,
+		client:    client,
+		url:       url,
+		catShards: catShards,
 
 		up: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name:        prometheus.BuildFQName(namespace, "indices_settings_stats", "up"),
@@ -46,11 +61,51 @@ func NewIndicesSettings(logger log.Logger, client *http.Client, url *url.URL) *I
 			Help:        "Current number of read only indices within cluster",
 			ConstLabels: constLabels,
 		}),
+		searchThrottledIndices: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "indices_settings_stats", "search_throttled_indices"),
+			Help:        "Current number of search throttled indices within cluster",
+			ConstLabels: constLabels,
+		}),
+		totalIndices: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "indices_settings_stats", "total_indices"),
+			Help:        "Current number of indices within cluster",
+			ConstLabels: constLabels,
+		}),
+		creationRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "indices_settings_stats", "creation_rate"),
+			Help:        "Change of the number of indices since the previous scrape, negative when indices were deleted",
+			ConstLabels: constLabels,
+		}),
 		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
 			Name:        prometheus.BuildFQName(namespace, "indices_settings_stats", "json_parse_failures"),
 			Help:        "Number of errors while parsing JSON.",
 			ConstLabels: constLabels,
 		}),
+		indexSearchThrottled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index", "search_throttled"),
+			"Whether the index is search throttled (1=throttled, 0=not)",
+			[]string{"index"}, constLabels,
+		),
+		indexConfiguredReplicas: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index", "configured_replicas"),
+			"Number of replicas configured for each primary shard of the index",
+			[]string{"index"}, constLabels,
+		),
+		indexAssignedReplicas: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "index", "assigned_replicas"),
+			"Lowest number of started replicas of any primary shard of the index",
+			[]string{"index"}, constLabels,
+		),
+		clusterLevelBlocks: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "level_blocks_total"),
+			"Number of cluster level blocks applying to the operations of the block type, e.g. cluster.blocks.read_only blocks write and metadata_write",
+			[]string{"block_type"}, constLabels,
+		),
+		clusterLevelBlockInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cluster", "level_block_info"),
+			"Constant metric with the ID and description of a cluster level block and a block type it applies to as labels, e.g. to tell cluster.blocks.read_only (6) from cluster.blocks.read_only_allow_delete (13)",
+			[]string{"block_id", "description", "block_type"}, constLabels,
+		),
 	}
 }
 
@@ -59,43 +114,23 @@ func (cs *IndicesSettings) Describe(ch chan<- *prometheus.Desc) {
 	ch <- cs.up.Desc()
 	ch <- cs.totalScrapes.Desc()
 	ch <- cs.readOnlyIndices.Desc()
+	ch <- cs.searchThrottledIndices.Desc()
+	ch <- cs.totalIndices.Desc()
+	ch <- cs.creationRate.Desc()
+	ch <- cs.indexSearchThrottled
+	ch <- cs.indexConfiguredReplicas
+	ch <- cs.indexAssignedReplicas
+	ch <- cs.clusterLevelBlocks
+	ch <- cs.clusterLevelBlockInfo
 	ch <- cs.jsonParseFailures.Desc()
 }
 
-func (cs *IndicesSettings) getAndParseURL(u *url.URL, data interface{}) error {
-	res, err := cs.client.Get(u.String())
-	if err != nil {
-		return fmt.Errorf("failed to get from %s://%s:%s%s: %s",
-			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
-	}
-
-	defer func() {
-		err = res.Body.Close()
-		if err != nil {
-			_ = level.Warn(cs.logger).Log(
-				"msg", "failed to close http.Client",
-				"err", err,
-			)
-		}
-	}()
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
-	}
-
-	if err := json.NewDecoder(res.Body).Decode(data); err != nil {
-		cs.jsonParseFailures.Inc()
-		return err
-	}
-	return nil
-}
-
 func (cs *IndicesSettings) fetchAndDecodeIndicesSettings() (IndicesSettingsResponse, error) {
 
 	u := *cs.url
 	u.Path = path.Join(u.Path, "/_all/_settings")
 	var asr IndicesSettingsResponse
-	err := cs.getAndParseURL(&u, &asr)
+	err := getAndDecodeURL(cs.logger, cs.client, &u, &asr, cs.jsonParseFailures)
 	if err != nil {
 		return asr, err
 	}
@@ -103,6 +138,21 @@ func (cs *IndicesSettings) fetchAndDecodeIndicesSettings() (IndicesSettingsRespo
 	return asr, err
 }
 
+func (cs *IndicesSettings) fetchAndDecodeCatShards() (CatShardsResponse, error) {
+	return cs.catShards.fetchAndDecodeCatShards(cs.jsonParseFailures)
+}
+
+// fetchAndDecodeClusterBlocks fetches the cluster level blocks, set by cluster.blocks.* or by Elasticsearch itself
+func (cs *IndicesSettings) fetchAndDecodeClusterBlocks() (ClusterBlocksResponse, error) {
+	var cbr ClusterBlocksResponse
+
+	u := *cs.url
+	u.Path = path.Join(u.Path, "/_cluster/state/blocks")
+	u.RawQuery = "filter_path=blocks.global"
+	err := getAndDecodeURL(cs.logger, cs.client, &u, &cbr, cs.jsonParseFailures)
+	return cbr, err
+}
+
 // Collect gets all indices settings metric values
 func (cs *IndicesSettings) Collect(ch chan<- prometheus.Metric) {
 
@@ -112,11 +162,17 @@ func (cs *IndicesSettings) Collect(ch chan<- prometheus.Metric) {
 		ch <- cs.totalScrapes
 		ch <- cs.jsonParseFailures
 		ch <- cs.readOnlyIndices
+		ch <- cs.searchThrottledIndices
+		ch <- cs.totalIndices
+		ch <- cs.creationRate
 	}()
 
 	asr, err := cs.fetchAndDecodeIndicesSettings()
 	if err != nil {
 		cs.readOnlyIndices.Set(0)
+		cs.searchThrottledIndices.Set(0)
+		cs.totalIndices.Set(0)
+		cs.creationRate.Set(0)
 		cs.up.Set(0)
 		_ = level.Warn(cs.logger).Log(
 			"msg", "failed to fetch and decode cluster settings stats",
@@ -126,11 +182,94 @@ func (cs *IndicesSettings) Collect(ch chan<- prometheus.Metric) {
 	}
 	cs.up.Set(1)
 
-	var c int
-	for _, value := range asr {
+	var c, throttled int
+	for indexName, value := range asr {
 		if value.Settings.IndexInfo.Blocks.ReadOnly == "true" {
 			c++
 		}
+
+		var searchThrottled float64
+		if value.Settings.IndexInfo.Search.Throttled == "true" {
+			throttled++
+			searchThrottled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			cs.indexSearchThrottled,
+			prometheus.GaugeValue,
+			searchThrottled,
+			indexName,
+		)
+
+		if replicas, err := strconv.Atoi(value.Settings.IndexInfo.NumberOfReplicas); err == nil {
+			ch <- prometheus.MustNewConstMetric(
+				cs.indexConfiguredReplicas,
+				prometheus.GaugeValue,
+				float64(replicas),
+				indexName,
+			)
+		}
 	}
 	cs.readOnlyIndices.Set(float64(c))
+	cs.searchThrottledIndices.Set(float64(throttled))
+	cs.totalIndices.Set(float64(len(asr)))
+	cs.creationRate.Set(float64(cs.trackIndexCount(len(asr))))
+
+	// the index blocks do not cover the blocks set on the whole cluster
+	clusterBlocksResp, err := cs.fetchAndDecodeClusterBlocks()
+	if err != nil {
+		_ = level.Warn(cs.logger).Log(
+			"msg", "failed to fetch and decode cluster blocks",
+			"err", err,
+		)
+	} else {
+		for blockType, count := range clusterBlockLevelCounts(clusterBlocksResp) {
+			ch <- prometheus.MustNewConstMetric(
+				cs.clusterLevelBlocks,
+				prometheus.GaugeValue,
+				float64(count),
+				blockType,
+			)
+		}
+		for id, block := range clusterBlocksResp.Blocks.Global {
+			for _, blockType := range block.Levels {
+				ch <- prometheus.MustNewConstMetric(
+					cs.clusterLevelBlockInfo,
+					prometheus.GaugeValue,
+					1,
+					id, block.Description, blockType,
+				)
+			}
+		}
+	}
+
+	catShardsResp, err := cs.fetchAndDecodeCatShards()
+	if err != nil {
+		_ = level.Warn(cs.logger).Log(
+			"msg", "failed to fetch and decode cat shards",
+			"err", err,
+		)
+		return
+	}
+	for indexName, replicas := range assignedReplicas(catShardsResp) {
+		ch <- prometheus.MustNewConstMetric(
+			cs.indexAssignedReplicas,
+			prometheus.GaugeValue,
+			float64(replicas),
+			indexName,
+		)
+	}
+}
+
+// trackIndexCount returns the change of the number of indices since the previous scrape, 0 on the first scrape
+func (cs *IndicesSettings) trackIndexCount(indexCount int) int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	var change int
+	if cs.indexCountSeen {
+		change = indexCount - cs.previousIndexCount
+	}
+	cs.indexCountSeen = true
+	cs.previousIndexCount = indexCount
+	return change
 }
@@ -13,12 +13,96 @@ type Settings struct {
 	IndexInfo IndexInfo `json:"index"`
 }
 
-// IndexInfo defines the blocks of the current index
+// IndexInfo defines the blocks, search, replica and lifecycle settings of the current index
 type IndexInfo struct {
-	Blocks Blocks `json:"blocks"`
+	Blocks           Blocks                 `json:"blocks"`
+	Search           IndexSearchSettings    `json:"search"`
+	NumberOfReplicas string                 `json:"number_of_replicas"`
+	Lifecycle        IndexLifecycleSettings `json:"lifecycle"`
 }
 
 // Blocks defines whether current index has read_only_allow_delete enabled
 type Blocks struct {
 	ReadOnly string `json:"read_only_allow_delete"`
 }
+
+// IndexSearchSettings defines whether current index is search throttled
+type IndexSearchSettings struct {
+	Throttled string `json:"throttled"`
+}
+
+// IndexLifecycleSettings defines the lifecycle policy managing the current index, empty for unmanaged indices
+type IndexLifecycleSettings struct {
+	Name string `json:"name"`
+}
+
+// CatShardsResponse is a representation of the cat shards API
+type CatShardsResponse []CatShardResponse
+
+// CatShardResponse is a representation of a single shard copy in the cat shards API
+type CatShardResponse struct {
+	Index  string `json:"index"`
+	Shard  string `json:"shard"`
+	PriRep string `json:"prirep"`
+	State  string `json:"state"`
+	// NodeID is only reported when requested with the id column, empty for unassigned shards
+	NodeID string `json:"id"`
+	// Docs is only reported when requested with the docs column, empty for unassigned shards
+	Docs string `json:"docs"`
+}
+
+// assignedReplicas returns for every index the lowest number of started replicas of any of its shards,
+// comparable to the configured number of replicas of the index
+func assignedReplicas(shards CatShardsResponse) map[string]int {
+	type shardID struct{ index, shard string }
+	started := map[shardID]int{}
+	for _, shard := range shards {
+		id := shardID{shard.Index, shard.Shard}
+		if _, ok := started[id]; !ok {
+			started[id] = 0
+		}
+		if shard.PriRep == "r" && shard.State == "STARTED" {
+			started[id]++
+		}
+	}
+
+	replicas := map[string]int{}
+	for id, count := range started {
+		if current, ok := replicas[id.index]; !ok || count < current {
+			replicas[id.index] = count
+		}
+	}
+	return replicas
+}
+
+// ClusterBlocksResponse is a representation of the blocks of the cluster state API
+type ClusterBlocksResponse struct {
+	Blocks struct {
+		// Global holds the cluster level blocks keyed by block ID
+		Global map[string]ClusterBlockResponse `json:"global"`
+	} `json:"blocks"`
+}
+
+// ClusterBlockResponse defines a single cluster level block and the operations it blocks
+type ClusterBlockResponse struct {
+	Description string   `json:"description"`
+	Retryable   bool     `json:"retryable"`
+	Levels      []string `json:"levels"`
+}
+
+// clusterBlockLevels lists the levels a block may apply to
+var clusterBlockLevels = []string{"read", "write", "metadata_read", "metadata_write"}
+
+// clusterBlockLevelCounts returns for every level the number of cluster level blocks applying to it
+func clusterBlockLevelCounts(blocks ClusterBlocksResponse) map[string]int {
+	counts := make(map[string]int, len(clusterBlockLevels))
+	for _, level := range clusterBlockLevels {
+		counts[level] = 0
+	}
+	for _, block := range blocks.Blocks.Global {
+		for _, level := range block.Levels {
+			counts[level]++
+		}
+	}
+	return counts
+}
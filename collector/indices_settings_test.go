@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestIndicesSettings(t *testing.T) {
@@ -52,7 +55,7 @@ func TestIndicesSettings(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Failed to parse URL: %s", err)
 			}
-			c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u)
+			c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u))
 			nsr, err := c.fetchAndDecodeIndicesSettings()
 			if err != nil {
 				t.Fatalf("Failed to fetch or decode indices settings: %s", err)
@@ -76,3 +79,225 @@ func TestIndicesSettings(t *testing.T) {
 		}
 	}
 }
+
+func TestIndicesSettingsSearchThrottled(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/logs-2019
+	//  curl -XPUT http://localhost:9200/logs-2020
+	//  curl -XPOST http://localhost:9200/logs-2019/_freeze
+	//  curl http://localhost:9200/_all/_settings
+	tcs := map[string]string{
+		"7.6.2": `{"logs-2020":{"settings":{"index":{"creation_date":"1585735212345","number_of_shards":"1","number_of_replicas":"1","uuid":"nS5dCqWKQ0CTRjgIbzS3zw","version":{"created":"7060299"},"provided_name":"logs-2020"}}},"logs-2019":{"settings":{"index":{"search":{"throttled":"true"},"number_of_shards":"1","provided_name":"logs-2019","frozen":"true","creation_date":"1585735201234","number_of_replicas":"1","uuid":"o8JjyVW1RM2F1Tb5lCpPZQ","version":{"created":"7060299"}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u))
+		nsr, err := c.fetchAndDecodeIndicesSettings()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices settings: %s", err)
+		}
+		t.Logf("[%s] All Indices Settings Response: %+v", ver, nsr)
+		if nsr["logs-2019"].Settings.IndexInfo.Search.Throttled != "true" {
+			t.Errorf("logs-2019 should be search throttled")
+		}
+		if nsr["logs-2020"].Settings.IndexInfo.Search.Throttled == "true" {
+			t.Errorf("logs-2020 should not be search throttled")
+		}
+	}
+}
+
+func TestIndicesSettingsConstLabels(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_cat/shards" {
+			fmt.Fprintln(w, `[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED"},{"index":"twitter","shard":"0","prirep":"r","state":"UNASSIGNED"}]`)
+			return
+		}
+		if r.URL.Path == "/_cluster/state/blocks" {
+			fmt.Fprintln(w, `{}`)
+			return
+		}
+		fmt.Fprintln(w, `{"twitter":{"settings":{"index":{"number_of_shards":"5","blocks":{"read_only_allow_delete":"true"},"provided_name":"twitter","number_of_replicas":"1"}}}}`)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	u.User = url.UserPassword("elastic", "changeme")
+	c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u))
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	var count int
+	for metric := range ch {
+		count++
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Failed to write metric: %s", err)
+		}
+		var found bool
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "url" {
+				t.Errorf("Unexpected url label on %s", metric.Desc())
+			}
+			if label.GetName() == "cluster_url" {
+				found = true
+				if label.GetValue() != ts.URL {
+					t.Errorf("Wrong cluster_url label %q on %s", label.GetValue(), metric.Desc())
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Missing cluster_url label on %s", metric.Desc())
+		}
+	}
+	if count != 14 {
+		t.Errorf("Wrong number of metrics: %d", count)
+	}
+}
+
+func TestIndicesSettingsCreationRate(t *testing.T) {
+	c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, &url.URL{Scheme: "http", Host: "localhost:9200"}, nil)
+	for i, tc := range []struct {
+		indexCount, expected int
+	}{
+		{0, 0},
+		{10, 10},
+		{12, 2},
+		{12, 0},
+		{9, -3},
+	} {
+		if change := c.trackIndexCount(tc.indexCount); change != tc.expected {
+			t.Errorf("Wrong creation rate for scrape %d: got %d, expected %d", i, change, tc.expected)
+		}
+	}
+}
+
+func TestIndicesSettingsReplicas(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":2,"number_of_replicas":1}}'
+	//  curl -XPUT http://localhost:9200/logs -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":1,"number_of_replicas":0}}'
+	//  curl 'http://localhost:9200/_cat/shards?format=json&h=index,shard,prirep,state' (with a second node joining)
+	tcs := map[string]string{
+		"7.6.2": `[{"index":"twitter","shard":"0","prirep":"p","state":"STARTED"},{"index":"twitter","shard":"0","prirep":"r","state":"STARTED"},{"index":"twitter","shard":"1","prirep":"p","state":"STARTED"},{"index":"twitter","shard":"1","prirep":"r","state":"INITIALIZING"},{"index":"logs","shard":"0","prirep":"p","state":"STARTED"}]`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u))
+		csr, err := c.fetchAndDecodeCatShards()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cat shards: %s", err)
+		}
+		t.Logf("[%s] Cat Shards Response: %+v", ver, csr)
+		replicas := assignedReplicas(csr)
+		if replicas["twitter"] != 0 {
+			t.Errorf("Wrong assigned replicas for twitter, a replica is still initializing: %d", replicas["twitter"])
+		}
+		if replicas["logs"] != 0 {
+			t.Errorf("Wrong assigned replicas for logs: %d", replicas["logs"])
+		}
+		csr[3].State = "STARTED"
+		if replicas := assignedReplicas(csr); replicas["twitter"] != 1 {
+			t.Errorf("Wrong assigned replicas for twitter: %d", replicas["twitter"])
+		}
+	}
+}
+
+func TestIndicesSettingsClusterBlocks(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl 'http://localhost:9200/_cluster/state/blocks?filter_path=blocks.global'
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"persistent":{"cluster.blocks.read_only":true}}'
+	//  curl 'http://localhost:9200/_cluster/state/blocks?filter_path=blocks.global'
+	tcs := map[string]map[string]int{
+		`{}`: {"read": 0, "write": 0, "metadata_read": 0, "metadata_write": 0},
+		`{"blocks":{"global":{"6":{"description":"cluster read-only (api)","retryable":false,"levels":["write","metadata_write"]}}}}`: {"read": 0, "write": 1, "metadata_read": 0, "metadata_write": 1},
+	}
+	for out, expected := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u))
+		cbr, err := c.fetchAndDecodeClusterBlocks()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode cluster blocks: %s", err)
+		}
+		counts := clusterBlockLevelCounts(cbr)
+		if len(counts) != len(expected) {
+			t.Errorf("Wrong block types: %v", counts)
+		}
+		for blockType, want := range expected {
+			if counts[blockType] != want {
+				t.Errorf("Wrong number of %s blocks for %s: got %d, expected %d", blockType, out, counts[blockType], want)
+			}
+		}
+	}
+}
+
+func TestIndicesSettingsClusterBlockInfo(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"persistent":{"cluster.blocks.read_only_allow_delete":true}}'
+	//  curl 'http://localhost:9200/_cluster/state/blocks?filter_path=blocks.global'
+	ts, u := newFixtureServer(t, map[string]string{
+		"/_all/_settings":        `{}`,
+		"/_cat/shards":           `[]`,
+		"/_cluster/state/blocks": `{"blocks":{"global":{"13":{"description":"cluster read-only / allow delete (api)","retryable":false,"levels":["write","metadata_write"]}}}}`,
+	})
+	defer ts.Close()
+	c := NewIndicesSettings(log.NewNopLogger(), http.DefaultClient, u, NewCatShardsCache(log.NewNopLogger(), http.DefaultClient, u))
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+
+	blockTypes := map[string]bool{}
+	for metric := range ch {
+		if !strings.Contains(metric.Desc().String(), `"elasticsearch_cluster_level_block_info"`) {
+			continue
+		}
+		m := &dto.Metric{}
+		if err := metric.Write(m); err != nil {
+			t.Fatalf("Failed to write metric: %s", err)
+		}
+		labels := map[string]string{}
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["block_id"] != "13" || labels["description"] != "cluster read-only / allow delete (api)" {
+			t.Errorf("Wrong cluster level block labels: %v", labels)
+		}
+		blockTypes[labels["block_type"]] = true
+	}
+	if len(blockTypes) != 2 || !blockTypes["write"] || !blockTypes["metadata_write"] {
+		t.Errorf("Wrong block types of the cluster level block: %v", blockTypes)
+	}
+}
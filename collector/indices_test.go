@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func TestIndices(t *testing.T) {
@@ -35,7 +38,7 @@ func TestIndices(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to parse URL: %s", err)
 		}
-		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false)
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, false)
 		stats, err := i.fetchAndDecodeIndexStats()
 		if err != nil {
 			t.Fatalf("Failed to fetch or decode indices stats: %s", err)
@@ -56,5 +59,402 @@ func TestIndices(t *testing.T) {
 		if stats.Indices["foo_1"].Total.Indexing.IndexTotal == 0 {
 			t.Errorf("Wrong indexing total recorded")
 		}
+		if ver == "5.5.2" {
+			// foo_1 has one replica, so all shards use twice the primary store size
+			primaryStoreSize := stats.Indices["foo_1"].Primaries.Store.SizeInBytes
+			totalStoreSize := stats.Indices["foo_1"].Total.Store.SizeInBytes
+			if primaryStoreSize != 8246 || totalStoreSize != 16492 {
+				t.Errorf("Wrong primary or total store size in bytes")
+			}
+		}
+		// the aggregated cache sizes match the sum over all indices
+		var queryCache, requestCache, fielddata int64
+		for _, indexStats := range stats.Indices {
+			queryCache += indexStats.Total.QueryCache.MemorySizeInBytes
+			requestCache += indexStats.Total.RequestCache.MemorySizeInBytes
+			fielddata += indexStats.Total.Fielddata.MemorySizeInBytes
+		}
+		expected := []float64{float64(queryCache), float64(requestCache), float64(fielddata)}
+		for n, metric := range i.allIndicesMetrics[:len(expected)] {
+			if v := metric.Value(stats.All); v != expected[n] {
+				t.Errorf("Wrong value for aggregated cache metric %d: got %v, expected %v", n, v, expected[n])
+			}
+		}
+		if stats.Indices["foo_1"].Total.Search.SlowTotal != nil {
+			t.Errorf("Slow search total should be nil when not reported")
+		}
+		if ver == "1.7.6" {
+			if stats.Indices["foo_1"].Primaries.Segments.IndexWriterMaxMemoryInBytes != 335544320 {
+				t.Errorf("Wrong index writer max memory recorded")
+			}
+		}
+	}
+}
+
+func TestIndicesTranslog(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/foo_1 -H 'Content-Type: application/json' -d '{"settings":{"index.translog.flush_threshold_size":"1gb"}}'
+	//  curl -XPOST http://localhost:9200/foo_1/_bulk -H 'Content-Type: application/x-ndjson' --data-binary @docs.ndjson
+	//  curl http://localhost:9200/_all/_stats/translog
+	tcs := map[string]string{
+		"7.6.2": `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"translog":{"operations":1200,"size_in_bytes":524288,"uncommitted_operations":800,"uncommitted_size_in_bytes":393216,"earliest_last_modified_age":90000}},"total":{"translog":{"operations":1200,"size_in_bytes":524288,"uncommitted_operations":800,"uncommitted_size_in_bytes":393216,"earliest_last_modified_age":90000}}},"indices":{"foo_1":{"uuid":"Y8Bdvpq2T3iSGBxC4xS2Fw","primaries":{"translog":{"operations":1200,"size_in_bytes":524288,"uncommitted_operations":800,"uncommitted_size_in_bytes":393216,"earliest_last_modified_age":90000}},"total":{"translog":{"operations":1200,"size_in_bytes":524288,"uncommitted_operations":800,"uncommitted_size_in_bytes":393216,"earliest_last_modified_age":90000}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, false)
+		stats, err := i.fetchAndDecodeIndexStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices stats: %s", err)
+		}
+		t.Logf("[%s] Index Translog Response: %+v", ver, stats)
+		translog := stats.Indices["foo_1"].Total.Translog
+		if translog.Operations != 1200 || translog.SizeInBytes != 524288 {
+			t.Errorf("Wrong translog operations or size")
+		}
+		if translog.UncommittedOperations != 800 || translog.UncommittedSizeInBytes != 393216 {
+			t.Errorf("Wrong uncommitted translog operations or size")
+		}
+		if translog.EarliestLastModifiedAge != 90000 {
+			t.Errorf("Wrong translog earliest last modified age")
+		}
+	}
+}
+
+func TestIndicesMergeThrottle(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"transient":{"indices.store.throttle.max_bytes_per_sec":"1mb"}}'
+	//  curl -XPOST http://localhost:9200/foo_1/_bulk -H 'Content-Type: application/x-ndjson' --data-binary @docs.ndjson
+	//  curl http://localhost:9200/_all/_stats/merge
+	tcs := map[string]string{
+		"7.6.2": `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"merges":{"current":0,"current_docs":0,"current_size_in_bytes":0,"total":12,"total_time_in_millis":45000,"total_docs":250000,"total_size_in_bytes":104857600,"total_stopped_time_in_millis":0,"total_throttled_time_in_millis":30000,"total_auto_throttle_in_bytes":20971520}},"total":{"merges":{"current":0,"current_docs":0,"current_size_in_bytes":0,"total":12,"total_time_in_millis":45000,"total_docs":250000,"total_size_in_bytes":104857600,"total_stopped_time_in_millis":0,"total_throttled_time_in_millis":30000,"total_auto_throttle_in_bytes":20971520}}},"indices":{"foo_1":{"uuid":"Y8Bdvpq2T3iSGBxC4xS2Fw","primaries":{"merges":{"current":0,"current_docs":0,"current_size_in_bytes":0,"total":12,"total_time_in_millis":45000,"total_docs":250000,"total_size_in_bytes":104857600,"total_stopped_time_in_millis":0,"total_throttled_time_in_millis":30000,"total_auto_throttle_in_bytes":20971520}},"total":{"merges":{"current":0,"current_docs":0,"current_size_in_bytes":0,"total":12,"total_time_in_millis":45000,"total_docs":250000,"total_size_in_bytes":104857600,"total_stopped_time_in_millis":0,"total_throttled_time_in_millis":30000,"total_auto_throttle_in_bytes":20971520}}}}}`,
+	}
+	expected := map[string]float64{
+		"elasticsearch_index_stats_merge_time_seconds_total":          45,
+		"elasticsearch_index_stats_merge_throttle_time_seconds_total": 30,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, false)
+		stats, err := i.fetchAndDecodeIndexStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices stats: %s", err)
+		}
+		t.Logf("[%s] Index Merges Response: %+v", ver, stats)
+		found := 0
+		for _, metric := range i.indexMetrics {
+			for name, value := range expected {
+				if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+					continue
+				}
+				found++
+				if v := metric.Value(stats.Indices["foo_1"]); v != value {
+					t.Errorf("Wrong value for %s: got %v, expected %v", name, v, value)
+				}
+			}
+		}
+		if found != len(expected) {
+			t.Errorf("Expected %d merge time metrics, found %d", len(expected), found)
+		}
+	}
+}
+
+func TestIndicesAggregateOnly(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/foo_1/_bulk -H 'Content-Type: application/x-ndjson' --data-binary @docs.ndjson
+	//  curl "http://localhost:9200/_all/_stats?filter_path=_all.total"
+	tcs := map[string]string{
+		"7.6.2": `{"_all":{"total":{"docs":{"count":1200,"deleted":3},"store":{"size_in_bytes":5242880},"indexing":{"index_total":1203,"index_time_in_millis":950,"index_current":0,"index_failed":0,"delete_total":0,"delete_time_in_millis":0,"delete_current":0,"noop_update_total":0,"is_throttled":false,"throttle_time_in_millis":0},"search":{"open_contexts":0,"query_total":40,"query_time_in_millis":2500,"query_current":0,"fetch_total":40,"fetch_time_in_millis":120,"fetch_current":0,"scroll_total":0,"scroll_time_in_millis":0,"scroll_current":0,"suggest_total":0,"suggest_time_in_millis":0,"suggest_current":0},"query_cache":{"memory_size_in_bytes":1024},"fielddata":{"memory_size_in_bytes":2048},"request_cache":{"memory_size_in_bytes":4096}}}}`,
+	}
+	expected := map[string]float64{
+		"elasticsearch_index_stats_all_docs":                            1200,
+		"elasticsearch_index_stats_all_store_size_bytes":                5242880,
+		"elasticsearch_index_stats_all_indexing_index_total":            1203,
+		"elasticsearch_index_stats_all_search_query_total":              40,
+		"elasticsearch_index_stats_all_search_query_time_seconds_total": 2.5,
+		"elasticsearch_index_stats_all_query_cache_memory_bytes":        1024,
+		"elasticsearch_index_stats_all_fielddata_memory_bytes":          2048,
+		"elasticsearch_index_stats_all_request_cache_memory_bytes":      4096,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RawQuery != "filter_path=_all.total" {
+				t.Errorf("[%s] Per index stats should not be requested, got query %q", ver, r.URL.RawQuery)
+			}
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, true, true, false)
+		stats, err := i.fetchAndDecodeIndexStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices stats: %s", err)
+		}
+		t.Logf("[%s] Aggregated Index Stats Response: %+v", ver, stats)
+		if len(stats.Indices) != 0 {
+			t.Errorf("Expected no per index stats, got %d indices", len(stats.Indices))
+		}
+		found := 0
+		for _, metric := range i.allIndicesMetrics {
+			for name, value := range expected {
+				if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+					continue
+				}
+				found++
+				if v := metric.Value(stats.All); v != value {
+					t.Errorf("Wrong value for %s: got %v, expected %v", name, v, value)
+				}
+			}
+		}
+		if found != len(expected) {
+			t.Errorf("Expected %d aggregated metrics, found %d", len(expected), found)
+		}
+	}
+}
+
+func TestIndicesVerboseSegments(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/foo_1 -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":2,"number_of_replicas":0}}'
+	//  curl -XPOST http://localhost:9200/foo_1/_bulk -H 'Content-Type: application/x-ndjson' --data-binary @docs.ndjson
+	//  curl http://localhost:9200/_all/_segments
+	tcs := map[string]string{
+		"7.6.2": `{"_shards":{"total":2,"successful":2,"failed":0},"indices":{"foo_1":{"shards":{"0":[{"routing":{"state":"STARTED","primary":true,"node":"9_P7yui4SQOkzGhTZCyjxQ"},"num_committed_segments":2,"num_search_segments":2,"segments":{"_0":{"generation":0,"num_docs":1000,"deleted_docs":0,"size_in_bytes":52428800,"memory_in_bytes":4096,"committed":true,"search":true,"version":"8.4.0","compound":false},"_1":{"generation":1,"num_docs":10,"deleted_docs":0,"size_in_bytes":10240,"memory_in_bytes":1024,"committed":true,"search":true,"version":"8.4.0","compound":true}}}],"1":[{"routing":{"state":"STARTED","primary":true,"node":"9_P7yui4SQOkzGhTZCyjxQ"},"num_committed_segments":1,"num_search_segments":1,"segments":{"_0":{"generation":0,"num_docs":2000,"deleted_docs":0,"size_in_bytes":104857600,"memory_in_bytes":8192,"committed":true,"search":true,"version":"8.4.0","compound":false}}}]}},"foo_2":{"shards":{"0":[{"routing":{"state":"STARTED","primary":true,"node":"9_P7yui4SQOkzGhTZCyjxQ"},"num_committed_segments":0,"num_search_segments":0,"segments":{}}]}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, true)
+		segments, err := i.fetchAndDecodeIndexSegments()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode index segments: %s", err)
+		}
+		t.Logf("[%s] Index Segments Response: %+v", ver, segments)
+		if v := segments.Indices["foo_1"].MaxSegmentSize(); v != 104857600 {
+			t.Errorf("Wrong max segment size of foo_1: %d", v)
+		}
+		if v := segments.Indices["foo_2"].MaxSegmentSize(); v != 0 {
+			t.Errorf("Wrong max segment size of empty index foo_2: %d", v)
+		}
+
+		// the average is derived from the store size, not from the segment memory
+		var indexStats IndexStatsIndexResponse
+		indexStats.Total.Store.SizeInBytes = 3000
+		indexStats.Total.Segments.Count = 3
+		indexStats.Total.Segments.MemoryInBytes = 30
+		for _, metric := range i.indexMetrics {
+			if !strings.Contains(metric.Desc.String(), `"elasticsearch_index_average_segment_size_bytes"`) {
+				continue
+			}
+			if v := metric.Value(indexStats); v != 1000 {
+				t.Errorf("Wrong average segment size: %v", v)
+			}
+		}
+	}
+}
+
+func TestIndicesBulk(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/foo_1/_bulk -H 'Content-Type: application/x-ndjson' --data-binary @docs.ndjson
+	//  curl http://localhost:9200/_all/_stats/bulk
+	tcs := map[string]string{
+		"7.6.2": `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{},"total":{}},"indices":{"foo_1":{"uuid":"2spCyo1pRi2Ajo-j-_dnPX","primaries":{},"total":{}}}}`,
+		"8.1.0": `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"bulk":{"total_operations":40,"total_time_in_millis":320,"total_size_in_bytes":409600,"avg_time_in_millis":8,"avg_size_in_bytes":10240}},"total":{"bulk":{"total_operations":80,"total_time_in_millis":640,"total_size_in_bytes":819200,"avg_time_in_millis":8,"avg_size_in_bytes":10240}}},"indices":{"foo_1":{"uuid":"2spCyo1pRi2Ajo-j-_dnPX","health":"yellow","status":"open","primaries":{"bulk":{"total_operations":40,"total_time_in_millis":320,"total_size_in_bytes":409600,"avg_time_in_millis":8,"avg_size_in_bytes":10240}},"total":{"bulk":{"total_operations":80,"total_time_in_millis":640,"total_size_in_bytes":819200,"avg_time_in_millis":8,"avg_size_in_bytes":10240}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, false)
+		stats, err := i.fetchAndDecodeIndexStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices stats: %s", err)
+		}
+		t.Logf("[%s] Index Bulk Response: %+v", ver, stats)
+		if ver == "7.6.2" {
+			if stats.All.Total.Bulk != nil {
+				t.Errorf("Unexpected bulk stats before 8.0")
+			}
+			continue
+		}
+		if stats.All.Total.Bulk == nil {
+			t.Fatalf("Missing bulk stats")
+		}
+		expected := []float64{80, 819200, 10240}
+		for idx, metric := range i.allBulkMetrics {
+			if v := metric.Value(stats.All); v != expected[idx] {
+				t.Errorf("Wrong value for bulk metric %d: got %v, expected %v", idx, v, expected[idx])
+			}
+		}
+	}
+}
+
+func TestIndicesSearchTimedOut(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl 'http://localhost:9200/foo_1/_search?timeout=1ms' (repeatedly, under load)
+	//  curl http://localhost:9200/_all/_stats/search
+	tcs := map[string]string{
+		"7.6.2":     `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480}},"total":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480}}},"indices":{"foo_1":{"uuid":"2spCyo1pRi2Ajo-j-_dnPX","primaries":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480}},"total":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480}}}}}`,
+		"timedout":  `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timedout":7}},"total":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timedout":7}}},"indices":{"foo_1":{"uuid":"2spCyo1pRi2Ajo-j-_dnPX","primaries":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timedout":7}},"total":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timedout":7}}}}}`,
+		"timed_out": `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timed_out":7}},"total":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timed_out":7}}},"indices":{"foo_1":{"uuid":"2spCyo1pRi2Ajo-j-_dnPX","primaries":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timed_out":7}},"total":{"search":{"open_contexts":0,"query_total":120,"query_time_in_millis":480,"timed_out":7}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, false)
+		stats, err := i.fetchAndDecodeIndexStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices stats: %s", err)
+		}
+		t.Logf("[%s] Index Search Response: %+v", ver, stats)
+		timedOut, ok := stats.Indices["foo_1"].Total.Search.timedOutTotal()
+		if ver == "7.6.2" {
+			if ok {
+				t.Errorf("Unexpected timed out searches when not reported")
+			}
+			continue
+		}
+		if !ok || timedOut != 7 {
+			t.Errorf("[%s] Wrong number of timed out searches: %d", ver, timedOut)
+		}
+		if v := i.searchTimedOut.Value(stats.Indices["foo_1"]); v != 7 {
+			t.Errorf("[%s] Wrong value for timed out search metric: %v", ver, v)
+		}
+	}
+}
+
+func TestIndicesFlushPeriodic(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/foo_1/_settings -H 'Content-Type: application/json' -d '{"index.translog.flush_threshold_size":"1mb"}'
+	//  curl -XPOST http://localhost:9200/foo_1/_bulk (repeatedly) and curl -XPOST http://localhost:9200/foo_1/_flush
+	//  curl http://localhost:9200/_all/_stats/flush
+	tcs := map[string]string{
+		"6.2.4":  `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"flush":{"total":12,"total_time_in_millis":340}},"total":{"flush":{"total":12,"total_time_in_millis":340}}},"indices":{"foo_1":{"primaries":{"flush":{"total":12,"total_time_in_millis":340}},"total":{"flush":{"total":12,"total_time_in_millis":340}}}}}`,
+		"7.10.2": `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"flush":{"total":12,"periodic":9,"total_time_in_millis":340}},"total":{"flush":{"total":12,"periodic":9,"total_time_in_millis":340}}},"indices":{"foo_1":{"uuid":"2spCyo1pRi2Ajo-j-_dnPX","primaries":{"flush":{"total":12,"periodic":9,"total_time_in_millis":340}},"total":{"flush":{"total":12,"periodic":9,"total_time_in_millis":340}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, false)
+		stats, err := i.fetchAndDecodeIndexStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode indices stats: %s", err)
+		}
+		t.Logf("[%s] Index Flush Response: %+v", ver, stats)
+		flush := stats.Indices["foo_1"].Total.Flush
+		if flush.Total != 12 || flush.TotalTimeInMillis != 340 {
+			t.Errorf("[%s] Wrong flush stats: %+v", ver, flush)
+		}
+		if ver == "6.2.4" {
+			if flush.Periodic != nil {
+				t.Errorf("Unexpected periodic flushes when not reported")
+			}
+			continue
+		}
+		if v := i.flushPeriodic.Value(stats.Indices["foo_1"]); v != 9 {
+			t.Errorf("[%s] Wrong value for periodic flush metric: %v", ver, v)
+		}
+	}
+}
+
+func TestIndicesBuffered(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/foo_1/_doc/1 -H 'Content-Type: application/json' -d '{"title":"abc"}'
+	//  curl http://localhost:9200/_all/_stats
+	out := `{"_shards":{"total":2,"successful":1,"failed":0},"_all":{"primaries":{"docs":{"count":1,"deleted":0}},"total":{"docs":{"count":1,"deleted":0}}},"indices":{"foo_1":{"uuid":"2spCyo1pRi2Ajo-j-_dnPX","primaries":{"docs":{"count":1,"deleted":0}},"total":{"docs":{"count":1,"deleted":0}}}}}`
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintln(w, out)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	i := NewIndices(log.NewNopLogger(), http.DefaultClient, u, false, false, false)
+	collect := func() int {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			i.Collect(ch)
+			close(ch)
+		}()
+		var count int
+		for range ch {
+			count++
+		}
+		return count
+	}
+
+	// without a buffer the cluster is queried on every scrape
+	live := collect()
+	if requests != 1 {
+		t.Fatalf("Expected 1 request for a live collect, got %d", requests)
+	}
+
+	i.buffer.refresh(time.Now())
+	requests = 0
+	for n := 0; n < 2; n++ {
+		// the buffered metrics are served with the last refresh time
+		if count := collect(); count != live+1 {
+			t.Errorf("Wrong number of buffered metrics: got %d, expected %d", count, live+1)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Buffered collects should not query the cluster, got %d requests", requests)
 	}
 }
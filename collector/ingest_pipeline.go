@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type ingestPipelineMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(pipelineStats IngestPipelineStatsResponse) float64
+	Labels func(pipeline string) []string
+}
+
+var (
+	defaultIngestPipelineLabels      = []string{"pipeline"}
+	defaultIngestPipelineLabelValues = func(pipeline string) []string {
+		return []string{pipeline}
+	}
+)
+
+// IngestPipeline information struct
+type IngestPipeline struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	pipelineMetrics []*ingestPipelineMetric
+	docsPerSecond   *prometheus.Desc
+
+	mu            sync.Mutex
+	lastPipelines map[string]IngestPipelineStatsResponse
+}
+
+// NewIngestPipeline defines Ingest Pipeline Prometheus metrics, the stats of each pipeline are summed up across all nodes
+func NewIngestPipeline(logger log.Logger, client *http.Client, url *url.URL) *IngestPipeline {
+	constLabels := constLabelsFromURL(url)
+	return &IngestPipeline{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "ingest_pipeline", "up"),
+			Help:        "Was the last scrape of the ElasticSearch ingest stats endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ingest_pipeline", "total_scrapes"),
+			Help:        "Current total ElasticSearch ingest stats scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ingest_pipeline", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		docsPerSecond: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ingest_pipeline", "docs_per_second"),
+			"Documents processed by the ingest pipeline per second of processing time since the previous scrape",
+			defaultIngestPipelineLabels, constLabels,
+		),
+		pipelineMetrics: []*ingestPipelineMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ingest_pipeline", "docs_total"),
+					"Number of documents processed by the ingest pipeline",
+					defaultIngestPipelineLabels, constLabels,
+				),
+				Value: func(pipelineStats IngestPipelineStatsResponse) float64 {
+					return float64(pipelineStats.Count)
+				},
+				Labels: defaultIngestPipelineLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ingest_pipeline", "failed_total"),
+					"Number of documents the ingest pipeline failed to process",
+					defaultIngestPipelineLabels, constLabels,
+				),
+				Value: func(pipelineStats IngestPipelineStatsResponse) float64 {
+					return float64(pipelineStats.Failed)
+				},
+				Labels: defaultIngestPipelineLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ingest_pipeline", "time_seconds_total"),
+					"Time spent processing documents in the ingest pipeline in seconds",
+					defaultIngestPipelineLabels, constLabels,
+				),
+				Value: func(pipelineStats IngestPipelineStatsResponse) float64 {
+					return float64(pipelineStats.TimeInMillis) / 1000
+				},
+				Labels: defaultIngestPipelineLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ingest_pipeline", "failure_rate"),
+					"Proportion of the documents processed by the ingest pipeline which failed",
+					defaultIngestPipelineLabels, constLabels,
+				),
+				Value: func(pipelineStats IngestPipelineStatsResponse) float64 {
+					return pipelineStats.FailureRate()
+				},
+				Labels: defaultIngestPipelineLabelValues,
+			},
+		},
+	}
+}
+
+// Describe add Ingest Pipeline metrics descriptions
+func (i *IngestPipeline) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range i.pipelineMetrics {
+		ch <- metric.Desc
+	}
+	ch <- i.docsPerSecond
+	ch <- i.up.Desc()
+	ch <- i.totalScrapes.Desc()
+	ch <- i.jsonParseFailures.Desc()
+}
+
+func (i *IngestPipeline) fetchAndDecodeIngestStats() (IngestPipelineNodesStatsResponse, error) {
+	var insr IngestPipelineNodesStatsResponse
+
+	u := *i.url
+	u.Path = path.Join(u.Path, "/_nodes/stats/ingest")
+	err := getAndDecodeURL(i.logger, i.client, &u, &insr, i.jsonParseFailures)
+	return insr, err
+}
+
+// Collect gets Ingest Pipeline metric values
+func (i *IngestPipeline) Collect(ch chan<- prometheus.Metric) {
+	i.totalScrapes.Inc()
+	defer func() {
+		ch <- i.up
+		ch <- i.totalScrapes
+		ch <- i.jsonParseFailures
+	}()
+
+	ingestStatsResp, err := i.fetchAndDecodeIngestStats()
+	if err != nil {
+		i.up.Set(0)
+		_ = level.Warn(i.logger).Log(
+			"msg", "failed to fetch and decode ingest stats",
+			"err", err,
+		)
+		return
+	}
+	i.up.Set(1)
+
+	pipelines := ingestStatsResp.pipelines()
+
+	i.mu.Lock()
+	lastPipelines := i.lastPipelines
+	i.lastPipelines = pipelines
+	i.mu.Unlock()
+
+	for pipeline, pipelineStats := range pipelines {
+		for _, metric := range i.pipelineMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(pipelineStats),
+				metric.Labels(pipeline)...,
+			)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			i.docsPerSecond,
+			prometheus.GaugeValue,
+			pipelineStats.DocsPerSecond(lastPipelines[pipeline]),
+			defaultIngestPipelineLabelValues(pipeline)...,
+		)
+	}
+}
@@ -0,0 +1,58 @@
+package collector
+
+// IngestPipelineNodesStatsResponse is a representation of the ingest stats of all nodes
+type IngestPipelineNodesStatsResponse struct {
+	Nodes map[string]IngestPipelineNodeStatsResponse `json:"nodes"`
+}
+
+// IngestPipelineNodeStatsResponse is a representation of the ingest stats of a single node
+type IngestPipelineNodeStatsResponse struct {
+	Ingest struct {
+		Pipelines map[string]IngestPipelineStatsResponse `json:"pipelines"`
+	} `json:"ingest"`
+}
+
+// IngestPipelineStatsResponse is a representation of the stats of a single ingest pipeline
+type IngestPipelineStatsResponse struct {
+	Count        int64 `json:"count"`
+	TimeInMillis int64 `json:"time_in_millis"`
+	Current      int64 `json:"current"`
+	Failed       int64 `json:"failed"`
+}
+
+// pipelines sums up the stats of every ingest pipeline across all nodes
+func (r IngestPipelineNodesStatsResponse) pipelines() map[string]IngestPipelineStatsResponse {
+	pipelines := make(map[string]IngestPipelineStatsResponse)
+	for _, node := range r.Nodes {
+		for name, stats := range node.Ingest.Pipelines {
+			total := pipelines[name]
+			total.Count += stats.Count
+			total.TimeInMillis += stats.TimeInMillis
+			total.Current += stats.Current
+			total.Failed += stats.Failed
+			pipelines[name] = total
+		}
+	}
+	return pipelines
+}
+
+// FailureRate returns the proportion of documents the pipeline failed to process, 0 if it processed none
+func (s IngestPipelineStatsResponse) FailureRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Count)
+}
+
+// DocsPerSecond returns the number of documents processed per second of pipeline processing time
+// since prev, 0 if the pipeline spent no time processing
+func (s IngestPipelineStatsResponse) DocsPerSecond(prev IngestPipelineStatsResponse) float64 {
+	// the stats restart from 0 when a node restarts
+	if s.Count < prev.Count || s.TimeInMillis < prev.TimeInMillis {
+		prev = IngestPipelineStatsResponse{}
+	}
+	if s.TimeInMillis == prev.TimeInMillis {
+		return 0
+	}
+	return float64(s.Count-prev.Count) / (float64(s.TimeInMillis-prev.TimeInMillis) / 1000)
+}
@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestIngestPipeline(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_ingest/pipeline/parse-logs -H 'Content-Type: application/json' -d '{"processors":[{"grok":{"field":"message","patterns":["%{IP:client} %{WORD:method}"]}}]}'
+	//  curl -XPUT http://localhost:9200/_ingest/pipeline/unused -H 'Content-Type: application/json' -d '{"processors":[{"set":{"field":"foo","value":"bar"}}]}'
+	//  curl -XPOST 'http://localhost:9200/logs/_doc?pipeline=parse-logs' -H 'Content-Type: application/json' -d '{"message":"127.0.0.1 GET"}' (repeated, some without a matching message)
+	//  curl http://localhost:9200/_nodes/stats/ingest
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":2,"successful":2,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","ingest":{"total":{"count":150,"time_in_millis":300,"current":0,"failed":3},"pipelines":{"parse-logs":{"count":150,"time_in_millis":300,"current":0,"failed":3,"processors":[{"grok":{"type":"grok","stats":{"count":150,"time_in_millis":280,"current":0,"failed":3}}}]},"unused":{"count":0,"time_in_millis":0,"current":0,"failed":0,"processors":[{"set":{"type":"set","stats":{"count":0,"time_in_millis":0,"current":0,"failed":0}}}]}}}},"yYpjx5JnT4yeRAzkb6M5cg":{"timestamp":1585735200000,"name":"node-1","ingest":{"total":{"count":50,"time_in_millis":100,"current":1,"failed":1},"pipelines":{"parse-logs":{"count":50,"time_in_millis":100,"current":1,"failed":1,"processors":[{"grok":{"type":"grok","stats":{"count":50,"time_in_millis":90,"current":1,"failed":1}}}]}}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		i := NewIngestPipeline(log.NewNopLogger(), http.DefaultClient, u)
+		insr, err := i.fetchAndDecodeIngestStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode ingest stats: %s", err)
+		}
+		t.Logf("[%s] Ingest Stats Response: %+v", ver, insr)
+		pipelines := insr.pipelines()
+		if len(pipelines) != 2 {
+			t.Fatalf("Wrong number of pipelines: %d", len(pipelines))
+		}
+
+		// docs_total, failed_total, time_seconds_total, failure_rate
+		expected := []float64{200, 4, 0.4, 0.02}
+		for n, metric := range i.pipelineMetrics {
+			if v := metric.Value(pipelines["parse-logs"]); v != expected[n] {
+				t.Errorf("Wrong value for ingest pipeline metric %d: got %v, expected %v", n, v, expected[n])
+			}
+		}
+		if v := pipelines["parse-logs"].DocsPerSecond(IngestPipelineStatsResponse{}); v != 500 {
+			t.Errorf("Wrong docs per second since start: %v", v)
+		}
+		prev := IngestPipelineStatsResponse{Count: 100, TimeInMillis: 300}
+		if v := pipelines["parse-logs"].DocsPerSecond(prev); v != 1000 {
+			t.Errorf("Wrong docs per second since the previous scrape: %v", v)
+		}
+		prev = IngestPipelineStatsResponse{Count: 1000, TimeInMillis: 5000}
+		if v := pipelines["parse-logs"].DocsPerSecond(prev); v != 500 {
+			t.Errorf("Wrong docs per second after a node restart: %v", v)
+		}
+
+		// a pipeline which has not processed any document must not report NaN
+		unused := pipelines["unused"]
+		if v := unused.FailureRate(); v != 0 {
+			t.Errorf("Wrong failure rate for unused pipeline: %v", v)
+		}
+		if v := unused.DocsPerSecond(IngestPipelineStatsResponse{}); v != 0 {
+			t.Errorf("Wrong docs per second for unused pipeline: %v", v)
+		}
+	}
+}
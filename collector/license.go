@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// License information struct
+type License struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	info                *prometheus.Desc
+	expiryTimestamp     *prometheus.Desc
+	expiryDaysRemaining *prometheus.Desc
+}
+
+// NewLicense defines License Prometheus metrics
+func NewLicense(logger log.Logger, client *http.Client, url *url.URL) *License {
+	constLabels := constLabelsFromURL(url)
+	return &License{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "license", "up"),
+			Help:        "Was the last scrape of the ElasticSearch license endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "license", "total_scrapes"),
+			Help:        "Current total ElasticSearch license scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "license", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		info: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "info"),
+			"License of the cluster, always 1",
+			[]string{"type", "status", "issued_to"}, constLabels,
+		),
+		expiryTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "expiry_timestamp_seconds"),
+			"Expiry date of the license in unix time",
+			nil, constLabels,
+		),
+		expiryDaysRemaining: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "license", "expiry_days_remaining"),
+			"Days until the license expires, negative when expired and +Inf when the license never expires",
+			nil, constLabels,
+		),
+	}
+}
+
+// Describe add License metrics descriptions
+func (l *License) Describe(ch chan<- *prometheus.Desc) {
+	ch <- l.info
+	ch <- l.expiryTimestamp
+	ch <- l.expiryDaysRemaining
+	ch <- l.up.Desc()
+	ch <- l.totalScrapes.Desc()
+	ch <- l.jsonParseFailures.Desc()
+}
+
+func (l *License) fetchAndDecodeLicense() (LicenseResponse, error) {
+	var lr LicenseResponse
+
+	u := *l.url
+	u.Path = path.Join(u.Path, "/_license")
+	err := getAndDecodeURL(l.logger, l.client, &u, &lr, l.jsonParseFailures)
+	return lr, err
+}
+
+// licenseExpiryDaysRemaining returns the days left until the license expires at the given time
+func licenseExpiryDaysRemaining(license LicenseDataResponse, now time.Time) float64 {
+	if license.ExpiryDateInMillis == nil {
+		return math.Inf(1)
+	}
+	return (float64(*license.ExpiryDateInMillis)/1000 - float64(now.UnixNano())/1e9) / 86400
+}
+
+// Collect gets License metric values
+func (l *License) Collect(ch chan<- prometheus.Metric) {
+	l.totalScrapes.Inc()
+	defer func() {
+		ch <- l.up
+		ch <- l.totalScrapes
+		ch <- l.jsonParseFailures
+	}()
+
+	licenseResp, err := l.fetchAndDecodeLicense()
+	if err != nil {
+		l.up.Set(0)
+		_ = level.Warn(l.logger).Log(
+			"msg", "failed to fetch and decode license",
+			"err", err,
+		)
+		return
+	}
+	l.up.Set(1)
+
+	license := licenseResp.License
+	ch <- prometheus.MustNewConstMetric(
+		l.info,
+		prometheus.GaugeValue,
+		1,
+		license.Type, license.Status, license.IssuedTo,
+	)
+	if license.ExpiryDateInMillis != nil {
+		ch <- prometheus.MustNewConstMetric(
+			l.expiryTimestamp,
+			prometheus.GaugeValue,
+			float64(*license.ExpiryDateInMillis)/1000,
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		l.expiryDaysRemaining,
+		prometheus.GaugeValue,
+		licenseExpiryDaysRemaining(license, time.Now()),
+	)
+}
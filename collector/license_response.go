@@ -0,0 +1,19 @@
+package collector
+
+// LicenseResponse is a representation of the Elasticsearch license
+type LicenseResponse struct {
+	License LicenseDataResponse `json:"license"`
+}
+
+// LicenseDataResponse is a representation of the license details
+type LicenseDataResponse struct {
+	Status            string `json:"status"`
+	UID               string `json:"uid"`
+	Type              string `json:"type"`
+	IssueDateInMillis int64  `json:"issue_date_in_millis"`
+	// ExpiryDateInMillis is absent for basic licenses, which never expire
+	ExpiryDateInMillis *int64 `json:"expiry_date_in_millis"`
+	MaxNodes           int64  `json:"max_nodes"`
+	IssuedTo           string `json:"issued_to"`
+	Issuer             string `json:"issuer"`
+}
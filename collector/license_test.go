@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestLicense(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true (trial only)
+	//  curl http://localhost:9200/_license
+	tcs := map[string]string{
+		"7.6.2-basic": `{"license":{"status":"active","uid":"a4f3d6c5-4b0e-4bcb-8b50-0a6d1b9c2e61","type":"basic","issue_date":"2020-04-01T10:00:00.000Z","issue_date_in_millis":1585735200000,"max_nodes":1000,"issued_to":"elasticsearch","issuer":"elasticsearch","start_date_in_millis":-1}}`,
+		"7.6.2-trial": `{"license":{"status":"active","uid":"0ab7cd84-2c87-4e5b-9a3c-6f1a2e6f8b9d","type":"trial","issue_date":"2020-04-01T10:00:00.000Z","issue_date_in_millis":1585735200000,"expiry_date":"2020-05-01T10:00:00.000Z","expiry_date_in_millis":1588327200000,"max_nodes":1000,"issued_to":"elasticsearch","issuer":"elasticsearch","start_date_in_millis":-1}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		l := NewLicense(log.NewNopLogger(), http.DefaultClient, u)
+		lr, err := l.fetchAndDecodeLicense()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode license: %s", err)
+		}
+		t.Logf("[%s] License Response: %+v", ver, lr)
+		if lr.License.Status != "active" {
+			t.Errorf("Wrong license status")
+		}
+		now := time.Unix(1585735200, 0)
+		days := licenseExpiryDaysRemaining(lr.License, now)
+		if ver == "7.6.2-basic" {
+			if lr.License.ExpiryDateInMillis != nil {
+				t.Errorf("Basic license should not have an expiry date")
+			}
+			if !math.IsInf(days, 1) {
+				t.Errorf("Basic license should never expire, got %v days remaining", days)
+			}
+			continue
+		}
+		if days != 30 {
+			t.Errorf("Wrong days remaining: got %v, expected 30", days)
+		}
+		if expired := licenseExpiryDaysRemaining(lr.License, now.Add(31*24*time.Hour)); expired != -1 {
+			t.Errorf("Wrong days remaining for expired license: got %v, expected -1", expired)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// masterNodeTracker counts the changes of the elected master node between scrapes, used by
+// ClusterHealth and the legacy CatMaster collector under their own metric names
+type masterNodeTracker struct {
+	changes prometheus.Counter
+
+	mu               sync.Mutex
+	lastMasterNodeID string
+}
+
+func newMasterNodeTracker(name, help string, constLabels prometheus.Labels) *masterNodeTracker {
+	return &masterNodeTracker{
+		changes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "cluster", name),
+			Help:        help,
+			ConstLabels: constLabels,
+		}),
+	}
+}
+
+// track counts a change whenever the elected master differs from the one seen on the previous scrape
+func (t *masterNodeTracker) track(masterNodeID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastMasterNodeID != "" && t.lastMasterNodeID != masterNodeID {
+		t.changes.Inc()
+	}
+	t.lastMasterNodeID = masterNodeID
+}
+
+// fetchAndDecodeMasterNode fetches the elected master node from the cat master API
+func fetchAndDecodeMasterNode(logger log.Logger, client *http.Client, esURL *url.URL, jsonParseFailures prometheus.Counter) (catMasterResponse, error) {
+	var cmr []catMasterResponse
+
+	u := *esURL
+	u.Path = path.Join(u.Path, "/_cat/master")
+	u.RawQuery = "format=json"
+	if err := getAndDecodeURL(logger, client, &u, &cmr, jsonParseFailures); err != nil {
+		return catMasterResponse{}, err
+	}
+	if len(cmr) == 0 {
+		return catMasterResponse{}, fmt.Errorf("no master node elected")
+	}
+
+	return cmr[0], nil
+}
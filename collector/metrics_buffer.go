@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsBuffer holds the last complete set of metrics of a collector refreshed in the background.
+// Collectors with expensive or slow requests serve it on scrapes instead of querying the cluster,
+// so a slow cluster never leads to a partial set of metrics. The time of the last successful
+// refresh is served with the buffered metrics, as they may be older than the scrape interval.
+type metricsBuffer struct {
+	collect     func(ch chan<- prometheus.Metric) error
+	lastRefresh prometheus.Gauge
+
+	mu      sync.RWMutex
+	metrics []prometheus.Metric
+}
+
+func newMetricsBuffer(collect func(ch chan<- prometheus.Metric) error, lastRefresh prometheus.Gauge) *metricsBuffer {
+	return &metricsBuffer{
+		collect:     collect,
+		lastRefresh: lastRefresh,
+	}
+}
+
+// Run refreshes the buffer in the background every interval until ctx is cancelled
+func (b *metricsBuffer) Run(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			b.refresh(time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// refresh collects the metrics and swaps them into the buffer. The last refresh time is only
+// updated when collect succeeded.
+func (b *metricsBuffer) refresh(now time.Time) {
+	ch := make(chan prometheus.Metric)
+	var err error
+	go func() {
+		err = b.collect(ch)
+		close(ch)
+	}()
+	var metrics []prometheus.Metric
+	for metric := range ch {
+		metrics = append(metrics, metric)
+	}
+	if err == nil {
+		b.lastRefresh.Set(float64(now.Unix()))
+	}
+
+	b.mu.Lock()
+	b.metrics = metrics
+	b.mu.Unlock()
+}
+
+// Describe adds the description of the last refresh time
+func (b *metricsBuffer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- b.lastRefresh.Desc()
+}
+
+// Collect serves the buffered metrics, or collects them directly until the buffer is filled by Run
+func (b *metricsBuffer) Collect(ch chan<- prometheus.Metric) {
+	b.mu.RLock()
+	metrics := b.metrics
+	b.mu.RUnlock()
+
+	if metrics == nil {
+		_ = b.collect(ch)
+		return
+	}
+	for _, metric := range metrics {
+		ch <- metric
+	}
+	ch <- b.lastRefresh
+}
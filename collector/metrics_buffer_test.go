@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestMetricsBufferLastRefresh(t *testing.T) {
+	metric := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_metric", Help: "Test metric."})
+	var err error
+	b := newMetricsBuffer(func(ch chan<- prometheus.Metric) error {
+		ch <- metric
+		return err
+	}, prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_last_refresh_timestamp_seconds", Help: "Test last refresh."}))
+
+	lastRefresh := func() float64 {
+		var m dto.Metric
+		if err := b.lastRefresh.Write(&m); err != nil {
+			t.Fatalf("Failed to read last refresh: %s", err)
+		}
+		return m.GetGauge().GetValue()
+	}
+
+	start := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+	b.refresh(start)
+	if v := lastRefresh(); v != float64(start.Unix()) {
+		t.Errorf("Wrong last refresh after a successful refresh: %v", v)
+	}
+
+	// a failed refresh keeps the time of the last successful one
+	err = errors.New("failed")
+	b.refresh(start.Add(time.Minute))
+	if v := lastRefresh(); v != float64(start.Unix()) {
+		t.Errorf("Wrong last refresh after a failed refresh: %v", v)
+	}
+
+	ch := make(chan prometheus.Metric, 2)
+	b.Collect(ch)
+	close(ch)
+	if len(ch) != 2 {
+		t.Errorf("Wrong number of buffered metrics: %d", len(ch))
+	}
+}
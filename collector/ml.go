@@ -0,0 +1,568 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type datafeedMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(datafeedStats MLDatafeedStatsDataResponse) float64
+	Labels func(datafeedStats MLDatafeedStatsDataResponse) []string
+}
+
+type inferenceDeploymentMetric struct {
+	Type  prometheus.ValueType
+	Desc  *prometheus.Desc
+	Value func(deploymentStats MLTrainedModelDeploymentStatsResponse) float64
+}
+
+type dataFrameAnalyticsMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(dataFrameAnalyticsStats MLDataFrameAnalyticsStatsDataResponse) float64
+	Labels func(dataFrameAnalyticsStats MLDataFrameAnalyticsStatsDataResponse, analysisType string) []string
+}
+
+type trainedModelMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(trainedModelStats MLTrainedModelStatsDataResponse) float64
+	Labels func(trainedModelStats MLTrainedModelStatsDataResponse) []string
+}
+
+var (
+	datafeedStates = map[string]float64{
+		"started":  1,
+		"stopped":  0,
+		"starting": 2,
+		"stopping": 3,
+		"failed":   -1,
+	}
+	// datafeedStateUnknown is the value of states missing from datafeedStates
+	datafeedStateUnknown = -2.0
+
+	// deploymentStatsRemovedCodes are the responses of clusters without the deployment stats API:
+	// unknown endpoints are answered with 400 and removed ones with 404 or 410
+	deploymentStatsRemovedCodes = map[int]bool{
+		http.StatusBadRequest: true,
+		http.StatusNotFound:   true,
+		http.StatusGone:       true,
+	}
+
+	defaultDatafeedLabels      = []string{"datafeed_id", "job_id"}
+	defaultDatafeedLabelValues = func(datafeedStats MLDatafeedStatsDataResponse) []string {
+		return []string{datafeedStats.DatafeedID, datafeedStats.TimingStats.JobID}
+	}
+
+	dataFrameAnalyticsStates = map[string]float64{
+		"stopping":   5,
+		"starting":   4,
+		"analyzing":  3,
+		"reindexing": 2,
+		"started":    1,
+		"stopped":    0,
+		"failed":     -1,
+	}
+	// dataFrameAnalyticsStateUnknown is the value of states missing from dataFrameAnalyticsStates
+	dataFrameAnalyticsStateUnknown = -2.0
+
+	defaultDataFrameAnalyticsLabels      = []string{"job_id", "analysis_type"}
+	defaultDataFrameAnalyticsLabelValues = func(dataFrameAnalyticsStats MLDataFrameAnalyticsStatsDataResponse, analysisType string) []string {
+		return []string{dataFrameAnalyticsStats.ID, analysisType}
+	}
+
+	trainedModelDeploymentStates = map[string]float64{
+		"started":  1,
+		"failed":   0,
+		"starting": 2,
+		"stopping": 3,
+	}
+	// trainedModelDeploymentStateUnknown is the value of states missing from trainedModelDeploymentStates
+	trainedModelDeploymentStateUnknown = -1.0
+
+	defaultTrainedModelLabels      = []string{"model_id", "deployment_id"}
+	defaultTrainedModelLabelValues = func(trainedModelStats MLTrainedModelStatsDataResponse) []string {
+		var deploymentID string
+		if trainedModelStats.DeploymentStats != nil {
+			deploymentID = trainedModelStats.DeploymentStats.DeploymentID
+		}
+		return []string{trainedModelStats.ModelID, deploymentID}
+	}
+)
+
+// ML information struct
+type ML struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	datafeedMetrics               []*datafeedMetric
+	trainedModelMetrics           []*trainedModelMetric
+	trainedModelDeploymentMetrics []*trainedModelMetric
+	inferenceDeploymentMetrics    []*inferenceDeploymentMetric
+	dataFrameAnalyticsMetrics     []*dataFrameAnalyticsMetric
+
+	mu sync.Mutex
+	// deploymentStatsRemoved is set once the cluster rejected the deployment stats API,
+	// which was removed in 8.1
+	deploymentStatsRemoved bool
+}
+
+// NewML defines ML Prometheus metrics
+func NewML(logger log.Logger, client *http.Client, url *url.URL) *ML {
+	constLabels := constLabelsFromURL(url)
+	return &ML{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "ml", "up"),
+			Help:        "Was the last scrape of the ElasticSearch ML endpoints successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ml", "total_scrapes"),
+			Help:        "Current total ElasticSearch ML scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "ml", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		datafeedMetrics: []*datafeedMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "datafeed_state"),
+					"Datafeed state (started=1, stopped=0, starting=2, stopping=3, failed=-1, unknown=-2)",
+					defaultDatafeedLabels, constLabels,
+				),
+				Value: func(datafeedStats MLDatafeedStatsDataResponse) float64 {
+					return stateValue(datafeedStates, datafeedStats.State, datafeedStateUnknown)
+				},
+				Labels: defaultDatafeedLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "datafeed_search_count_total"),
+					"Number of searches performed by the datafeed",
+					defaultDatafeedLabels, constLabels,
+				),
+				Value: func(datafeedStats MLDatafeedStatsDataResponse) float64 {
+					return float64(datafeedStats.TimingStats.SearchCount)
+				},
+				Labels: defaultDatafeedLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "datafeed_search_time_seconds_total"),
+					"Total time spent searching by the datafeed in seconds",
+					defaultDatafeedLabels, constLabels,
+				),
+				Value: func(datafeedStats MLDatafeedStatsDataResponse) float64 {
+					return datafeedStats.TimingStats.TotalSearchTimeMs / 1000
+				},
+				Labels: defaultDatafeedLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "datafeed_search_bucket_avg_time_seconds"),
+					"Average search time per bucket of the datafeed in seconds",
+					defaultDatafeedLabels, constLabels,
+				),
+				Value: func(datafeedStats MLDatafeedStatsDataResponse) float64 {
+					return datafeedStats.TimingStats.AverageSearchTimePerBucketMs / 1000
+				},
+				Labels: defaultDatafeedLabelValues,
+			},
+		},
+		trainedModelMetrics: []*trainedModelMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "model_inference_count_total"),
+					"Number of inferences performed by the trained model",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(trainedModelStats MLTrainedModelStatsDataResponse) float64 {
+					return float64(trainedModelStats.InferenceStats.InferenceCount)
+				},
+				Labels: defaultTrainedModelLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "model_cache_miss_count_total"),
+					"Number of inferences of the trained model which missed the model cache",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(trainedModelStats MLTrainedModelStatsDataResponse) float64 {
+					return float64(trainedModelStats.InferenceStats.CacheMissCount)
+				},
+				Labels: defaultTrainedModelLabelValues,
+			},
+		},
+		trainedModelDeploymentMetrics: []*trainedModelMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "model_inference_time_seconds_total"),
+					"Total time spent on inferences by the trained model deployment in seconds",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(trainedModelStats MLTrainedModelStatsDataResponse) float64 {
+					return trainedModelStats.DeploymentStats.InferenceTimeMs() / 1000
+				},
+				Labels: defaultTrainedModelLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "model_allocation_status"),
+					"Trained model deployment state (started=1, starting=2, stopping=3, failed=0, unknown=-1)",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(trainedModelStats MLTrainedModelStatsDataResponse) float64 {
+					return stateValue(trainedModelDeploymentStates, trainedModelStats.DeploymentStats.State, trainedModelDeploymentStateUnknown)
+				},
+				Labels: defaultTrainedModelLabelValues,
+			},
+		},
+		inferenceDeploymentMetrics: []*inferenceDeploymentMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "inference_requests_total"),
+					"Number of inference requests served by the trained model deployment on all nodes",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(deploymentStats MLTrainedModelDeploymentStatsResponse) float64 {
+					return float64(deploymentStats.InferenceCount())
+				},
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "inference_failure_total"),
+					"Number of inference requests of the trained model deployment which failed on all nodes",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(deploymentStats MLTrainedModelDeploymentStatsResponse) float64 {
+					return float64(deploymentStats.ErrorCount())
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "inference_queue_size"),
+					"Number of inference requests queued for the trained model deployment on all nodes",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(deploymentStats MLTrainedModelDeploymentStatsResponse) float64 {
+					return float64(deploymentStats.PendingRequests())
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "inference_number_of_allocations"),
+					"Number of allocations of the trained model deployment",
+					defaultTrainedModelLabels, constLabels,
+				),
+				Value: func(deploymentStats MLTrainedModelDeploymentStatsResponse) float64 {
+					return float64(deploymentStats.Allocations())
+				},
+			},
+		},
+		dataFrameAnalyticsMetrics: []*dataFrameAnalyticsMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "dfanalytics_state"),
+					"Data frame analytics job state (stopping=5, starting=4, analyzing=3, reindexing=2, started=1, stopped=0, failed=-1, unknown=-2)",
+					defaultDataFrameAnalyticsLabels, constLabels,
+				),
+				Value: func(dataFrameAnalyticsStats MLDataFrameAnalyticsStatsDataResponse) float64 {
+					return stateValue(dataFrameAnalyticsStates, dataFrameAnalyticsStats.State, dataFrameAnalyticsStateUnknown)
+				},
+				Labels: defaultDataFrameAnalyticsLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "dfanalytics_progress_pct"),
+					"Average progress in percent of all phases of the data frame analytics job",
+					defaultDataFrameAnalyticsLabels, constLabels,
+				),
+				Value: func(dataFrameAnalyticsStats MLDataFrameAnalyticsStatsDataResponse) float64 {
+					return dataFrameAnalyticsStats.ProgressPercent()
+				},
+				Labels: defaultDataFrameAnalyticsLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "dfanalytics_docs_processed_total"),
+					"Number of training and test documents processed by the data frame analytics job",
+					defaultDataFrameAnalyticsLabels, constLabels,
+				),
+				Value: func(dataFrameAnalyticsStats MLDataFrameAnalyticsStatsDataResponse) float64 {
+					return float64(dataFrameAnalyticsStats.DataCounts.TrainingDocsCount + dataFrameAnalyticsStats.DataCounts.TestDocsCount)
+				},
+				Labels: defaultDataFrameAnalyticsLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "ml", "dfanalytics_peak_memory_usage_bytes"),
+					"Peak memory usage in bytes of the data frame analytics job",
+					defaultDataFrameAnalyticsLabels, constLabels,
+				),
+				Value: func(dataFrameAnalyticsStats MLDataFrameAnalyticsStatsDataResponse) float64 {
+					return float64(dataFrameAnalyticsStats.MemoryUsage.PeakUsageBytes)
+				},
+				Labels: defaultDataFrameAnalyticsLabelValues,
+			},
+		},
+	}
+}
+
+// Describe add ML metrics descriptions
+func (m *ML) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range m.datafeedMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range m.trainedModelMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range m.trainedModelDeploymentMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range m.inferenceDeploymentMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range m.dataFrameAnalyticsMetrics {
+		ch <- metric.Desc
+	}
+	ch <- m.up.Desc()
+	ch <- m.totalScrapes.Desc()
+	ch <- m.jsonParseFailures.Desc()
+}
+
+func (m *ML) fetchAndDecodeDatafeedStats() (MLDatafeedStatsResponse, error) {
+	var dsr MLDatafeedStatsResponse
+
+	u := *m.url
+	u.Path = path.Join(u.Path, "/_ml/datafeeds/_stats")
+	err := getAndDecodeURL(m.logger, m.client, &u, &dsr, m.jsonParseFailures)
+	return dsr, err
+}
+
+func (m *ML) fetchAndDecodeTrainedModelStats() (MLTrainedModelStatsResponse, error) {
+	var tmsr MLTrainedModelStatsResponse
+
+	u := *m.url
+	u.Path = path.Join(u.Path, "/_ml/trained_models/_stats")
+	err := getAndDecodeURL(m.logger, m.client, &u, &tmsr, m.jsonParseFailures)
+	return tmsr, err
+}
+
+func (m *ML) fetchAndDecodeDeploymentStats() (MLDeploymentStatsResponse, error) {
+	var dsr MLDeploymentStatsResponse
+
+	u := *m.url
+	u.Path = path.Join(u.Path, "/_ml/trained_models/_all/deployment/_stats")
+	err := getAndDecodeURL(m.logger, m.client, &u, &dsr, m.jsonParseFailures)
+	return dsr, err
+}
+
+func (m *ML) fetchAndDecodeDataFrameAnalyticsStats() (MLDataFrameAnalyticsStatsResponse, error) {
+	var dfasr MLDataFrameAnalyticsStatsResponse
+
+	u := *m.url
+	u.Path = path.Join(u.Path, "/_ml/data_frame/analytics/_stats")
+	err := getAndDecodeURL(m.logger, m.client, &u, &dfasr, m.jsonParseFailures)
+	return dfasr, err
+}
+
+func (m *ML) fetchAndDecodeDataFrameAnalyticsConfig() (MLDataFrameAnalyticsConfigResponse, error) {
+	var dfacr MLDataFrameAnalyticsConfigResponse
+
+	u := *m.url
+	u.Path = path.Join(u.Path, "/_ml/data_frame/analytics")
+	err := getAndDecodeURL(m.logger, m.client, &u, &dfacr, m.jsonParseFailures)
+	return dfacr, err
+}
+
+// Collect gets ML metric values
+func (m *ML) Collect(ch chan<- prometheus.Metric) {
+	m.totalScrapes.Inc()
+	defer func() {
+		ch <- m.up
+		ch <- m.totalScrapes
+		ch <- m.jsonParseFailures
+	}()
+
+	datafeedStatsResp, err := m.fetchAndDecodeDatafeedStats()
+	if err != nil {
+		m.up.Set(0)
+		_ = level.Warn(m.logger).Log(
+			"msg", "failed to fetch and decode ML datafeed stats",
+			"err", err,
+		)
+		return
+	}
+	m.up.Set(1)
+
+	for _, datafeedStats := range datafeedStatsResp.Datafeeds {
+		for _, metric := range m.datafeedMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(datafeedStats),
+				metric.Labels(datafeedStats)...,
+			)
+		}
+	}
+
+	m.collectTrainedModels(ch)
+	m.collectDataFrameAnalytics(ch)
+}
+
+func (m *ML) collectDataFrameAnalytics(ch chan<- prometheus.Metric) {
+	dataFrameAnalyticsStatsResp, err := m.fetchAndDecodeDataFrameAnalyticsStats()
+	if err != nil {
+		// the data frame analytics API only exists starting with 7.3, so this is expected on older clusters
+		_ = level.Debug(m.logger).Log(
+			"msg", "failed to fetch and decode ML data frame analytics stats",
+			"err", err,
+		)
+		return
+	}
+
+	// the stats only report the type of the analysis once it started, so it is taken from the configuration
+	analysisTypes := make(map[string]string)
+	dataFrameAnalyticsConfigResp, err := m.fetchAndDecodeDataFrameAnalyticsConfig()
+	if err != nil {
+		_ = level.Warn(m.logger).Log(
+			"msg", "failed to fetch and decode ML data frame analytics config",
+			"err", err,
+		)
+	}
+	for _, dataFrameAnalyticsConfig := range dataFrameAnalyticsConfigResp.DataFrameAnalytics {
+		analysisTypes[dataFrameAnalyticsConfig.ID] = dataFrameAnalyticsConfig.AnalysisType()
+	}
+
+	for _, dataFrameAnalyticsStats := range dataFrameAnalyticsStatsResp.DataFrameAnalytics {
+		for _, metric := range m.dataFrameAnalyticsMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(dataFrameAnalyticsStats),
+				metric.Labels(dataFrameAnalyticsStats, analysisTypes[dataFrameAnalyticsStats.ID])...,
+			)
+		}
+	}
+}
+
+func (m *ML) collectTrainedModels(ch chan<- prometheus.Metric) {
+	trainedModelStatsResp, err := m.fetchAndDecodeTrainedModelStats()
+	if err != nil {
+		// the trained models API only exists starting with 7.10, so this is expected on older clusters
+		_ = level.Debug(m.logger).Log(
+			"msg", "failed to fetch and decode ML trained model stats",
+			"err", err,
+		)
+		return
+	}
+
+	for _, trainedModelStats := range trainedModelStatsResp.TrainedModelStats {
+		for _, metric := range m.trainedModelMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(trainedModelStats),
+				metric.Labels(trainedModelStats)...,
+			)
+		}
+		if trainedModelStats.DeploymentStats == nil {
+			continue
+		}
+		for _, metric := range m.trainedModelDeploymentMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(trainedModelStats),
+				metric.Labels(trainedModelStats)...,
+			)
+		}
+	}
+
+	m.collectInferenceDeployments(ch, trainedModelStatsResp)
+}
+
+// collectInferenceDeployments exports the inference stats of the trained model deployments. Starting
+// with 8.1 they are part of the trained model stats, 8.0 only reports them in the deployment stats.
+func (m *ML) collectInferenceDeployments(ch chan<- prometheus.Metric, trainedModelStatsResp MLTrainedModelStatsResponse) {
+	var deployments []MLTrainedModelDeploymentStatsResponse
+	for _, trainedModelStats := range trainedModelStatsResp.TrainedModelStats {
+		if trainedModelStats.DeploymentStats != nil {
+			deployments = append(deployments, *trainedModelStats.DeploymentStats)
+		}
+	}
+	if len(deployments) == 0 {
+		m.mu.Lock()
+		deploymentStatsRemoved := m.deploymentStatsRemoved
+		m.mu.Unlock()
+		if deploymentStatsRemoved {
+			return
+		}
+
+		deploymentStatsResp, err := m.fetchAndDecodeDeploymentStats()
+		if statusErr, ok := err.(*httpStatusError); ok && deploymentStatsRemovedCodes[statusErr.StatusCode] {
+			_ = level.Debug(m.logger).Log(
+				"msg", "ML deployment stats API is not available, no longer fetching it",
+				"err", err,
+			)
+			m.mu.Lock()
+			m.deploymentStatsRemoved = true
+			m.mu.Unlock()
+			return
+		}
+		if err != nil {
+			_ = level.Warn(m.logger).Log(
+				"msg", "failed to fetch and decode ML deployment stats",
+				"err", err,
+			)
+			return
+		}
+		deployments = deploymentStatsResp.DeploymentStats
+	}
+
+	for _, deploymentStats := range deployments {
+		for _, metric := range m.inferenceDeploymentMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(deploymentStats),
+				deploymentStats.ModelID, deploymentStats.DeploymentID,
+			)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package collector
+
+import (
+	"encoding/json"
+)
+
+// MLDatafeedStatsResponse is a representation of the ML datafeeds stats
+type MLDatafeedStatsResponse struct {
+	Count     int64                         `json:"count"`
+	Datafeeds []MLDatafeedStatsDataResponse `json:"datafeeds"`
+}
+
+// MLDatafeedStatsDataResponse is a representation of the stats of a single ML datafeed
+type MLDatafeedStatsDataResponse struct {
+	DatafeedID  string                        `json:"datafeed_id"`
+	State       string                        `json:"state"`
+	TimingStats MLDatafeedTimingStatsResponse `json:"timing_stats"`
+}
+
+// MLDatafeedTimingStatsResponse is a representation of the ML datafeed search timing stats
+type MLDatafeedTimingStatsResponse struct {
+	JobID                        string  `json:"job_id"`
+	SearchCount                  int64   `json:"search_count"`
+	BucketCount                  int64   `json:"bucket_count"`
+	TotalSearchTimeMs            float64 `json:"total_search_time_ms"`
+	AverageSearchTimePerBucketMs float64 `json:"average_search_time_per_bucket_ms"`
+}
+
+// MLDataFrameAnalyticsStatsResponse is a representation of the ML data frame analytics jobs stats
+type MLDataFrameAnalyticsStatsResponse struct {
+	Count              int64                                   `json:"count"`
+	DataFrameAnalytics []MLDataFrameAnalyticsStatsDataResponse `json:"data_frame_analytics"`
+}
+
+// MLDataFrameAnalyticsStatsDataResponse is a representation of the stats of a single ML data frame analytics job
+type MLDataFrameAnalyticsStatsDataResponse struct {
+	ID         string                              `json:"id"`
+	State      string                              `json:"state"`
+	Progress   []MLDataFrameAnalyticsPhaseResponse `json:"progress"`
+	DataCounts struct {
+		TrainingDocsCount int64 `json:"training_docs_count"`
+		TestDocsCount     int64 `json:"test_docs_count"`
+	} `json:"data_counts"`
+	MemoryUsage struct {
+		PeakUsageBytes int64 `json:"peak_usage_bytes"`
+	} `json:"memory_usage"`
+}
+
+// MLDataFrameAnalyticsPhaseResponse is a representation of the progress of a phase of a ML data frame analytics job
+type MLDataFrameAnalyticsPhaseResponse struct {
+	Phase           string  `json:"phase"`
+	ProgressPercent float64 `json:"progress_percent"`
+}
+
+// ProgressPercent returns the average progress of all phases of the job
+func (d MLDataFrameAnalyticsStatsDataResponse) ProgressPercent() float64 {
+	if len(d.Progress) == 0 {
+		return 0
+	}
+	var total float64
+	for _, phase := range d.Progress {
+		total += phase.ProgressPercent
+	}
+	return total / float64(len(d.Progress))
+}
+
+// MLDataFrameAnalyticsConfigResponse is a representation of the ML data frame analytics jobs configuration
+type MLDataFrameAnalyticsConfigResponse struct {
+	Count              int64                                    `json:"count"`
+	DataFrameAnalytics []MLDataFrameAnalyticsConfigDataResponse `json:"data_frame_analytics"`
+}
+
+// MLDataFrameAnalyticsConfigDataResponse is a representation of the configuration of a single ML data frame analytics job
+type MLDataFrameAnalyticsConfigDataResponse struct {
+	ID string `json:"id"`
+	// Analysis has a single key named after the type of the analysis
+	Analysis map[string]json.RawMessage `json:"analysis"`
+}
+
+// AnalysisType returns the type of the analysis, e.g. outlier_detection
+func (d MLDataFrameAnalyticsConfigDataResponse) AnalysisType() string {
+	for analysis := range d.Analysis {
+		return analysis
+	}
+	return ""
+}
+
+// MLTrainedModelStatsResponse is a representation of the ML trained models stats
+type MLTrainedModelStatsResponse struct {
+	Count             int64                             `json:"count"`
+	TrainedModelStats []MLTrainedModelStatsDataResponse `json:"trained_model_stats"`
+}
+
+// MLTrainedModelStatsDataResponse is a representation of the stats of a single ML trained model
+type MLTrainedModelStatsDataResponse struct {
+	ModelID        string                               `json:"model_id"`
+	PipelineCount  int64                                `json:"pipeline_count"`
+	InferenceStats MLTrainedModelInferenceStatsResponse `json:"inference_stats"`
+	// DeploymentStats is only reported for deployed models, starting with 8.0
+	DeploymentStats *MLTrainedModelDeploymentStatsResponse `json:"deployment_stats"`
+}
+
+// MLTrainedModelInferenceStatsResponse is a representation of the ML trained model inference stats
+type MLTrainedModelInferenceStatsResponse struct {
+	FailureCount   int64 `json:"failure_count"`
+	InferenceCount int64 `json:"inference_count"`
+	CacheMissCount int64 `json:"cache_miss_count"`
+}
+
+// MLDeploymentStatsResponse is a representation of the ML trained model deployment stats of 8.0,
+// later versions report them as part of the trained model stats
+type MLDeploymentStatsResponse struct {
+	Count           int64                                   `json:"count"`
+	DeploymentStats []MLTrainedModelDeploymentStatsResponse `json:"deployment_stats"`
+}
+
+// MLTrainedModelDeploymentStatsResponse is a representation of the ML trained model deployment stats
+type MLTrainedModelDeploymentStatsResponse struct {
+	DeploymentID string `json:"deployment_id"`
+	ModelID      string `json:"model_id"`
+	State        string `json:"state"`
+	// NumberOfAllocations is reported starting with 8.4, older versions only report the allocation status
+	NumberOfAllocations int64                                       `json:"number_of_allocations"`
+	AllocationStatus    MLTrainedModelAllocationStatusResponse      `json:"allocation_status"`
+	Nodes               []MLTrainedModelDeploymentNodeStatsResponse `json:"nodes"`
+}
+
+// MLTrainedModelAllocationStatusResponse is a representation of the allocation status of a ML trained model deployment
+type MLTrainedModelAllocationStatusResponse struct {
+	AllocationCount       int64  `json:"allocation_count"`
+	TargetAllocationCount int64  `json:"target_allocation_count"`
+	State                 string `json:"state"`
+}
+
+// MLTrainedModelDeploymentNodeStatsResponse is a representation of the ML trained model deployment stats on a single node
+type MLTrainedModelDeploymentNodeStatsResponse struct {
+	InferenceCount          int64   `json:"inference_count"`
+	AverageInferenceTimeMs  float64 `json:"average_inference_time_ms"`
+	ErrorCount              int64   `json:"error_count"`
+	NumberOfPendingRequests int64   `json:"number_of_pending_requests"`
+}
+
+// InferenceTimeMs returns the total inference time of the deployment across all nodes
+func (d MLTrainedModelDeploymentStatsResponse) InferenceTimeMs() float64 {
+	var total float64
+	for _, node := range d.Nodes {
+		total += node.AverageInferenceTimeMs * float64(node.InferenceCount)
+	}
+	return total
+}
+
+// InferenceCount returns the number of inferences of the deployment across all nodes
+func (d MLTrainedModelDeploymentStatsResponse) InferenceCount() int64 {
+	var total int64
+	for _, node := range d.Nodes {
+		total += node.InferenceCount
+	}
+	return total
+}
+
+// ErrorCount returns the number of failed inferences of the deployment across all nodes
+func (d MLTrainedModelDeploymentStatsResponse) ErrorCount() int64 {
+	var total int64
+	for _, node := range d.Nodes {
+		total += node.ErrorCount
+	}
+	return total
+}
+
+// PendingRequests returns the number of inference requests queued on all nodes of the deployment
+func (d MLTrainedModelDeploymentStatsResponse) PendingRequests() int64 {
+	var total int64
+	for _, node := range d.Nodes {
+		total += node.NumberOfPendingRequests
+	}
+	return total
+}
+
+// Allocations returns the number of allocations of the deployment
+func (d MLTrainedModelDeploymentStatsResponse) Allocations() int64 {
+	if d.NumberOfAllocations > 0 {
+		return d.NumberOfAllocations
+	}
+	return d.AllocationStatus.AllocationCount
+}
@@ -0,0 +1,311 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestMLDatafeedStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  curl -XPUT http://localhost:9200/_ml/anomaly_detectors/total-requests -d '{"analysis_config":{"bucket_span":"10m","detectors":[{"function":"sum","field_name":"total"}]},"data_description":{"time_field":"timestamp"}}'
+	//  curl -XPUT http://localhost:9200/_ml/datafeeds/datafeed-total-requests -d '{"job_id":"total-requests","indices":["server-metrics"]}'
+	//  curl -XPOST http://localhost:9200/_ml/anomaly_detectors/total-requests/_open
+	//  curl -XPOST http://localhost:9200/_ml/datafeeds/datafeed-total-requests/_start
+	//  curl http://localhost:9200/_ml/datafeeds/_stats
+	tcs := map[string]string{
+		"7.6.2": `{"count":2,"datafeeds":[{"datafeed_id":"datafeed-total-requests","state":"started","node":{"id":"2spCyo1pRi2Ajo-j-_dnPX","name":"node-0","ephemeral_id":"hoXMLZB0RWKfR9UPPUCxXX","transport_address":"127.0.0.1:9300","attributes":{"ml.machine_memory":"17179869184","ml.max_open_jobs":"20"}},"assignment_explanation":"","timing_stats":{"job_id":"total-requests","search_count":20,"bucket_count":1200,"total_search_time_ms":3000.0,"average_search_time_per_bucket_ms":2.5,"exponential_average_search_time_per_hour_ms":151.0}},{"datafeed_id":"datafeed-low-traffic","state":"stopped","assignment_explanation":"","timing_stats":{"job_id":"low-traffic","search_count":0,"bucket_count":0,"total_search_time_ms":0.0}}]}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		m := NewML(log.NewNopLogger(), http.DefaultClient, u)
+		dsr, err := m.fetchAndDecodeDatafeedStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode ML datafeed stats: %s", err)
+		}
+		t.Logf("[%s] ML Datafeed Stats Response: %+v", ver, dsr)
+		if len(dsr.Datafeeds) != 2 {
+			t.Fatalf("Wrong number of datafeeds")
+		}
+		datafeed := dsr.Datafeeds[0]
+		if datafeed.DatafeedID != "datafeed-total-requests" {
+			t.Errorf("Wrong datafeed id")
+		}
+		if datafeed.TimingStats.JobID != "total-requests" {
+			t.Errorf("Wrong datafeed job id")
+		}
+		if datafeedStates[datafeed.State] != 1 {
+			t.Errorf("Wrong datafeed state")
+		}
+		if datafeed.TimingStats.SearchCount != 20 {
+			t.Errorf("Wrong datafeed search count")
+		}
+		if datafeed.TimingStats.TotalSearchTimeMs != 3000 {
+			t.Errorf("Wrong datafeed total search time")
+		}
+		if datafeedStates[dsr.Datafeeds[1].State] != 0 {
+			t.Errorf("Wrong state for stopped datafeed")
+		}
+		unknown := dsr.Datafeeds[1]
+		unknown.State = "paused"
+		if v := m.datafeedMetrics[0].Value(unknown); v != datafeedStateUnknown {
+			t.Errorf("Wrong value for unknown datafeed state: %v", v)
+		}
+	}
+}
+
+func TestMLTrainedModelStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  eland_import_hub_model --url http://localhost:9200 --hub-model-id elastic/distilbert-base-cased-finetuned-conll03-english --task-type ner --start
+	//  curl http://localhost:9200/_ml/trained_models/_stats
+	tcs := map[string]string{
+		"7.10.2": `{"count":1,"trained_model_stats":[{"model_id":"lang_ident_model_1","pipeline_count":0,"inference_stats":{"failure_count":0,"inference_count":42,"cache_miss_count":3,"missing_all_fields_count":0,"timestamp":1612345678901}}]}`,
+		"8.1.0":  `{"count":2,"trained_model_stats":[{"model_id":"elastic__distilbert-base-cased-finetuned-conll03-english","model_size_stats":{"model_size_bytes":260831121,"required_native_memory_bytes":773320002},"pipeline_count":1,"inference_stats":{"failure_count":0,"inference_count":42,"cache_miss_count":3,"missing_all_fields_count":0,"timestamp":1648046385433},"deployment_stats":{"model_id":"elastic__distilbert-base-cased-finetuned-conll03-english","deployment_id":"elastic__distilbert-base-cased-finetuned-conll03-english","inference_threads":1,"model_threads":1,"queue_capacity":1024,"state":"started","allocation_status":{"allocation_count":2,"target_allocation_count":2,"state":"fully_allocated"},"start_time":1648046300000,"nodes":[{"node":{"2spCyo1pRi2Ajo-j-_dnPX":{"name":"node-0"}},"routing_state":{"routing_state":"started"},"inference_count":30,"average_inference_time_ms":20.0},{"node":{"yYpjx5JnT4yeRAzkb6M5cg":{"name":"node-1"}},"routing_state":{"routing_state":"started"},"inference_count":10,"average_inference_time_ms":40.0}]}},{"model_id":"lang_ident_model_1","pipeline_count":0}]}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		m := NewML(log.NewNopLogger(), http.DefaultClient, u)
+		tmsr, err := m.fetchAndDecodeTrainedModelStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode ML trained model stats: %s", err)
+		}
+		t.Logf("[%s] ML Trained Model Stats Response: %+v", ver, tmsr)
+		model := tmsr.TrainedModelStats[0]
+		if model.InferenceStats.InferenceCount != 42 {
+			t.Errorf("Wrong trained model inference count")
+		}
+		if model.InferenceStats.CacheMissCount != 3 {
+			t.Errorf("Wrong trained model cache miss count")
+		}
+		if ver == "7.10.2" {
+			if model.DeploymentStats != nil {
+				t.Errorf("Unexpected deployment stats before 8.0")
+			}
+			if labels := defaultTrainedModelLabelValues(model); labels[1] != "" {
+				t.Errorf("Expected empty deployment_id label, got %q", labels[1])
+			}
+			continue
+		}
+		if model.DeploymentStats == nil {
+			t.Fatalf("Missing deployment stats")
+		}
+		if labels := defaultTrainedModelLabelValues(model); labels[1] != "elastic__distilbert-base-cased-finetuned-conll03-english" {
+			t.Errorf("Wrong deployment_id label %q", labels[1])
+		}
+		if trainedModelDeploymentStates[model.DeploymentStats.State] != 1 {
+			t.Errorf("Wrong deployment state")
+		}
+		for _, metric := range m.trainedModelMetrics {
+			if !strings.Contains(metric.Desc.String(), `"elasticsearch_ml_model_allocation_status"`) {
+				continue
+			}
+			unknown := model
+			deploymentStats := *model.DeploymentStats
+			deploymentStats.State = "downloading"
+			unknown.DeploymentStats = &deploymentStats
+			if v := metric.Value(unknown); v != trainedModelDeploymentStateUnknown {
+				t.Errorf("Wrong value for unknown deployment state: %v", v)
+			}
+		}
+		if model.DeploymentStats.InferenceTimeMs() != 1000 {
+			t.Errorf("Wrong deployment inference time, got %f", model.DeploymentStats.InferenceTimeMs())
+		}
+		if tmsr.TrainedModelStats[1].DeploymentStats != nil {
+			t.Errorf("Unexpected deployment stats for undeployed model")
+		}
+	}
+}
+
+func TestMLDeploymentStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  eland_import_hub_model --url http://localhost:9200 --hub-model-id elastic/distilbert-base-cased-finetuned-conll03-english --task-type ner --start
+	//  curl http://localhost:9200/_ml/trained_models/_all/deployment/_stats
+	tcs := map[string]string{
+		"8.0.0": `{"count":1,"deployment_stats":[{"model_id":"elastic__distilbert-base-cased-finetuned-conll03-english","model_size_bytes":260831121,"inference_threads":1,"model_threads":1,"state":"started","allocation_status":{"allocation_count":2,"target_allocation_count":2,"state":"fully_allocated"},"start_time":1644316200000,"nodes":[{"node":{"2spCyo1pRi2Ajo-j-_dnPX":{"name":"node-0"}},"routing_state":{"routing_state":"started"},"inference_count":30,"average_inference_time_ms":20.0,"error_count":1,"number_of_pending_requests":4},{"node":{"yYpjx5JnT4yeRAzkb6M5cg":{"name":"node-1"}},"routing_state":{"routing_state":"started"},"inference_count":10,"average_inference_time_ms":40.0,"error_count":2,"number_of_pending_requests":3}]}]}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		m := NewML(log.NewNopLogger(), http.DefaultClient, u)
+		dsr, err := m.fetchAndDecodeDeploymentStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode ML deployment stats: %s", err)
+		}
+		t.Logf("[%s] ML Deployment Stats Response: %+v", ver, dsr)
+		if len(dsr.DeploymentStats) != 1 {
+			t.Fatalf("Wrong number of deployments")
+		}
+		// inference_requests_total, inference_failure_total, inference_queue_size, inference_number_of_allocations
+		expected := []float64{40, 3, 7, 2}
+		for i, metric := range m.inferenceDeploymentMetrics {
+			if v := metric.Value(dsr.DeploymentStats[0]); v != expected[i] {
+				t.Errorf("Wrong value for inference deployment metric %d: got %v, expected %v", i, v, expected[i])
+			}
+		}
+	}
+}
+
+func TestMLDeploymentStatsRemoved(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  curl http://localhost:9200/_ml/datafeeds/_stats
+	//  curl http://localhost:9200/_ml/trained_models/_stats
+	//  curl http://localhost:9200/_ml/trained_models/_all/deployment/_stats
+	tcs := map[string]map[string]string{
+		"8.1.0": {
+			"/_ml/datafeeds/_stats":      `{"count":0,"datafeeds":[]}`,
+			"/_ml/trained_models/_stats": `{"count":1,"trained_model_stats":[{"model_id":"lang_ident_model_1","pipeline_count":0}]}`,
+		},
+	}
+	for ver, out := range tcs {
+		requests := map[string]int{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			body, ok := out[r.URL.Path]
+			if !ok {
+				http.Error(w, `{"error":"no handler found for uri"}`, http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, body)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		m := NewML(log.NewNopLogger(), http.DefaultClient, u)
+		for i := 0; i < 3; i++ {
+			ch := make(chan prometheus.Metric, 100)
+			m.Collect(ch)
+			close(ch)
+		}
+		if n := requests["/_ml/trained_models/_all/deployment/_stats"]; n != 1 {
+			t.Errorf("[%s] Wrong number of deployment stats requests: got %d, expected 1", ver, n)
+		}
+		if n := requests["/_ml/trained_models/_stats"]; n != 3 {
+			t.Errorf("[%s] Wrong number of trained model stats requests: got %d, expected 3", ver, n)
+		}
+	}
+}
+
+func TestMLDataFrameAnalyticsStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  curl -XPUT http://localhost:9200/_ml/data_frame/analytics/weblog-outliers -H 'Content-Type: application/json' -d '{"source":{"index":"weblogs"},"dest":{"index":"weblog-outliers"},"analysis":{"outlier_detection":{}}}'
+	//  curl -XPUT http://localhost:9200/_ml/data_frame/analytics/house-prices -H 'Content-Type: application/json' -d '{"source":{"index":"houses"},"dest":{"index":"house-prices"},"analysis":{"regression":{"dependent_variable":"price"}}}'
+	//  curl -XPOST http://localhost:9200/_ml/data_frame/analytics/weblog-outliers/_start
+	//  curl http://localhost:9200/_ml/data_frame/analytics/_stats
+	//  curl http://localhost:9200/_ml/data_frame/analytics
+	tcs := map[string]map[string]string{
+		"7.10.2": {
+			"/_ml/data_frame/analytics/_stats": `{"count":2,"data_frame_analytics":[{"id":"weblog-outliers","state":"analyzing","progress":[{"phase":"reindexing","progress_percent":100},{"phase":"loading_data","progress_percent":100},{"phase":"computing_outliers","progress_percent":40},{"phase":"writing_results","progress_percent":0}],"data_counts":{"training_docs_count":1500,"test_docs_count":500,"skipped_docs_count":3},"memory_usage":{"timestamp":1612345678901,"peak_usage_bytes":1048576,"status":"ok"},"analysis_stats":{"outlier_detection_stats":{"timestamp":1612345678901,"parameters":{"n_neighbors":0,"method":"ensemble"},"timing_stats":{"elapsed_time":2300}}},"node":{"id":"2spCyo1pRi2Ajo-j-_dnPX","name":"node-0"},"assignment_explanation":""},{"id":"house-prices","state":"stopped","progress":[{"phase":"reindexing","progress_percent":0},{"phase":"loading_data","progress_percent":0},{"phase":"feature_selection","progress_percent":0},{"phase":"coarse_parameter_search","progress_percent":0},{"phase":"fine_tuning_parameters","progress_percent":0},{"phase":"final_training","progress_percent":0},{"phase":"writing_results","progress_percent":0},{"phase":"inference","progress_percent":0}],"data_counts":{"training_docs_count":0,"test_docs_count":0,"skipped_docs_count":0},"memory_usage":{"peak_usage_bytes":0,"status":"ok"}}]}`,
+			"/_ml/data_frame/analytics":        `{"count":2,"data_frame_analytics":[{"id":"house-prices","source":{"index":["houses"],"query":{"match_all":{}}},"dest":{"index":"house-prices","results_field":"ml"},"analysis":{"regression":{"dependent_variable":"price","prediction_field_name":"price_prediction","training_percent":100.0,"randomize_seed":-2353471817937516178,"loss_function":"mse","early_stopping_enabled":true}},"model_memory_limit":"1gb","create_time":1612345670123,"version":"7.10.2","allow_lazy_start":false,"max_num_threads":1},{"id":"weblog-outliers","source":{"index":["weblogs"],"query":{"match_all":{}}},"dest":{"index":"weblog-outliers","results_field":"ml"},"analysis":{"outlier_detection":{"compute_feature_influence":true,"outlier_fraction":0.05,"standardization_enabled":true}},"model_memory_limit":"1gb","create_time":1612345660123,"version":"7.10.2","allow_lazy_start":false,"max_num_threads":1}]}`,
+		},
+	}
+	for ver, out := range tcs {
+		ts, u := newFixtureServer(t, out)
+		defer ts.Close()
+		m := NewML(log.NewNopLogger(), http.DefaultClient, u)
+		dfasr, err := m.fetchAndDecodeDataFrameAnalyticsStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode ML data frame analytics stats: %s", err)
+		}
+		t.Logf("[%s] ML Data Frame Analytics Stats Response: %+v", ver, dfasr)
+		if len(dfasr.DataFrameAnalytics) != 2 {
+			t.Fatalf("Wrong number of data frame analytics jobs")
+		}
+		job := dfasr.DataFrameAnalytics[0]
+		// dfanalytics_state, dfanalytics_progress_pct, dfanalytics_docs_processed_total, dfanalytics_peak_memory_usage_bytes
+		expected := []float64{3, 60, 2000, 1048576}
+		for i, metric := range m.dataFrameAnalyticsMetrics {
+			if v := metric.Value(job); v != expected[i] {
+				t.Errorf("Wrong value for data frame analytics metric %d: got %v, expected %v", i, v, expected[i])
+			}
+		}
+		stopped := dfasr.DataFrameAnalytics[1]
+		if dataFrameAnalyticsStates[stopped.State] != 0 || stopped.ProgressPercent() != 0 {
+			t.Errorf("Wrong state or progress for stopped job")
+		}
+
+		// the analysis type of the stopped job is only reported by the configuration
+		analysisTypes := map[string]string{
+			"weblog-outliers": "outlier_detection",
+			"house-prices":    "regression",
+		}
+		ch := make(chan prometheus.Metric, 100)
+		m.collectDataFrameAnalytics(ch)
+		close(ch)
+		var n int
+		for metric := range ch {
+			pm := &dto.Metric{}
+			if err := metric.Write(pm); err != nil {
+				t.Fatalf("Failed to write metric: %s", err)
+			}
+			labels := make(map[string]string)
+			for _, l := range pm.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["analysis_type"] != analysisTypes[labels["job_id"]] {
+				t.Errorf("[%s] Wrong analysis type %q of job %q", ver, labels["analysis_type"], labels["job_id"])
+			}
+			n++
+		}
+		if n != 2*len(m.dataFrameAnalyticsMetrics) {
+			t.Errorf("[%s] Wrong number of data frame analytics metrics: %d", ver, n)
+		}
+	}
+}
+
+func TestMLDataFrameAnalyticsStates(t *testing.T) {
+	m := NewML(log.NewNopLogger(), http.DefaultClient, &url.URL{})
+	for state, expected := range map[string]float64{
+		"starting": 4,
+		"stopping": 5,
+		"stopped":  0,
+		"unknown":  dataFrameAnalyticsStateUnknown,
+	} {
+		stats := MLDataFrameAnalyticsStatsDataResponse{State: state}
+		if v := m.dataFrameAnalyticsMetrics[0].Value(stats); v != expected {
+			t.Errorf("Wrong value for state %q: got %v, expected %v", state, v, expected)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	nodeAttributeLabelReplacer = strings.NewReplacer(".", "_", "-", "_")
+	validLabelName             = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// reservedNodeLabels lists the labels of the per node metrics, node attributes must not shadow them
+	reservedNodeLabels = map[string]bool{
+		"cluster": true, "cluster_url": true, "host": true, "name": true, "role": true,
+		"es_master_node": true, "es_data_node": true, "es_ingest_node": true, "es_client_node": true,
+		"type": true, "breaker": true, "mount": true, "path": true, "device": true, "cache": true,
+		"area": true, "gc": true, "pool": true, "node_id": true, "node_name": true, "field_name": true,
+		"bound_address": true, "publish_address": true,
+	}
+)
+
+// nodeAttributeLabel returns the label name of a node attribute, dots and dashes are replaced by underscores
+func nodeAttributeLabel(attribute string) string {
+	return nodeAttributeLabelReplacer.Replace(attribute)
+}
+
+// ParseNodeAttributes parses a comma separated list of node attribute names, e.g. zone,rack, whose
+// values are added as labels to the per node metrics
+func ParseNodeAttributes(s string) ([]string, error) {
+	var attributes []string
+	labels := map[string]bool{}
+	for _, attribute := range strings.Split(s, ",") {
+		attribute = strings.TrimPrefix(strings.TrimSpace(attribute), "node.attr.")
+		if attribute == "" {
+			continue
+		}
+		label := nodeAttributeLabel(attribute)
+		if !validLabelName.MatchString(label) {
+			return nil, fmt.Errorf("node attribute %q is not a valid label name", attribute)
+		}
+		if reservedNodeLabels[label] {
+			return nil, fmt.Errorf("node attribute %q conflicts with the %s label of the node metrics", attribute, label)
+		}
+		if labels[label] {
+			return nil, fmt.Errorf("node attribute %q is listed twice", attribute)
+		}
+		labels[label] = true
+		attributes = append(attributes, attribute)
+	}
+	return attributes, nil
+}
+
+// withNodeAttributeLabels appends the label names of the node attributes to the labels
+func withNodeAttributeLabels(labels []string, attributes []string) []string {
+	result := make([]string, 0, len(labels)+len(attributes))
+	result = append(result, labels...)
+	for _, attribute := range attributes {
+		result = append(result, nodeAttributeLabel(attribute))
+	}
+	return result
+}
+
+// withAttributeValues appends the values of the node attributes to the label values, empty for missing attributes
+func (c *Nodes) withAttributeValues(values []string, node NodeStatsNodeResponse) []string {
+	for _, attribute := range c.attributes {
+		values = append(values, node.Attributes[attribute])
+	}
+	return values
+}
@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestParseNodeAttributes(t *testing.T) {
+	tcs := map[string]struct {
+		attributes []string
+		ok         bool
+	}{
+		"":                                 {nil, true},
+		"zone":                             {[]string{"zone"}, true},
+		"zone, rack ,":                     {[]string{"zone", "rack"}, true},
+		"node.attr.zone,ml.machine_memory": {[]string{"zone", "ml.machine_memory"}, true},
+		"zone,zone":                        {nil, false},
+		"name":                             {nil, false},
+		"bound_address":                    {nil, false},
+		"publish-address":                  {nil, false},
+		"1zone":                            {nil, false},
+		"zone/a":                           {nil, false},
+	}
+	for s, tc := range tcs {
+		attributes, err := ParseNodeAttributes(s)
+		if (err == nil) != tc.ok {
+			t.Errorf("Unexpected error for %q: %v", s, err)
+			continue
+		}
+		if fmt.Sprint(attributes) != fmt.Sprint(tc.attributes) {
+			t.Errorf("Wrong attributes for %q: got %v, expected %v", s, attributes, tc.attributes)
+		}
+	}
+}
+
+func TestReservedNodeLabels(t *testing.T) {
+	variableLabels := regexp.MustCompile(`variableLabels: \[(.*)\]}$`)
+	c := NewNodes(log.NewNopLogger(), http.DefaultClient, &url.URL{}, true, "_local", false, "", nil)
+	ch := make(chan *prometheus.Desc, 1000)
+	c.Describe(ch)
+	close(ch)
+	for desc := range ch {
+		m := variableLabels.FindStringSubmatch(desc.String())
+		if m == nil {
+			t.Fatalf("Failed to find the labels of %s", desc)
+		}
+		for _, label := range strings.Fields(m[1]) {
+			if !reservedNodeLabels[label] {
+				t.Errorf("Label %q of %s is not reserved for node attributes", label, desc)
+			}
+		}
+	}
+}
+
+func TestNodesAttributeLabels(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "node.attr.zone=us-east-1a" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":2,"successful":2,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"172.17.0.2:9300","host":"172.17.0.2","ip":"172.17.0.2:9300","roles":["ingest","master","data","ml"],"attributes":{"ml.machine_memory":"2083807232","xpack.installed":"true","zone":"us-east-1a","ml.max_open_jobs":"20"},"thread_pool":{"search":{"threads":0,"queue":0,"active":0,"rejected":0,"largest":0,"completed":0}},"breakers":{"request":{"limit_size_in_bytes":644245094,"limit_size":"614.3mb","estimated_size_in_bytes":0,"estimated_size":"0b","overhead":1.0,"tripped":0}}},"Jx0Vt0hTR0iVbVGRx1oBCA":{"timestamp":1585735200000,"name":"node-1","transport_address":"172.17.0.3:9300","host":"172.17.0.3","ip":"172.17.0.3:9300","roles":["ingest","master","data","ml"],"attributes":{"ml.machine_memory":"2083807232","xpack.installed":"true","ml.max_open_jobs":"20"},"thread_pool":{"search":{"threads":0,"queue":0,"active":0,"rejected":0,"largest":0,"completed":0}},"breakers":{"request":{"limit_size_in_bytes":644245094,"limit_size":"614.3mb","estimated_size_in_bytes":0,"estimated_size":"0b","overhead":1.0,"tripped":0}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", []string{"zone", "ml.machine_memory"})
+		ch := make(chan prometheus.Metric)
+		go func() {
+			c.Collect(ch)
+			close(ch)
+		}()
+
+		zones := map[string]string{}
+		var nodeMetrics int
+		for metric := range ch {
+			if strings.Contains(metric.Desc().String(), `"elasticsearch_node_stats_`) {
+				continue
+			}
+			nodeMetrics++
+			var m dto.Metric
+			if err := metric.Write(&m); err != nil {
+				t.Fatalf("Failed to write metric: %s", err)
+			}
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			zone, ok := labels["zone"]
+			if !ok {
+				t.Fatalf("[%s] Missing zone label on %s", ver, metric.Desc())
+			}
+			if labels["ml_machine_memory"] != "2083807232" {
+				t.Errorf("[%s] Wrong ml_machine_memory label on %s", ver, metric.Desc())
+			}
+			zones[labels["name"]] = zone
+		}
+		if nodeMetrics == 0 {
+			t.Fatalf("[%s] No node metrics collected", ver)
+		}
+		if zones["node-0"] != "us-east-1a" || zones["node-1"] != "" {
+			t.Errorf("[%s] Wrong zones: %v", ver, zones)
+		}
+	}
+}
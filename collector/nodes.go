@@ -3,9 +3,12 @@ package collector
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -42,19 +45,19 @@ func getRoles(node NodeStatsNodeResponse) map[string]bool {
 			}
 		}
 	}
-	if len(node.HTTP) == 0 {
+	if node.HTTP == nil {
 		roles["client"] = false
 	}
 	return roles
 }
 
-func createRoleMetric(role string) *nodeMetric {
+func createRoleMetric(role string, attributes []string) *nodeMetric {
 	return &nodeMetric{
 		Type: prometheus.GaugeValue,
 		Desc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "nodes", "roles"),
 			"Node roles",
-			defaultRoleLabels, prometheus.Labels{"role": role},
+			withNodeAttributeLabels(defaultRoleLabels, attributes), prometheus.Labels{"role": role},
 		),
 		Value: func(node NodeStatsNodeResponse) float64 {
 			return 1.0
@@ -69,6 +72,24 @@ func createRoleMetric(role string) *nodeMetric {
 	}
 }
 
+// writeThreadPool returns the thread pool handling indexing, named bulk before 6.3
+func writeThreadPool(node NodeStatsNodeResponse) (NodeStatsThreadPoolPoolResponse, bool) {
+	if pool, ok := node.ThreadPool["write"]; ok {
+		return pool, true
+	}
+	pool, ok := node.ThreadPool["bulk"]
+	return pool, ok
+}
+
+// parseCgroupBytes parses a control group memory value, "max" or an unparsable value means unlimited
+func parseCgroupBytes(value string) float64 {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return v
+}
+
 var (
 	defaultNodeLabels               = []string{"cluster", "host", "name", "es_master_node", "es_data_node", "es_ingest_node", "es_client_node"}
 	defaultRoleLabels               = []string{"cluster", "host", "name"}
@@ -77,6 +98,7 @@ var (
 	defaultFilesystemDataLabels     = append(defaultNodeLabels, "mount", "path")
 	defaultFilesystemIODeviceLabels = append(defaultNodeLabels, "device")
 	defaultCacheLabels              = append(defaultNodeLabels, "cache")
+	defaultNodeAddressLabels        = []string{"node_id", "node_name", "bound_address", "publish_address"}
 
 	defaultNodeLabelValues = func(cluster string, node NodeStatsNodeResponse) []string {
 		roles := getRoles(node)
@@ -121,6 +143,13 @@ type gcCollectionMetric struct {
 	Labels func(cluster string, node NodeStatsNodeResponse, collector string) []string
 }
 
+type gcOverheadMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(node NodeStatsNodeResponse, gcStats NodeStatsJVMGCCollectorResponse) float64
+	Labels func(cluster string, node NodeStatsNodeResponse, collector string) []string
+}
+
 type breakerMetric struct {
 	Type   prometheus.ValueType
 	Desc   *prometheus.Desc
@@ -151,32 +180,66 @@ type filesystemIODeviceMetric struct {
 
 // Nodes information struct
 type Nodes struct {
-	logger log.Logger
-	client *http.Client
-	url    *url.URL
-	all    bool
-	node   string
+	logger     log.Logger
+	client     *http.Client
+	url        *url.URL
+	all        bool
+	node       string
+	quickStats bool
+
+	fielddataFields string
+	attributes      []string
 
 	up                              prometheus.Gauge
 	totalScrapes, jsonParseFailures prometheus.Counter
 
-	nodeMetrics               []*nodeMetric
-	gcCollectionMetrics       []*gcCollectionMetric
-	breakerMetrics            []*breakerMetric
-	threadPoolMetrics         []*threadPoolMetric
-	filesystemDataMetrics     []*filesystemDataMetric
-	filesystemIODeviceMetrics []*filesystemIODeviceMetric
+	nodeMetrics                  []*nodeMetric
+	gcCollectionMetrics          []*gcCollectionMetric
+	gcOverheadMetrics            []*gcOverheadMetric
+	breakerMetrics               []*breakerMetric
+	threadPoolMetrics            []*threadPoolMetric
+	filesystemDataMetrics        []*filesystemDataMetric
+	filesystemIODeviceMetrics    []*filesystemIODeviceMetric
+	searchProfileMetrics         []*nodeMetric
+	searchThreadPoolMetrics      []*nodeMetric
+	writeThreadPoolMetrics       []*nodeMetric
+	replicationThreadPoolMetrics []*nodeMetric
+	requestBreakerMetrics        []*nodeMetric
+	indexingPressureMetrics      []*nodeMetric
+	discoveryMetrics             []*nodeMetric
+	discoveryPublicationMetrics  []*nodeMetric
+	cgroupMemoryMetrics          []*nodeMetric
+
+	threadPoolMaxQueueSize    *prometheus.Desc
+	fieldDataMemory           *prometheus.Desc
+	httpBoundAddressInfo      *prometheus.Desc
+	transportBoundAddressInfo *prometheus.Desc
+
+	mu sync.Mutex
+	// profiledRequests is the number of profiled search requests of every node on the previous scrape
+	profiledRequests map[string]int64
 }
 
-// NewNodes defines Nodes Prometheus metrics
-func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, node string) *Nodes {
+// NewNodes defines Nodes Prometheus metrics. With quickStats only the thread pool stats are fetched.
+// The fielddata memory is broken down by field for the comma separated fielddataFields, which may contain wildcards.
+// The values of the node attributes are added as labels to all per node metrics, see ParseNodeAttributes.
+func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, node string, quickStats bool, fielddataFields string, attributes []string) *Nodes {
 	constLabels := constLabelsFromURL(url)
+	withAttributes := func(labels []string) []string {
+		return withNodeAttributeLabels(labels, attributes)
+	}
 	return &Nodes{
-		logger: logger,
-		client: client,
-		url:    url,
-		all:    all,
-		node:   node,
+		logger:     logger,
+		client:     client,
+		url:        url,
+		all:        all,
+		node:       node,
+		quickStats: quickStats,
+
+		fielddataFields: fielddataFields,
+		attributes:      attributes,
+
+		profiledRequests: make(map[string]int64),
 
 		up: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name:        prometheus.BuildFQName(namespace, "node_stats", "up"),
@@ -194,13 +257,34 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 			ConstLabels: constLabels,
 		}),
 
+		fieldDataMemory: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "field_data_memory_bytes"),
+			"Fielddata memory usage of a single field in bytes",
+			withAttributes([]string{"node_id", "node_name", "field_name"}), constLabels,
+		),
+		httpBoundAddressInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "http_bound_address_info"),
+			"Constant metric with an address the HTTP layer of the node is bound to and the address it publishes as labels",
+			withAttributes(defaultNodeAddressLabels), constLabels,
+		),
+		transportBoundAddressInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "transport_bound_address_info"),
+			"Constant metric with an address the transport layer of the node is bound to and the address it publishes as labels",
+			withAttributes(defaultNodeAddressLabels), constLabels,
+		),
+		threadPoolMaxQueueSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "thread_pool", "max_queue_size"),
+			"Configured maximum number of tasks queued in the thread pool, not reported for unbounded queues",
+			withAttributes(defaultThreadPoolLabels), constLabels,
+		),
+
 		nodeMetrics: []*nodeMetric{
 			{
 				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "load1"),
 					"Shortterm load average",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return node.OS.CPU.LoadAvg.Load1
@@ -212,7 +296,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "load5"),
 					"Midterm load average",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return node.OS.CPU.LoadAvg.Load5
@@ -224,7 +308,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "load15"),
 					"Longterm load average",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return node.OS.CPU.LoadAvg.Load15
@@ -236,7 +320,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "cpu_percent"),
 					"Percent CPU used by OS",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.OS.CPU.Percent)
@@ -248,7 +332,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "mem_free_bytes"),
 					"Amount of free physical memory in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.OS.Mem.Free)
@@ -260,7 +344,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "mem_used_bytes"),
 					"Amount of used physical memory in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.OS.Mem.Used)
@@ -272,7 +356,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "mem_actual_free_bytes"),
 					"Amount of free physical memory in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.OS.Mem.ActualFree)
@@ -284,19 +368,43 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "os", "mem_actual_used_bytes"),
 					"Amount of used physical memory in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.OS.Mem.ActualUsed)
 				},
 				Labels: defaultNodeLabelValues,
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "os", "swap_used_bytes"),
+					"Amount of used swap space in bytes",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.OS.Swap.Used)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "os", "swap_free_bytes"),
+					"Amount of free swap space in bytes",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.OS.Swap.Free)
+				},
+				Labels: defaultNodeLabelValues,
+			},
 			{
 				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "fielddata_memory_size_bytes"),
 					"Field data cache memory usage in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.FieldData.MemorySize)
@@ -308,7 +416,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "fielddata_evictions"),
 					"Evictions from field data",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.FieldData.Evictions)
@@ -320,7 +428,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "completion_size_in_bytes"),
 					"Completion in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Completion.Size)
@@ -332,7 +440,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "filter_cache_memory_size_bytes"),
 					"Filter cache memory usage in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.FilterCache.MemorySize)
@@ -344,7 +452,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "filter_cache_evictions"),
 					"Evictions from filter cache",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.FilterCache.Evictions)
@@ -356,7 +464,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "query_cache_memory_size_bytes"),
 					"Query cache memory usage in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.QueryCache.MemorySize)
@@ -368,7 +476,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "query_cache_evictions"),
 					"Evictions from query cache",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.QueryCache.Evictions)
@@ -380,7 +488,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "query_cache_total"),
 					"Query cache total count",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.QueryCache.TotalCount)
@@ -392,7 +500,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "query_cache_cache_size"),
 					"Query cache cache size",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.QueryCache.CacheSize)
@@ -404,7 +512,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "query_cache_cache_total"),
 					"Query cache cache count",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.QueryCache.CacheCount)
@@ -416,7 +524,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "query_cache_count"),
 					"Query cache count",
-					defaultCacheLabels, constLabels,
+					withAttributes(defaultCacheLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.QueryCache.HitCount)
@@ -428,7 +536,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "query_miss_count"),
 					"Query miss count",
-					defaultCacheLabels, constLabels,
+					withAttributes(defaultCacheLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.QueryCache.MissCount)
@@ -440,7 +548,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "request_cache_memory_size_bytes"),
 					"Request cache memory usage in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.RequestCache.MemorySize)
@@ -452,7 +560,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "request_cache_evictions"),
 					"Evictions from request cache",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.RequestCache.Evictions)
@@ -464,7 +572,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "request_cache_count"),
 					"Request cache count",
-					defaultCacheLabels, constLabels,
+					withAttributes(defaultCacheLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.RequestCache.HitCount)
@@ -476,7 +584,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "request_miss_count"),
 					"Request miss count",
-					defaultCacheLabels, constLabels,
+					withAttributes(defaultCacheLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.RequestCache.MissCount)
@@ -488,7 +596,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "translog_operations"),
 					"Total translog operations",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Translog.Operations)
@@ -500,7 +608,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "translog_size_in_bytes"),
 					"Total translog size in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Translog.Size)
@@ -512,7 +620,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "get_time_seconds"),
 					"Total get time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Get.Time) / 1000
@@ -524,7 +632,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "get_total"),
 					"Total get",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Get.Total)
@@ -536,7 +644,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "get_missing_time_seconds"),
 					"Total time of get missing in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Get.MissingTime) / 1000
@@ -548,7 +656,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "get_missing_total"),
 					"Total get missing",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Get.MissingTotal)
@@ -560,7 +668,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "get_exists_time_seconds"),
 					"Total time get exists in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Get.ExistsTime) / 1000
@@ -572,7 +680,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "get_exists_total"),
 					"Total get exists operations",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Get.ExistsTotal)
@@ -584,7 +692,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_refresh", "time_seconds_total"),
 					"Total time spent refreshing in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Refresh.TotalTime) / 1000
@@ -596,7 +704,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_refresh", "total"),
 					"Total refreshes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Refresh.Total)
@@ -608,7 +716,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_query_time_seconds"),
 					"Total search query time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.QueryTime) / 1000
@@ -620,7 +728,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_query_total"),
 					"Total number of queries",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.QueryTotal)
@@ -632,7 +740,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_fetch_time_seconds"),
 					"Total search fetch time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.FetchTime) / 1000
@@ -644,19 +752,43 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_fetch_total"),
 					"Total number of fetches",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.FetchTotal)
 				},
 				Labels: defaultNodeLabelValues,
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indices", "search_query_current"),
+					"Current number of queries in flight",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Indices.Search.QueryCurrent)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indices", "search_fetch_current"),
+					"Current number of fetches in flight",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Indices.Search.FetchCurrent)
+				},
+				Labels: defaultNodeLabelValues,
+			},
 			{
 				Type: prometheus.CounterValue,
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_suggest_total"),
 					"Total number of suggests",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.SuggestTotal)
@@ -668,7 +800,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_suggest_time_seconds"),
 					"Total suggest time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.SuggestTime) / 1000
@@ -680,7 +812,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_scroll_total"),
 					"Total number of scrolls",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.ScrollTotal)
@@ -692,7 +824,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "search_scroll_time_seconds"),
 					"Total scroll time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Search.ScrollTime) / 1000
@@ -704,7 +836,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "docs"),
 					"Count of documents on this node",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Docs.Count)
@@ -716,7 +848,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "docs_deleted"),
 					"Count of deleted documents on this node",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Docs.Deleted)
@@ -728,7 +860,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "store_size_bytes"),
 					"Current size of stored index data in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Store.Size)
@@ -740,19 +872,55 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "store_throttle_time_seconds_total"),
 					"Throttle time for index store in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Store.ThrottleTime) / 1000
 				},
 				Labels: defaultNodeLabelValues,
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node_recovery", "current_as_source"),
+					"Number of ongoing peer recoveries for which the node is the source",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Indices.Recovery.CurrentAsSource)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node_recovery", "current_as_target"),
+					"Number of ongoing peer recoveries for which the node is the target",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Indices.Recovery.CurrentAsTarget)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node_recovery", "throttle_time_seconds_total"),
+					"Time peer recoveries were throttled on the node, as source or target, in seconds",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Indices.Recovery.ThrottleTime) / 1000
+				},
+				Labels: defaultNodeLabelValues,
+			},
 			{
 				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_memory_bytes"),
 					"Current memory size of segments in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.Memory)
@@ -764,7 +932,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_count"),
 					"Count of index segments on this node",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.Count)
@@ -776,7 +944,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_terms_memory_in_bytes"),
 					"Count of terms in memory for this node",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.TermsMemory)
@@ -788,7 +956,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_index_writer_memory_in_bytes"),
 					"Count of memory for index writer on this node",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.IndexWriterMemory)
@@ -800,7 +968,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_norms_memory_in_bytes"),
 					"Count of memory used by norms",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.NormsMemory)
@@ -812,7 +980,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_stored_fields_memory_in_bytes"),
 					"Count of stored fields memory",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.StoredFieldsMemory)
@@ -824,7 +992,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_doc_values_memory_in_bytes"),
 					"Count of doc values memory",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.DocValuesMemory)
@@ -836,7 +1004,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_fixed_bit_set_memory_in_bytes"),
 					"Count of fixed bit set",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.FixedBitSet)
@@ -848,7 +1016,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_term_vectors_memory_in_bytes"),
 					"Term vectors memory usage in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.TermVectorsMemory)
@@ -860,7 +1028,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_points_memory_in_bytes"),
 					"Point values memory usage in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.PointsMemory)
@@ -872,7 +1040,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "segments_version_map_memory_in_bytes"),
 					"Version map memory usage in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Segments.VersionMapMemory)
@@ -884,7 +1052,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "flush_total"),
 					"Total flushes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Flush.Total)
@@ -896,7 +1064,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "flush_time_seconds"),
 					"Cumulative flush time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Flush.Time) / 1000
@@ -908,7 +1076,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "warmer_total"),
 					"Total warmer count",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Warmer.Total)
@@ -920,7 +1088,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices", "warmer_time_seconds_total"),
 					"Total warmer time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Warmer.TotalTime) / 1000
@@ -932,7 +1100,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_indexing", "index_time_seconds_total"),
 					"Cumulative index time in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Indexing.IndexTime) / 1000
@@ -944,7 +1112,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_indexing", "index_total"),
 					"Total index calls",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Indexing.IndexTotal)
@@ -956,7 +1124,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_indexing", "delete_time_seconds_total"),
 					"Total time indexing delete in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Indexing.DeleteTime) / 1000
@@ -968,7 +1136,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_indexing", "delete_total"),
 					"Total indexing deletes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Indexing.DeleteTotal)
@@ -980,7 +1148,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_indexing", "is_throttled"),
 					"Indexing throttling",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					if node.Indices.Indexing.IsThrottled {
@@ -995,7 +1163,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_indexing", "throttle_time_seconds_total"),
 					"Cumulative indexing throttling time",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Indexing.ThrottleTime) / 1000
@@ -1007,7 +1175,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_merges", "total"),
 					"Total merges",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Merges.Total)
@@ -1019,7 +1187,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_merges", "current"),
 					"Current merges",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Merges.Current)
@@ -1031,7 +1199,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_merges", "current_size_in_bytes"),
 					"Size of a current merges in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Merges.CurrentSize)
@@ -1043,7 +1211,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_merges", "docs_total"),
 					"Cumulative docs merged",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Merges.TotalDocs)
@@ -1055,7 +1223,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_merges", "total_size_bytes_total"),
 					"Total merge size in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Merges.TotalSize)
@@ -1067,7 +1235,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_merges", "total_time_seconds_total"),
 					"Total time spent merging in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Merges.TotalTime) / 1000
@@ -1079,7 +1247,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "indices_merges", "total_throttled_time_seconds_total"),
 					"Total throttled time of merges in seconds",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Indices.Merges.TotalThrottledTime) / 1000
@@ -1091,7 +1259,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory", "used_bytes"),
 					"JVM memory currently used by area",
-					append(defaultNodeLabels, "area"), constLabels,
+					withAttributes(append(defaultNodeLabels, "area")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.HeapUsed)
@@ -1105,7 +1273,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory", "used_bytes"),
 					"JVM memory currently used by area",
-					append(defaultNodeLabels, "area"), constLabels,
+					withAttributes(append(defaultNodeLabels, "area")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.NonHeapUsed)
@@ -1119,7 +1287,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory", "max_bytes"),
 					"JVM memory max",
-					append(defaultNodeLabels, "area"), constLabels,
+					withAttributes(append(defaultNodeLabels, "area")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.HeapMax)
@@ -1133,7 +1301,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory", "committed_bytes"),
 					"JVM memory currently committed by area",
-					append(defaultNodeLabels, "area"), constLabels,
+					withAttributes(append(defaultNodeLabels, "area")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.HeapCommitted)
@@ -1147,7 +1315,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory", "committed_bytes"),
 					"JVM memory currently committed by area",
-					append(defaultNodeLabels, "area"), constLabels,
+					withAttributes(append(defaultNodeLabels, "area")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.NonHeapCommitted)
@@ -1161,7 +1329,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "used_bytes"),
 					"JVM memory currently used by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["young"].Used)
@@ -1175,7 +1343,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "max_bytes"),
 					"JVM memory max by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["young"].Max)
@@ -1189,7 +1357,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "peak_used_bytes"),
 					"JVM memory peak used by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["young"].PeakUsed)
@@ -1203,7 +1371,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "peak_max_bytes"),
 					"JVM memory peak max by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["young"].PeakMax)
@@ -1217,7 +1385,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "used_bytes"),
 					"JVM memory currently used by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["survivor"].Used)
@@ -1231,7 +1399,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "max_bytes"),
 					"JVM memory max by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["survivor"].Max)
@@ -1245,7 +1413,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "peak_used_bytes"),
 					"JVM memory peak used by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["survivor"].PeakUsed)
@@ -1259,7 +1427,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "peak_max_bytes"),
 					"JVM memory peak max by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["survivor"].PeakMax)
@@ -1273,7 +1441,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "used_bytes"),
 					"JVM memory currently used by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["old"].Used)
@@ -1287,7 +1455,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "max_bytes"),
 					"JVM memory max by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["old"].Max)
@@ -1301,7 +1469,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "peak_used_bytes"),
 					"JVM memory peak used by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["old"].PeakUsed)
@@ -1315,7 +1483,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_memory_pool", "peak_max_bytes"),
 					"JVM memory peak max by pool",
-					append(defaultNodeLabels, "pool"), constLabels,
+					withAttributes(append(defaultNodeLabels, "pool")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.Mem.Pools["old"].PeakMax)
@@ -1329,7 +1497,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_buffer_pool", "used_bytes"),
 					"JVM buffer currently used",
-					append(defaultNodeLabels, "type"), constLabels,
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.BufferPools["direct"].Used)
@@ -1343,7 +1511,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_buffer_pool", "used_bytes"),
 					"JVM buffer currently used",
-					append(defaultNodeLabels, "type"), constLabels,
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.JVM.BufferPools["mapped"].Used)
@@ -1352,12 +1520,68 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 					return append(defaultNodeLabelValues(cluster, node), "mapped")
 				},
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "jvm_buffer_pool", "count"),
+					"JVM buffer pool buffers count",
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.JVM.BufferPools["direct"].Count)
+				},
+				Labels: func(cluster string, node NodeStatsNodeResponse) []string {
+					return append(defaultNodeLabelValues(cluster, node), "direct")
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "jvm_buffer_pool", "total_capacity_bytes"),
+					"JVM buffer pool total capacity",
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.JVM.BufferPools["direct"].TotalCapacity)
+				},
+				Labels: func(cluster string, node NodeStatsNodeResponse) []string {
+					return append(defaultNodeLabelValues(cluster, node), "direct")
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "jvm_buffer_pool", "count"),
+					"JVM buffer pool buffers count",
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.JVM.BufferPools["mapped"].Count)
+				},
+				Labels: func(cluster string, node NodeStatsNodeResponse) []string {
+					return append(defaultNodeLabelValues(cluster, node), "mapped")
+				},
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "jvm_buffer_pool", "total_capacity_bytes"),
+					"JVM buffer pool total capacity",
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.JVM.BufferPools["mapped"].TotalCapacity)
+				},
+				Labels: func(cluster string, node NodeStatsNodeResponse) []string {
+					return append(defaultNodeLabelValues(cluster, node), "mapped")
+				},
+			},
 			{
 				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "cpu_percent"),
 					"Percent CPU used by process",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.CPU.Percent)
@@ -1369,7 +1593,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "mem_resident_size_bytes"),
 					"Resident memory in use by process in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.Memory.Resident)
@@ -1381,7 +1605,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "mem_share_size_bytes"),
 					"Shared memory in use by process in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.Memory.Share)
@@ -1393,7 +1617,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "mem_virtual_size_bytes"),
 					"Total virtual memory used in bytes",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.Memory.TotalVirtual)
@@ -1405,7 +1629,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "open_files_count"),
 					"Open file descriptors",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.OpenFD)
@@ -1417,7 +1641,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "max_files_descriptors"),
 					"Max file descriptors",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.MaxFD)
@@ -1429,7 +1653,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "cpu_time_seconds_sum"),
 					"Process CPU time in seconds",
-					append(defaultNodeLabels, "type"), constLabels,
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.CPU.Total) / 1000
@@ -1443,7 +1667,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "cpu_time_seconds_sum"),
 					"Process CPU time in seconds",
-					append(defaultNodeLabels, "type"), constLabels,
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.CPU.Sys) / 1000
@@ -1457,7 +1681,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "process", "cpu_time_seconds_sum"),
 					"Process CPU time in seconds",
-					append(defaultNodeLabels, "type"), constLabels,
+					withAttributes(append(defaultNodeLabels, "type")), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Process.CPU.User) / 1000
@@ -1466,12 +1690,42 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 					return append(defaultNodeLabelValues(cluster, node), "user")
 				},
 			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node_http", "connections_current_open"),
+					"Currently open HTTP connections, 0 when HTTP is disabled on the node",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					if node.HTTP == nil {
+						return 0
+					}
+					return float64(node.HTTP.CurrentOpen)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node_http", "connections_opened_total"),
+					"Total opened HTTP connections, a high rate with few open connections indicates clients without keep-alive",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					if node.HTTP == nil {
+						return 0
+					}
+					return float64(node.HTTP.TotalOpened)
+				},
+				Labels: defaultNodeLabelValues,
+			},
 			{
 				Type: prometheus.CounterValue,
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "transport", "rx_packets_total"),
 					"Count of packets received",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Transport.RxCount)
@@ -1483,7 +1737,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "transport", "rx_size_bytes_total"),
 					"Total number of bytes received",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Transport.RxSize)
@@ -1495,7 +1749,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "transport", "tx_packets_total"),
 					"Count of packets sent",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Transport.TxCount)
@@ -1507,13 +1761,73 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "transport", "tx_size_bytes_total"),
 					"Total number of bytes sent",
-					defaultNodeLabels, constLabels,
+					withAttributes(defaultNodeLabels), constLabels,
 				),
 				Value: func(node NodeStatsNodeResponse) float64 {
 					return float64(node.Transport.TxSize)
 				},
 				Labels: defaultNodeLabelValues,
 			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "filesystem_io_stats_total", "operations_count"),
+					"Count of disk operations across all devices",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.FS.IOStats.Total.Operations)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "filesystem_io_stats_total", "read_operations_count"),
+					"Count of disk read operations across all devices",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.FS.IOStats.Total.ReadOperations)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "filesystem_io_stats_total", "write_operations_count"),
+					"Count of disk write operations across all devices",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.FS.IOStats.Total.WriteOperations)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "filesystem_io_stats_total", "read_size_kilobytes_sum"),
+					"Total kilobytes read from disk across all devices",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.FS.IOStats.Total.ReadSize)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "filesystem_io_stats_total", "write_size_kilobytes_sum"),
+					"Total kilobytes written to disk across all devices",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.FS.IOStats.Total.WriteSize)
+				},
+				Labels: defaultNodeLabelValues,
+			},
 		},
 		gcCollectionMetrics: []*gcCollectionMetric{
 			{
@@ -1521,7 +1835,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_gc", "collection_seconds_count"),
 					"Count of JVM GC runs",
-					append(defaultNodeLabels, "gc"), constLabels,
+					withAttributes(append(defaultNodeLabels, "gc")), constLabels,
 				),
 				Value: func(gcStats NodeStatsJVMGCCollectorResponse) float64 {
 					return float64(gcStats.CollectionCount)
@@ -1535,7 +1849,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "jvm_gc", "collection_seconds_sum"),
 					"GC run time in seconds",
-					append(defaultNodeLabels, "gc"), constLabels,
+					withAttributes(append(defaultNodeLabels, "gc")), constLabels,
 				),
 				Value: func(gcStats NodeStatsJVMGCCollectorResponse) float64 {
 					return float64(gcStats.CollectionTime) / 1000
@@ -1545,13 +1859,32 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				},
 			},
 		},
+		gcOverheadMetrics: []*gcOverheadMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "jvm_gc", "overhead_percent"),
+					"Percentage of JVM uptime spent in GC since the node started",
+					withAttributes(append(defaultNodeLabels, "gc")), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse, gcStats NodeStatsJVMGCCollectorResponse) float64 {
+					if node.JVM.UptimeInMillis == 0 {
+						return 0
+					}
+					return float64(gcStats.CollectionTime) / float64(node.JVM.UptimeInMillis) * 100
+				},
+				Labels: func(cluster string, node NodeStatsNodeResponse, collector string) []string {
+					return append(defaultNodeLabelValues(cluster, node), collector)
+				},
+			},
+		},
 		breakerMetrics: []*breakerMetric{
 			{
 				Type: prometheus.GaugeValue,
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "breakers", "estimated_size_bytes"),
 					"Estimated size in bytes of breaker",
-					defaultBreakerLabels, constLabels,
+					withAttributes(defaultBreakerLabels), constLabels,
 				),
 				Value: func(breakerStats NodeStatsBreakersResponse) float64 {
 					return float64(breakerStats.EstimatedSize)
@@ -1565,7 +1898,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "breakers", "limit_size_bytes"),
 					"Limit size in bytes for breaker",
-					defaultBreakerLabels, constLabels,
+					withAttributes(defaultBreakerLabels), constLabels,
 				),
 				Value: func(breakerStats NodeStatsBreakersResponse) float64 {
 					return float64(breakerStats.LimitSize)
@@ -1579,7 +1912,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "breakers", "tripped"),
 					"tripped for breaker",
-					defaultBreakerLabels, constLabels,
+					withAttributes(defaultBreakerLabels), constLabels,
 				),
 				Value: func(breakerStats NodeStatsBreakersResponse) float64 {
 					return float64(breakerStats.Tripped)
@@ -1593,7 +1926,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "breakers", "overhead"),
 					"Overhead of circuit breakers",
-					defaultBreakerLabels, constLabels,
+					withAttributes(defaultBreakerLabels), constLabels,
 				),
 				Value: func(breakerStats NodeStatsBreakersResponse) float64 {
 					return breakerStats.Overhead
@@ -1609,7 +1942,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "thread_pool", "completed_count"),
 					"Thread Pool operations completed",
-					defaultThreadPoolLabels, constLabels,
+					withAttributes(defaultThreadPoolLabels), constLabels,
 				),
 				Value: func(threadPoolStats NodeStatsThreadPoolPoolResponse) float64 {
 					return float64(threadPoolStats.Completed)
@@ -1621,7 +1954,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "thread_pool", "rejected_count"),
 					"Thread Pool operations rejected",
-					defaultThreadPoolLabels, constLabels,
+					withAttributes(defaultThreadPoolLabels), constLabels,
 				),
 				Value: func(threadPoolStats NodeStatsThreadPoolPoolResponse) float64 {
 					return float64(threadPoolStats.Rejected)
@@ -1633,7 +1966,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "thread_pool", "active_count"),
 					"Thread Pool threads active",
-					defaultThreadPoolLabels, constLabels,
+					withAttributes(defaultThreadPoolLabels), constLabels,
 				),
 				Value: func(threadPoolStats NodeStatsThreadPoolPoolResponse) float64 {
 					return float64(threadPoolStats.Active)
@@ -1645,7 +1978,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "thread_pool", "largest_count"),
 					"Thread Pool largest threads count",
-					defaultThreadPoolLabels, constLabels,
+					withAttributes(defaultThreadPoolLabels), constLabels,
 				),
 				Value: func(threadPoolStats NodeStatsThreadPoolPoolResponse) float64 {
 					return float64(threadPoolStats.Largest)
@@ -1657,7 +1990,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "thread_pool", "queue_count"),
 					"Thread Pool operations queued",
-					defaultThreadPoolLabels, constLabels,
+					withAttributes(defaultThreadPoolLabels), constLabels,
 				),
 				Value: func(threadPoolStats NodeStatsThreadPoolPoolResponse) float64 {
 					return float64(threadPoolStats.Queue)
@@ -1669,7 +2002,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "thread_pool", "threads_count"),
 					"Thread Pool current threads count",
-					defaultThreadPoolLabels, constLabels,
+					withAttributes(defaultThreadPoolLabels), constLabels,
 				),
 				Value: func(threadPoolStats NodeStatsThreadPoolPoolResponse) float64 {
 					return float64(threadPoolStats.Threads)
@@ -1683,7 +2016,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_data", "available_bytes"),
 					"Available space on block device in bytes",
-					defaultFilesystemDataLabels, constLabels,
+					withAttributes(defaultFilesystemDataLabels), constLabels,
 				),
 				Value: func(fsStats NodeStatsFSDataResponse) float64 {
 					return float64(fsStats.Available)
@@ -1695,7 +2028,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_data", "free_bytes"),
 					"Free space on block device in bytes",
-					defaultFilesystemDataLabels, constLabels,
+					withAttributes(defaultFilesystemDataLabels), constLabels,
 				),
 				Value: func(fsStats NodeStatsFSDataResponse) float64 {
 					return float64(fsStats.Free)
@@ -1707,7 +2040,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_data", "size_bytes"),
 					"Size of block device in bytes",
-					defaultFilesystemDataLabels, constLabels,
+					withAttributes(defaultFilesystemDataLabels), constLabels,
 				),
 				Value: func(fsStats NodeStatsFSDataResponse) float64 {
 					return float64(fsStats.Total)
@@ -1721,7 +2054,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_io_stats_device", "operations_count"),
 					"Count of disk operations",
-					defaultFilesystemIODeviceLabels, constLabels,
+					withAttributes(defaultFilesystemIODeviceLabels), constLabels,
 				),
 				Value: func(fsIODeviceStats NodeStatsFSIOStatsDeviceResponse) float64 {
 					return float64(fsIODeviceStats.Operations)
@@ -1733,7 +2066,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_io_stats_device", "read_operations_count"),
 					"Count of disk read operations",
-					defaultFilesystemIODeviceLabels, constLabels,
+					withAttributes(defaultFilesystemIODeviceLabels), constLabels,
 				),
 				Value: func(fsIODeviceStats NodeStatsFSIOStatsDeviceResponse) float64 {
 					return float64(fsIODeviceStats.ReadOperations)
@@ -1745,7 +2078,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_io_stats_device", "write_operations_count"),
 					"Count of disk write operations",
-					defaultFilesystemIODeviceLabels, constLabels,
+					withAttributes(defaultFilesystemIODeviceLabels), constLabels,
 				),
 				Value: func(fsIODeviceStats NodeStatsFSIOStatsDeviceResponse) float64 {
 					return float64(fsIODeviceStats.WriteOperations)
@@ -1757,7 +2090,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_io_stats_device", "read_size_kilobytes_sum"),
 					"Total kilobytes read from disk",
-					defaultFilesystemIODeviceLabels, constLabels,
+					withAttributes(defaultFilesystemIODeviceLabels), constLabels,
 				),
 				Value: func(fsIODeviceStats NodeStatsFSIOStatsDeviceResponse) float64 {
 					return float64(fsIODeviceStats.ReadSize)
@@ -1769,7 +2102,7 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Desc: prometheus.NewDesc(
 					prometheus.BuildFQName(namespace, "filesystem_io_stats_device", "write_size_kilobytes_sum"),
 					"Total kilobytes written to disk",
-					defaultFilesystemIODeviceLabels, constLabels,
+					withAttributes(defaultFilesystemIODeviceLabels), constLabels,
 				),
 				Value: func(fsIODeviceStats NodeStatsFSIOStatsDeviceResponse) float64 {
 					return float64(fsIODeviceStats.WriteSize)
@@ -1777,6 +2110,295 @@ func NewNodes(logger log.Logger, client *http.Client, url *url.URL, all bool, no
 				Labels: defaultFilesystemIODeviceLabelValues,
 			},
 		},
+		searchProfileMetrics: []*nodeMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indices", "search_profile_requests_total"),
+					"Total number of profiled search requests",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Indices.Search.Profile.Total)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indices", "search_profile_time_seconds_total"),
+					"Total time spent profiling search requests in seconds",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Indices.Search.Profile.Time) / 1000
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		searchThreadPoolMetrics: []*nodeMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "thread_pool", "search_queue_size"),
+					"Number of tasks in the search thread pool queue",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.ThreadPool["search"].Queue)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "thread_pool", "search_rejected_total"),
+					"Total number of tasks rejected by the search thread pool",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.ThreadPool["search"].Rejected)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		writeThreadPoolMetrics: []*nodeMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "thread_pool", "write_queue_size"),
+					"Number of tasks in the write thread pool queue",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					pool, _ := writeThreadPool(node)
+					return float64(pool.Queue)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "thread_pool", "write_rejected_total"),
+					"Total number of tasks rejected by the write thread pool, rejected documents are lost unless the client retries",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					pool, _ := writeThreadPool(node)
+					return float64(pool.Rejected)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		replicationThreadPoolMetrics: []*nodeMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "thread_pool", "replication_queue_size"),
+					"Number of tasks in the replication thread pool queue",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.ThreadPool["replication"].Queue)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "thread_pool", "replication_rejected_total"),
+					"Total number of tasks rejected by the replication thread pool, acknowledged writes may be missing on replicas",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.ThreadPool["replication"].Rejected)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		requestBreakerMetrics: []*nodeMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "circuit_breaker", "request_tripped_total"),
+					"Total number of times the request circuit breaker tripped, each trip rejects a search aggregation due to memory pressure",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Breakers["request"].Tripped)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		cgroupMemoryMetrics: []*nodeMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "os", "cgroup_memory_limit_bytes"),
+					"Memory limit of the control group of the node in bytes, +Inf when unlimited",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return parseCgroupBytes(node.OS.Cgroup.Memory.LimitInBytes)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "os", "cgroup_memory_usage_bytes"),
+					"Memory used by the control group of the node in bytes",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return parseCgroupBytes(node.OS.Cgroup.Memory.UsageInBytes)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		discoveryMetrics: []*nodeMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node", "discovery_cluster_applier_stats_queue_size"),
+					"Number of cluster states received by the node which are not applied yet",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Discovery.ClusterStateQueue.Total)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node", "discovery_committed_cluster_states_count"),
+					"Number of committed cluster states received by the node which are not applied yet",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.Discovery.ClusterStateQueue.Committed)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		discoveryPublicationMetrics: []*nodeMetric{
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node", "discovery_cluster_applier_stats_recordings_count"),
+					"Total number of executions of the cluster state appliers and listeners of the node",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					var count int64
+					for _, recording := range node.Discovery.ClusterApplierStats.Recordings {
+						count += recording.CumulativeExecutionCount
+					}
+					return float64(count)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "node", "discovery_serialized_cluster_states_count"),
+					"Total number of full cluster states and diffs serialized by the node for publication",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					states := node.Discovery.SerializedClusterStates
+					return float64(states.FullStates.Count + states.Diffs.Count)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
+		indexingPressureMetrics: []*nodeMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indexing_pressure", "memory_total_bytes"),
+					"Memory currently used by indexing requests in bytes",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.IndexingPressure.Memory.Current.AllInBytes)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indexing_pressure", "coordinating_bytes"),
+					"Memory currently used by indexing requests in the coordinating stage in bytes",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.IndexingPressure.Memory.Current.CoordinatingInBytes)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indexing_pressure", "primary_bytes"),
+					"Memory currently used by indexing requests in the primary stage in bytes",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.IndexingPressure.Memory.Current.PrimaryInBytes)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indexing_pressure", "replica_bytes"),
+					"Memory currently used by indexing requests in the replica stage in bytes",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.IndexingPressure.Memory.Current.ReplicaInBytes)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indexing_pressure", "coordinating_rejections_total"),
+					"Total number of indexing requests rejected in the coordinating stage",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.IndexingPressure.Memory.Total.CoordinatingRejections)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indexing_pressure", "primary_rejections_total"),
+					"Total number of indexing requests rejected in the primary stage",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.IndexingPressure.Memory.Total.PrimaryRejections)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "indexing_pressure", "replica_rejections_total"),
+					"Total number of indexing requests rejected in the replica stage",
+					withAttributes(defaultNodeLabels), constLabels,
+				),
+				Value: func(node NodeStatsNodeResponse) float64 {
+					return float64(node.IndexingPressure.Memory.Total.ReplicaRejections)
+				},
+				Labels: defaultNodeLabelValues,
+			},
+		},
 	}
 }
 
@@ -1788,6 +2410,9 @@ func (c *Nodes) Describe(ch chan<- *prometheus.Desc) {
 	for _, metric := range c.gcCollectionMetrics {
 		ch <- metric.Desc
 	}
+	for _, metric := range c.gcOverheadMetrics {
+		ch <- metric.Desc
+	}
 	for _, metric := range c.threadPoolMetrics {
 		ch <- metric.Desc
 	}
@@ -1797,6 +2422,37 @@ func (c *Nodes) Describe(ch chan<- *prometheus.Desc) {
 	for _, metric := range c.filesystemIODeviceMetrics {
 		ch <- metric.Desc
 	}
+	for _, metric := range c.searchProfileMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.searchThreadPoolMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.writeThreadPoolMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.replicationThreadPoolMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.requestBreakerMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.indexingPressureMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.discoveryMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.discoveryPublicationMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range c.cgroupMemoryMetrics {
+		ch <- metric.Desc
+	}
+	ch <- c.threadPoolMaxQueueSize
+	ch <- c.fieldDataMemory
+	ch <- c.httpBoundAddressInfo
+	ch <- c.transportBoundAddressInfo
 	ch <- c.up.Desc()
 	ch <- c.totalScrapes.Desc()
 	ch <- c.jsonParseFailures.Desc()
@@ -1813,6 +2469,12 @@ func (c *Nodes) fetchAndDecodeNodeStats() (nodeStatsResponse, error) {
 		u.Path = path.Join(u.Path, "_nodes", c.node, "stats")
 	}
 
+	if c.quickStats {
+		u.Path = path.Join(u.Path, "thread_pool")
+	} else if c.fielddataFields != "" {
+		u.RawQuery = "fielddata_fields=" + url.QueryEscape(c.fielddataFields)
+	}
+
 	res, err := c.client.Get(u.String())
 	if err != nil {
 		return nsr, fmt.Errorf("failed to get cluster health from %s://%s:%s%s: %s",
@@ -1840,6 +2502,32 @@ func (c *Nodes) fetchAndDecodeNodeStats() (nodeStatsResponse, error) {
 	return nsr, nil
 }
 
+// fetchAndDecodeNodeInfo fetches the thread pool configuration and the HTTP and transport addresses
+// of the nodes from the nodes info API
+func (c *Nodes) fetchAndDecodeNodeInfo() (NodeInfoResponse, error) {
+	var nir NodeInfoResponse
+
+	u := *c.url
+	if c.all {
+		u.Path = path.Join(u.Path, "/_nodes/_all/thread_pool,http,transport")
+	} else {
+		u.Path = path.Join(u.Path, "_nodes", c.node, "thread_pool,http,transport")
+	}
+	err := getAndDecodeURL(c.logger, c.client, &u, &nir, c.jsonParseFailures)
+	return nir, err
+}
+
+// searchProfilingIncreased records the number of profiled search requests of the node and reports
+// whether it changed to a non-zero value since the previous scrape, also after a node restart
+func (c *Nodes) searchProfilingIncreased(nodeID string, profiledRequests int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.profiledRequests[nodeID]
+	c.profiledRequests[nodeID] = profiledRequests
+	return profiledRequests > 0 && profiledRequests != previous
+}
+
 // Collect gets nodes metric values
 func (c *Nodes) Collect(ch chan<- prometheus.Metric) {
 	c.totalScrapes.Inc()
@@ -1860,18 +2548,83 @@ func (c *Nodes) Collect(ch chan<- prometheus.Metric) {
 	}
 	c.up.Set(1)
 
-	for _, node := range nodeStatsResp.Nodes {
+	// the thread pool configuration and the addresses are not part of the node stats
+	var nodeInfoResp NodeInfoResponse
+	if !c.quickStats {
+		nodeInfoResp, err = c.fetchAndDecodeNodeInfo()
+		if err != nil {
+			_ = level.Warn(c.logger).Log(
+				"msg", "failed to fetch and decode node info",
+				"err", err,
+			)
+		}
+	}
+
+	for id, node := range nodeStatsResp.Nodes {
+		// Thread Pool stats
+		for pool, pstats := range node.ThreadPool {
+			for _, metric := range c.threadPoolMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(pstats),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node, pool), node)...,
+				)
+			}
+		}
+
+		// Search Thread Pool stats
+		if _, ok := node.ThreadPool["search"]; ok {
+			for _, metric := range c.searchThreadPoolMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+				)
+			}
+		}
+
+		// Write Thread Pool stats
+		if _, ok := writeThreadPool(node); ok {
+			for _, metric := range c.writeThreadPoolMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+				)
+			}
+		}
+
+		// Replication Thread Pool stats
+		if _, ok := node.ThreadPool["replication"]; ok {
+			for _, metric := range c.replicationThreadPoolMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+				)
+			}
+		}
+
+		// only thread pool stats are fetched in quick stats mode
+		if c.quickStats {
+			continue
+		}
+
 		// Handle the node labels metric
 		roles := getRoles(node)
 
 		for _, role := range []string{"master", "data", "client", "ingest"} {
 			if roles[role] {
-				metric := createRoleMetric(role)
+				metric := createRoleMetric(role, c.attributes)
 				ch <- prometheus.MustNewConstMetric(
 					metric.Desc,
 					metric.Type,
 					metric.Value(node),
-					metric.Labels(nodeStatsResp.ClusterName, node)...,
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
 				)
 			}
 		}
@@ -1881,10 +2634,51 @@ func (c *Nodes) Collect(ch chan<- prometheus.Metric) {
 				metric.Desc,
 				metric.Type,
 				metric.Value(node),
-				metric.Labels(nodeStatsResp.ClusterName, node)...,
+				c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+			)
+		}
+
+		// Fielddata per field, only reported for the requested fields
+		for field, fstats := range node.Indices.FieldData.Fields {
+			ch <- prometheus.MustNewConstMetric(
+				c.fieldDataMemory,
+				prometheus.GaugeValue,
+				float64(fstats.MemorySize),
+				c.withAttributeValues([]string{id, node.Name, field}, node)...,
+			)
+		}
+
+		// Thread Pool configuration
+		for pool, pinfo := range nodeInfoResp.Nodes[id].ThreadPool {
+			if pinfo.QueueSize < 0 {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				c.threadPoolMaxQueueSize,
+				prometheus.GaugeValue,
+				float64(pinfo.QueueSize),
+				c.withAttributeValues(defaultThreadPoolLabelValues(nodeStatsResp.ClusterName, node, pool), node)...,
 			)
 		}
 
+		// HTTP and transport addresses, a node may be bound to several addresses
+		for desc, addresses := range map[*prometheus.Desc]*NodeInfoAddressResponse{
+			c.httpBoundAddressInfo:      nodeInfoResp.Nodes[id].HTTP,
+			c.transportBoundAddressInfo: nodeInfoResp.Nodes[id].Transport,
+		} {
+			if addresses == nil {
+				continue
+			}
+			for _, boundAddress := range addresses.BoundAddress {
+				ch <- prometheus.MustNewConstMetric(
+					desc,
+					prometheus.GaugeValue,
+					1,
+					c.withAttributeValues([]string{id, node.Name, boundAddress, addresses.PublishAddress}, node)...,
+				)
+			}
+		}
+
 		// GC Stats
 		for collector, gcStats := range node.JVM.GC.Collectors {
 			for _, metric := range c.gcCollectionMetrics {
@@ -1892,7 +2686,15 @@ func (c *Nodes) Collect(ch chan<- prometheus.Metric) {
 					metric.Desc,
 					metric.Type,
 					metric.Value(gcStats),
-					metric.Labels(nodeStatsResp.ClusterName, node, collector)...,
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node, collector), node)...,
+				)
+			}
+			for _, metric := range c.gcOverheadMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node, gcStats),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node, collector), node)...,
 				)
 			}
 		}
@@ -1904,19 +2706,19 @@ func (c *Nodes) Collect(ch chan<- prometheus.Metric) {
 					metric.Desc,
 					metric.Type,
 					metric.Value(bstats),
-					metric.Labels(nodeStatsResp.ClusterName, node, breaker)...,
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node, breaker), node)...,
 				)
 			}
 		}
 
-		// Thread Pool stats
-		for pool, pstats := range node.ThreadPool {
-			for _, metric := range c.threadPoolMetrics {
+		// Request Breaker stats
+		if _, ok := node.Breakers["request"]; ok {
+			for _, metric := range c.requestBreakerMetrics {
 				ch <- prometheus.MustNewConstMetric(
 					metric.Desc,
 					metric.Type,
-					metric.Value(pstats),
-					metric.Labels(nodeStatsResp.ClusterName, node, pool)...,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
 				)
 			}
 		}
@@ -1928,7 +2730,7 @@ func (c *Nodes) Collect(ch chan<- prometheus.Metric) {
 					metric.Desc,
 					metric.Type,
 					metric.Value(fsDataStats),
-					metric.Labels(nodeStatsResp.ClusterName, node, fsDataStats.Mount, fsDataStats.Path)...,
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node, fsDataStats.Mount, fsDataStats.Path), node)...,
 				)
 			}
 		}
@@ -1940,7 +2742,72 @@ func (c *Nodes) Collect(ch chan<- prometheus.Metric) {
 					metric.Desc,
 					metric.Type,
 					metric.Value(fsIODeviceStats),
-					metric.Labels(nodeStatsResp.ClusterName, node, fsIODeviceStats.DeviceName)...,
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node, fsIODeviceStats.DeviceName), node)...,
+				)
+			}
+		}
+
+		// Indexing pressure stats, available since Elasticsearch 7.9
+		if node.IndexingPressure != nil {
+			for _, metric := range c.indexingPressureMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+				)
+			}
+		}
+
+		// Discovery stats, the serialized cluster states and applier stats are available since Elasticsearch 7.16
+		if node.Discovery != nil {
+			for _, metric := range c.discoveryMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+				)
+			}
+			if node.Discovery.SerializedClusterStates != nil && node.Discovery.ClusterApplierStats != nil {
+				for _, metric := range c.discoveryPublicationMetrics {
+					ch <- prometheus.MustNewConstMetric(
+						metric.Desc,
+						metric.Type,
+						metric.Value(node),
+						c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+					)
+				}
+			}
+		}
+
+		// Control group memory stats, only reported on Linux inside a control group
+		if node.OS.Cgroup != nil && node.OS.Cgroup.Memory != nil {
+			for _, metric := range c.cgroupMemoryMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
+				)
+			}
+		}
+
+		// Search profiling stats, only reported by some versions
+		if node.Indices.Search.Profile != nil {
+			if c.searchProfilingIncreased(id, node.Indices.Search.Profile.Total) {
+				_ = level.Warn(c.logger).Log(
+					"msg", "search profiling is active, profiled requests add overhead",
+					"node", node.Name,
+					"profiled_requests", node.Indices.Search.Profile.Total,
+				)
+			}
+			for _, metric := range c.searchProfileMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(node),
+					c.withAttributeValues(metric.Labels(nodeStatsResp.ClusterName, node), node)...,
 				)
 			}
 		}
@@ -24,9 +24,74 @@ type NodeStatsNodeResponse struct {
 	ThreadPool       map[string]NodeStatsThreadPoolPoolResponse `json:"thread_pool"`
 	JVM              NodeStatsJVMResponse                       `json:"jvm"`
 	Breakers         map[string]NodeStatsBreakersResponse       `json:"breakers"`
-	HTTP             map[string]int                             `json:"http"`
+	HTTP             *NodeStatsHTTPResponse                     `json:"http"`
 	Transport        NodeStatsTransportResponse                 `json:"transport"`
 	Process          NodeStatsProcessResponse                   `json:"process"`
+	IndexingPressure *NodeStatsIndexingPressureResponse         `json:"indexing_pressure"`
+	Discovery        *NodeStatsDiscoveryResponse                `json:"discovery"`
+}
+
+// NodeStatsDiscoveryResponse is a representation of the discovery stats of the cluster coordination
+type NodeStatsDiscoveryResponse struct {
+	ClusterStateQueue       NodeStatsDiscoveryClusterStateQueueResponse        `json:"cluster_state_queue"`
+	SerializedClusterStates *NodeStatsDiscoverySerializedClusterStatesResponse `json:"serialized_cluster_states"`
+	ClusterApplierStats     *NodeStatsDiscoveryClusterApplierStatsResponse     `json:"cluster_applier_stats"`
+}
+
+// NodeStatsDiscoveryClusterStateQueueResponse defines the cluster states received by a node which are not applied yet
+type NodeStatsDiscoveryClusterStateQueueResponse struct {
+	Total     int64 `json:"total"`
+	Pending   int64 `json:"pending"`
+	Committed int64 `json:"committed"`
+}
+
+// NodeStatsDiscoverySerializedClusterStatesResponse defines the cluster states serialized by the master, available since Elasticsearch 7.16
+type NodeStatsDiscoverySerializedClusterStatesResponse struct {
+	FullStates NodeStatsDiscoverySerializedClusterStateResponse `json:"full_states"`
+	Diffs      NodeStatsDiscoverySerializedClusterStateResponse `json:"diffs"`
+}
+
+// NodeStatsDiscoverySerializedClusterStateResponse defines the number and size of serialized full cluster states or diffs
+type NodeStatsDiscoverySerializedClusterStateResponse struct {
+	Count                   int64 `json:"count"`
+	UncompressedSizeInBytes int64 `json:"uncompressed_size_in_bytes"`
+	CompressedSizeInBytes   int64 `json:"compressed_size_in_bytes"`
+}
+
+// NodeStatsDiscoveryClusterApplierStatsResponse defines the time spent applying cluster states, available since Elasticsearch 7.16
+type NodeStatsDiscoveryClusterApplierStatsResponse struct {
+	Recordings []NodeStatsDiscoveryClusterApplierRecordingResponse `json:"recordings"`
+}
+
+// NodeStatsDiscoveryClusterApplierRecordingResponse defines the executions of a cluster state applier or listener
+type NodeStatsDiscoveryClusterApplierRecordingResponse struct {
+	Name                          string `json:"name"`
+	CumulativeExecutionCount      int64  `json:"cumulative_execution_count"`
+	CumulativeExecutionTimeMillis int64  `json:"cumulative_execution_time_millis"`
+}
+
+// NodeStatsIndexingPressureResponse is a representation of the indexing pressure stats, available since Elasticsearch 7.9
+type NodeStatsIndexingPressureResponse struct {
+	Memory NodeStatsIndexingPressureMemoryResponse `json:"memory"`
+}
+
+// NodeStatsIndexingPressureMemoryResponse defines node stats indexing pressure memory information structure
+type NodeStatsIndexingPressureMemoryResponse struct {
+	Current      NodeStatsIndexingPressureMemoryUsageResponse `json:"current"`
+	Total        NodeStatsIndexingPressureMemoryUsageResponse `json:"total"`
+	LimitInBytes int64                                        `json:"limit_in_bytes"`
+}
+
+// NodeStatsIndexingPressureMemoryUsageResponse defines node stats indexing pressure memory usage and rejections
+type NodeStatsIndexingPressureMemoryUsageResponse struct {
+	CombinedCoordinatingAndPrimaryInBytes int64 `json:"combined_coordinating_and_primary_in_bytes"`
+	CoordinatingInBytes                   int64 `json:"coordinating_in_bytes"`
+	PrimaryInBytes                        int64 `json:"primary_in_bytes"`
+	ReplicaInBytes                        int64 `json:"replica_in_bytes"`
+	AllInBytes                            int64 `json:"all_in_bytes"`
+	CoordinatingRejections                int64 `json:"coordinating_rejections"`
+	PrimaryRejections                     int64 `json:"primary_rejections"`
+	ReplicaRejections                     int64 `json:"replica_rejections"`
 }
 
 // NodeStatsBreakersResponse is a representation of a statistics about the field data circuit breaker
@@ -39,9 +104,10 @@ type NodeStatsBreakersResponse struct {
 
 // NodeStatsJVMResponse is a representation of a JVM stats, memory pool information, garbage collection, buffer pools, number of loaded/unloaded classes
 type NodeStatsJVMResponse struct {
-	BufferPools map[string]NodeStatsJVMBufferPoolResponse `json:"buffer_pools"`
-	GC          NodeStatsJVMGCResponse                    `json:"gc"`
-	Mem         NodeStatsJVMMemResponse                   `json:"mem"`
+	BufferPools    map[string]NodeStatsJVMBufferPoolResponse `json:"buffer_pools"`
+	GC             NodeStatsJVMGCResponse                    `json:"gc"`
+	Mem            NodeStatsJVMMemResponse                   `json:"mem"`
+	UptimeInMillis int64                                     `json:"uptime_in_millis"`
 }
 
 // NodeStatsJVMGCResponse defines node stats JVM garbage collector information structure
@@ -104,6 +170,33 @@ type NodeStatsThreadPoolPoolResponse struct {
 	Completed int64 `json:"completed"`
 }
 
+// NodeInfoResponse is a representation of the Elasticsearch nodes info
+type NodeInfoResponse struct {
+	ClusterName string                          `json:"cluster_name"`
+	Nodes       map[string]NodeInfoNodeResponse `json:"nodes"`
+}
+
+// NodeInfoNodeResponse defines the node info of a single node
+type NodeInfoNodeResponse struct {
+	Name       string                                `json:"name"`
+	ThreadPool map[string]NodeInfoThreadPoolResponse `json:"thread_pool"`
+	HTTP       *NodeInfoAddressResponse              `json:"http"`
+	Transport  *NodeInfoAddressResponse              `json:"transport"`
+}
+
+// NodeInfoAddressResponse defines the addresses the HTTP or transport layer of a node is bound to and publishes
+type NodeInfoAddressResponse struct {
+	BoundAddress   []string `json:"bound_address"`
+	PublishAddress string   `json:"publish_address"`
+}
+
+// NodeInfoThreadPoolResponse defines the configuration of a thread pool, a queue size of -1 means unbounded
+type NodeInfoThreadPoolResponse struct {
+	Type      string `json:"type"`
+	Size      int64  `json:"size"`
+	QueueSize int64  `json:"queue_size"`
+}
+
 // NodeStatsTCPResponse defines node stats TCP information structure
 type NodeStatsTCPResponse struct {
 	ActiveOpens  int64 `json:"active_opens"`
@@ -126,16 +219,17 @@ type NodeStatsIndicesResponse struct {
 	Merges       NodeStatsIndicesMergesResponse
 	Get          NodeStatsIndicesGetResponse
 	Search       NodeStatsIndicesSearchResponse
-	FieldData    NodeStatsIndicesCacheResponse `json:"fielddata"`
-	FilterCache  NodeStatsIndicesCacheResponse `json:"filter_cache"`
-	QueryCache   NodeStatsIndicesCacheResponse `json:"query_cache"`
-	RequestCache NodeStatsIndicesCacheResponse `json:"request_cache"`
+	FieldData    NodeStatsIndicesFieldDataResponse `json:"fielddata"`
+	FilterCache  NodeStatsIndicesCacheResponse     `json:"filter_cache"`
+	QueryCache   NodeStatsIndicesCacheResponse     `json:"query_cache"`
+	RequestCache NodeStatsIndicesCacheResponse     `json:"request_cache"`
 	Flush        NodeStatsIndicesFlushResponse
 	Warmer       NodeStatsIndicesWarmerResponse
 	Segments     NodeStatsIndicesSegmentsResponse
 	Refresh      NodeStatsIndicesRefreshResponse
 	Translog     NodeStatsIndicesTranslogResponse
 	Completion   NodeStatsIndicesCompletionResponse
+	Recovery     NodeStatsIndicesRecoveryResponse
 }
 
 // NodeStatsIndicesDocsResponse defines node stats docs information structure for indices
@@ -156,6 +250,13 @@ type NodeStatsIndicesTranslogResponse struct {
 	Size       int64 `json:"size_in_bytes"`
 }
 
+// NodeStatsIndicesRecoveryResponse defines node stats peer recovery information structure for indices
+type NodeStatsIndicesRecoveryResponse struct {
+	CurrentAsSource int64 `json:"current_as_source"`
+	CurrentAsTarget int64 `json:"current_as_target"`
+	ThrottleTime    int64 `json:"throttle_time_in_millis"`
+}
+
 // NodeStatsIndicesCompletionResponse defines node stats completion information structure for indices
 type NodeStatsIndicesCompletionResponse struct {
 	Size int64 `json:"size_in_bytes"`
@@ -230,6 +331,15 @@ type NodeStatsIndicesSearchResponse struct {
 	SuggestTime  int64 `json:"suggest_time_in_millis"`
 	ScrollTotal  int64 `json:"scroll_total"`
 	ScrollTime   int64 `json:"scroll_time_in_millis"`
+
+	Profile *NodeStatsIndicesSearchProfileResponse `json:"profile"`
+}
+
+// NodeStatsIndicesSearchProfileResponse defines node stats search profiling information structure for indices.
+// Not every version reports it, so it is only set when present in the response.
+type NodeStatsIndicesSearchProfileResponse struct {
+	Total int64 `json:"total"`
+	Time  int64 `json:"time_in_millis"`
 }
 
 // NodeStatsIndicesFlushResponse defines node stats flush information structure for indices
@@ -255,6 +365,19 @@ type NodeStatsIndicesCacheResponse struct {
 	TotalCount int64 `json:"total_count"`
 }
 
+// NodeStatsIndicesFieldDataResponse defines node stats fielddata information structure, the memory per field
+// is only reported for the fields requested with fielddata_fields
+type NodeStatsIndicesFieldDataResponse struct {
+	Evictions  int64                                             `json:"evictions"`
+	MemorySize int64                                             `json:"memory_size_in_bytes"`
+	Fields     map[string]NodeStatsIndicesFieldDataFieldResponse `json:"fields"`
+}
+
+// NodeStatsIndicesFieldDataFieldResponse defines the fielddata memory of a single field
+type NodeStatsIndicesFieldDataFieldResponse struct {
+	MemorySize int64 `json:"memory_size_in_bytes"`
+}
+
 // NodeStatsOSResponse is a representation of a  operating system stats, load average, mem, swap
 type NodeStatsOSResponse struct {
 	Timestamp int64 `json:"timestamp"`
@@ -265,6 +388,8 @@ type NodeStatsOSResponse struct {
 	CPU     NodeStatsOSCPUResponse  `json:"cpu"`
 	Mem     NodeStatsOSMemResponse  `json:"mem"`
 	Swap    NodeStatsOSSwapResponse `json:"swap"`
+	// Cgroup is only reported on Linux when the node runs inside a control group
+	Cgroup *NodeStatsOSCgroupResponse `json:"cgroup"`
 }
 
 // NodeStatsOSMemResponse defines node stats operating system memory usage structure
@@ -281,6 +406,19 @@ type NodeStatsOSSwapResponse struct {
 	Free int64 `json:"free_in_bytes"`
 }
 
+// NodeStatsOSCgroupResponse defines node stats operating system control group structure
+type NodeStatsOSCgroupResponse struct {
+	Memory *NodeStatsOSCgroupMemoryResponse `json:"memory"`
+}
+
+// NodeStatsOSCgroupMemoryResponse defines node stats operating system control group memory usage structure.
+// The values are strings since the limit may be "max" or exceed int64 when unlimited.
+type NodeStatsOSCgroupMemoryResponse struct {
+	ControlGroup string `json:"control_group"`
+	LimitInBytes string `json:"limit_in_bytes"`
+	UsageInBytes string `json:"usage_in_bytes"`
+}
+
 // NodeStatsOSCPUResponse defines node stats operating system CPU usage structure
 type NodeStatsOSCPUResponse struct {
 	Sys     int64                      `json:"sys"`
@@ -325,7 +463,7 @@ type NodeStatsProcessCPUResponse struct {
 // NodeStatsHTTPResponse defines node stats HTTP connections structure
 type NodeStatsHTTPResponse struct {
 	CurrentOpen int64 `json:"current_open"`
-	TotalOpen   int64 `json:"total_open"`
+	TotalOpened int64 `json:"total_opened"`
 }
 
 // NodeStatsFSResponse is a representation of a file system information, data path, free disk space, read/write stats
@@ -348,6 +486,7 @@ type NodeStatsFSDataResponse struct {
 // NodeStatsFSIOStatsResponse defines node stats filesystem device structure
 type NodeStatsFSIOStatsResponse struct {
 	Devices []NodeStatsFSIOStatsDeviceResponse `json:"devices"`
+	Total   NodeStatsFSIOStatsDeviceResponse   `json:"total"`
 }
 
 // NodeStatsFSIOStatsDeviceResponse is a representation of a node stat filesystem device
@@ -3,6 +3,7 @@ package collector
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -10,6 +11,8 @@ import (
 	"testing"
 
 	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestNodesStats(t *testing.T) {
@@ -44,12 +47,42 @@ func TestNodesStats(t *testing.T) {
 				t.Fatalf("Failed to parse URL: %s", err)
 			}
 			u.User = url.UserPassword("elastic", "changeme")
-			c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local")
+			c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
 			nsr, err := c.fetchAndDecodeNodeStats()
 			if err != nil {
 				t.Fatalf("Failed to fetch or decode node stats: %s", err)
 			}
 			t.Logf("[%s/%s] Node Stats Response: %+v", hn, ver, nsr)
+			for _, node := range nsr.Nodes {
+				if node.Indices.Search.Profile != nil {
+					t.Errorf("Search profile stats should be nil when not reported")
+				}
+			}
+			if ver == "5.4.2" {
+				for _, node := range nsr.Nodes {
+					if node.JVM.UptimeInMillis != 185693 {
+						t.Errorf("Wrong jvm uptime")
+					}
+					if node.JVM.BufferPools["direct"].Count != 16 {
+						t.Errorf("Wrong direct buffer pool count")
+					}
+					if node.JVM.BufferPools["direct"].TotalCapacity != 33776599 {
+						t.Errorf("Wrong direct buffer pool total capacity")
+					}
+					for _, gcStats := range node.JVM.GC.Collectors {
+						expected := float64(gcStats.CollectionTime) / 185693 * 100
+						if v := c.gcOverheadMetrics[0].Value(node, gcStats); v != expected {
+							t.Errorf("Wrong gc overhead percent: got %v, expected %v", v, expected)
+						}
+					}
+					if node.FS.IOStats.Total.ReadSize != 24816 {
+						t.Errorf("Wrong total io stats read kilobytes")
+					}
+					if node.FS.IOStats.Total.Operations != 3017 {
+						t.Errorf("Wrong total io stats operations")
+					}
+				}
+			}
 			if nsr.ClusterName == "elasticsearch" {
 				for _, nsnr := range nsr.Nodes {
 					if nsnr.Indices.Docs.Count > 0 {
@@ -116,6 +149,67 @@ func TestNodesStats(t *testing.T) {
 							t.Errorf("CLI should be not ingest")
 						}
 					}
+					if node.Name == "elasticdata-03" {
+						if node.Transport.RxSize != 1032986412750 {
+							t.Errorf("Wrong transport rx size")
+						}
+						if node.Transport.TxSize != 933277781744 {
+							t.Errorf("Wrong transport tx size")
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNodesQuickStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/thread_pool
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"thread_pool":{"search":{"threads":7,"queue":12,"active":7,"rejected":42,"largest":7,"completed":1024},"write":{"threads":4,"queue":3,"active":4,"rejected":17,"largest":4,"completed":512},"replication":{"threads":2,"queue":5,"active":2,"rejected":1,"largest":2,"completed":256}}}}}`,
+		"6.2.4": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["master","data","ingest"],"thread_pool":{"search":{"threads":7,"queue":12,"active":7,"rejected":42,"largest":7,"completed":1024},"bulk":{"threads":4,"queue":3,"active":4,"rejected":17,"largest":4,"completed":512}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/_nodes/stats/thread_pool" {
+				t.Errorf("Unexpected path in quick stats mode: %s", r.URL.Path)
+			}
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", true, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Quick Stats Response: %+v", ver, nsr)
+		for _, node := range nsr.Nodes {
+			expected := []float64{12, 42}
+			for i, metric := range c.searchThreadPoolMetrics {
+				if v := metric.Value(node); v != expected[i] {
+					t.Errorf("Wrong value for search thread pool metric %d: got %v, expected %v", i, v, expected[i])
+				}
+			}
+			expected = []float64{3, 17}
+			for i, metric := range c.writeThreadPoolMetrics {
+				if v := metric.Value(node); v != expected[i] {
+					t.Errorf("Wrong value for write thread pool metric %d: got %v, expected %v", i, v, expected[i])
+				}
+			}
+			if _, ok := node.ThreadPool["replication"]; !ok {
+				continue
+			}
+			expected = []float64{5, 1}
+			for i, metric := range c.replicationThreadPoolMetrics {
+				if v := metric.Value(node); v != expected[i] {
+					t.Errorf("Wrong value for replication thread pool metric %d: got %v, expected %v", i, v, expected[i])
 				}
 			}
 		}
@@ -160,3 +254,733 @@ func (h *basicAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	h.Next.ServeHTTP(w, r)
 }
+
+func TestNodesIndexingPressure(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/indexing_pressure
+	tcs := map[string]string{
+		"7.9.0": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1598918400000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["data","ingest","master","ml","remote_cluster_client","transform"],"indexing_pressure":{"memory":{"current":{"combined_coordinating_and_primary_in_bytes":2048,"coordinating_in_bytes":1024,"primary_in_bytes":1024,"replica_in_bytes":512,"all_in_bytes":2560},"total":{"combined_coordinating_and_primary_in_bytes":1048576,"coordinating_in_bytes":524288,"primary_in_bytes":524288,"replica_in_bytes":262144,"all_in_bytes":1310720,"coordinating_rejections":3,"primary_rejections":2,"replica_rejections":1},"limit_in_bytes":107374182}}}}}`,
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"]}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Indexing Pressure Response: %+v", ver, nsr)
+		for _, node := range nsr.Nodes {
+			if ver == "7.6.2" {
+				if node.IndexingPressure != nil {
+					t.Errorf("Indexing pressure should be nil before 7.9")
+				}
+				continue
+			}
+			if node.IndexingPressure == nil {
+				t.Fatalf("Indexing pressure should be set")
+			}
+			expected := []float64{2560, 1024, 1024, 512, 3, 2, 1}
+			for i, metric := range c.indexingPressureMetrics {
+				if v := metric.Value(node); v != expected[i] {
+					t.Errorf("Wrong value for indexing pressure metric %d: got %v, expected %v", i, v, expected[i])
+				}
+			}
+		}
+	}
+}
+
+func TestNodesOSSwapAndCgroup(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -m 2g -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/os
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"os":{"timestamp":1585735200000,"cpu":{"percent":3,"load_average":{"1m":0.5,"5m":0.4,"15m":0.3}},"mem":{"total_in_bytes":8348520448,"free_in_bytes":1048576000,"used_in_bytes":7299944448,"free_percent":13,"used_percent":87},"swap":{"total_in_bytes":1073741824,"free_in_bytes":1040187392,"used_in_bytes":33554432},"cgroup":{"cpuacct":{"control_group":"/","usage_nanos":253742516},"cpu":{"control_group":"/","cfs_period_micros":100000,"cfs_quota_micros":-1,"stat":{"number_of_elapsed_periods":0,"number_of_times_throttled":0,"time_throttled_nanos":0}},"memory":{"control_group":"/","limit_in_bytes":"2147483648","usage_in_bytes":"1610612736"}}}}}}`,
+		"5.4.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"bbMJ7Dq4Q7GNJ0-jMClmUQ":{"timestamp":1498740000000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["master","data","ingest"],"os":{"timestamp":1498740000000,"cpu":{"percent":3,"load_average":{"1m":0.5,"5m":0.4,"15m":0.3}},"mem":{"total_in_bytes":8348520448,"free_in_bytes":1048576000,"used_in_bytes":7299944448,"free_percent":13,"used_percent":87},"swap":{"total_in_bytes":0,"free_in_bytes":0,"used_in_bytes":0}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node OS Response: %+v", ver, nsr)
+		for _, node := range nsr.Nodes {
+			if ver == "5.4.2" {
+				if node.OS.Swap.Used != 0 {
+					t.Errorf("Wrong swap used")
+				}
+				if node.OS.Cgroup != nil {
+					t.Errorf("Cgroup should be nil outside a control group")
+				}
+				continue
+			}
+			if node.OS.Swap.Used != 33554432 {
+				t.Errorf("Wrong swap used")
+			}
+			if node.OS.Swap.Free != 1040187392 {
+				t.Errorf("Wrong swap free")
+			}
+			if node.OS.Cgroup == nil || node.OS.Cgroup.Memory == nil {
+				t.Fatalf("Cgroup memory should be set")
+			}
+			expected := []float64{2147483648, 1610612736}
+			for i, metric := range c.cgroupMemoryMetrics {
+				if v := metric.Value(node); v != expected[i] {
+					t.Errorf("Wrong value for cgroup memory metric %d: got %v, expected %v", i, v, expected[i])
+				}
+			}
+		}
+	}
+	if v := parseCgroupBytes("max"); !math.IsInf(v, 1) {
+		t.Errorf("Unlimited cgroup memory should be +Inf, got %v", v)
+	}
+}
+
+func TestNodesRequestBreaker(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/breaker
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"breakers":{"request":{"limit_size_in_bytes":644245094,"limit_size":"614.3mb","estimated_size_in_bytes":0,"estimated_size":"0b","overhead":1.0,"tripped":7},"fielddata":{"limit_size_in_bytes":429496729,"limit_size":"409.5mb","estimated_size_in_bytes":0,"estimated_size":"0b","overhead":1.03,"tripped":2},"in_flight_requests":{"limit_size_in_bytes":1073741824,"limit_size":"1gb","estimated_size_in_bytes":0,"estimated_size":"0b","overhead":2.0,"tripped":0},"parent":{"limit_size_in_bytes":1020054732,"limit_size":"972.7mb","estimated_size_in_bytes":215454592,"estimated_size":"205.4mb","overhead":1.0,"tripped":0}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Breaker Response: %+v", ver, nsr)
+		for _, node := range nsr.Nodes {
+			if v := c.requestBreakerMetrics[0].Value(node); v != 7 {
+				t.Errorf("Wrong request breaker tripped count: got %v, expected 7", v)
+			}
+		}
+	}
+}
+
+func TestNodesRecoveryThrottle(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_cluster/settings -H 'Content-Type: application/json' -d '{"transient":{"indices.recovery.max_bytes_per_sec":"1mb"}}'
+	//  curl -XPUT http://localhost:9200/foo_1/_settings -H 'Content-Type: application/json' -d '{"number_of_replicas":1}' (with a second node joining)
+	//  curl http://localhost:9200/_nodes/stats/indices/recovery
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"indices":{"recovery":{"current_as_source":1,"current_as_target":0,"throttle_time_in_millis":45500}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Recovery Response: %+v", ver, nsr)
+		for _, node := range nsr.Nodes {
+			if node.Indices.Recovery.ThrottleTime != 45500 {
+				t.Errorf("Wrong recovery throttle time: %d", node.Indices.Recovery.ThrottleTime)
+			}
+			expected := map[string]float64{
+				"elasticsearch_node_recovery_current_as_source":           1,
+				"elasticsearch_node_recovery_current_as_target":           0,
+				"elasticsearch_node_recovery_throttle_time_seconds_total": 45.5,
+			}
+			found := 0
+			for _, metric := range c.nodeMetrics {
+				for name, value := range expected {
+					if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+						continue
+					}
+					found++
+					if v := metric.Value(node); v != value {
+						t.Errorf("Wrong value for %s: got %v, expected %v", name, v, value)
+					}
+				}
+			}
+			if found != len(expected) {
+				t.Errorf("Expected %d recovery metrics, found %d", len(expected), found)
+			}
+		}
+	}
+}
+
+func TestNodesJVMMemoryPools(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/jvm
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"jvm":{"timestamp":1585735200000,"uptime_in_millis":3600000,"mem":{"heap_used_in_bytes":650117120,"heap_used_percent":60,"heap_committed_in_bytes":1073741824,"heap_max_in_bytes":1073741824,"non_heap_used_in_bytes":150000000,"non_heap_committed_in_bytes":160000000,"pools":{"young":{"used_in_bytes":100663296,"max_in_bytes":279183360,"peak_used_in_bytes":279183360,"peak_max_in_bytes":279183360},"survivor":{"used_in_bytes":10485760,"max_in_bytes":34865152,"peak_used_in_bytes":34865152,"peak_max_in_bytes":34865152},"old":{"used_in_bytes":538968064,"max_in_bytes":759693312,"peak_used_in_bytes":600000000,"peak_max_in_bytes":759693312}}}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node JVM Response: %+v", ver, nsr)
+		expected := map[string][]float64{
+			"young":    {100663296, 279183360, 279183360},
+			"survivor": {10485760, 34865152, 34865152},
+			"old":      {538968064, 759693312, 600000000},
+		}
+		names := []string{"used_bytes", "max_bytes", "peak_used_bytes"}
+		for _, node := range nsr.Nodes {
+			found := 0
+			for _, metric := range c.nodeMetrics {
+				labels := metric.Labels(nsr.ClusterName, node)
+				pool := labels[len(labels)-1]
+				values, ok := expected[pool]
+				if !ok {
+					continue
+				}
+				for i, name := range names {
+					if !strings.Contains(metric.Desc.String(), `"elasticsearch_jvm_memory_pool_`+name+`"`) {
+						continue
+					}
+					found++
+					if v := metric.Value(node); v != values[i] {
+						t.Errorf("Wrong %s for pool %s: got %v, expected %v", name, pool, v, values[i])
+					}
+				}
+			}
+			if found != len(expected)*len(names) {
+				t.Errorf("Expected %d JVM memory pool metrics, found %d", len(expected)*len(names), found)
+			}
+		}
+	}
+}
+
+func TestNodesProcess(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/process
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"process":{"timestamp":1585735200000,"open_file_descriptors":312,"max_file_descriptors":1048576,"cpu":{"percent":37,"total_in_millis":84500},"mem":{"total_virtual_in_bytes":5368709120}}}}}`,
+		"5.4.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1498820641883,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["master","data","ingest"],"process":{"timestamp":1498820641883,"open_file_descriptors":312,"max_file_descriptors":1048576,"cpu":{"percent":37,"total_in_millis":84500},"mem":{"total_virtual_in_bytes":5368709120}}}}}`,
+	}
+	expected := map[string]float64{
+		"elasticsearch_process_cpu_percent":            37,
+		"elasticsearch_process_mem_virtual_size_bytes": 5368709120,
+		"elasticsearch_process_open_files_count":       312,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Process Response: %+v", ver, nsr)
+		for _, node := range nsr.Nodes {
+			found := 0
+			for _, metric := range c.nodeMetrics {
+				for name, value := range expected {
+					if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+						continue
+					}
+					found++
+					if v := metric.Value(node); v != value {
+						t.Errorf("Wrong value for %s: got %v, expected %v", name, v, value)
+					}
+				}
+			}
+			if found != len(expected) {
+				t.Errorf("Expected %d process metrics, found %d", len(expected), found)
+			}
+		}
+	}
+}
+
+func TestNodesQueryCache(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/indices/query_cache
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"indices":{"query_cache":{"memory_size_in_bytes":1048576,"total_count":1000,"hit_count":750,"miss_count":250,"cache_size":120,"cache_count":150,"evictions":30}}}}}`,
+	}
+	expected := map[string]float64{
+		"elasticsearch_indices_query_cache_memory_size_bytes": 1048576,
+		"elasticsearch_indices_query_cache_total":             1000,
+		"elasticsearch_indices_query_cache_count":             750,
+		"elasticsearch_indices_query_miss_count":              250,
+		"elasticsearch_indices_query_cache_evictions":         30,
+		"elasticsearch_indices_query_cache_cache_total":       150,
+		"elasticsearch_indices_query_cache_cache_size":        120,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Query Cache Response: %+v", ver, nsr)
+		for _, node := range nsr.Nodes {
+			found := 0
+			for _, metric := range c.nodeMetrics {
+				for name, value := range expected {
+					if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+						continue
+					}
+					found++
+					if v := metric.Value(node); v != value {
+						t.Errorf("Wrong value for %s: got %v, expected %v", name, v, value)
+					}
+				}
+			}
+			if found != len(expected) {
+				t.Errorf("Expected %d query cache metrics, found %d", len(expected), found)
+			}
+		}
+	}
+}
+
+func TestNodesThreadPoolMaxQueueSize(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "thread_pool.write.queue_size=500" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/_all/thread_pool,http,transport
+	tcs := map[string]string{
+		"6.8.8": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1","version":"6.8.8","roles":["master","data","ingest"],"thread_pool":{"search":{"type":"fixed_auto_queue_size","min":7,"max":7,"queue_size":1000},"write":{"type":"fixed","min":4,"max":4,"queue_size":500},"generic":{"type":"scaling","min":4,"max":128,"keep_alive":"30s","queue_size":-1}}}}}`,
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1","version":"7.6.2","roles":["ingest","master","data","ml"],"thread_pool":{"search":{"type":"fixed_auto_queue_size","size":7,"queue_size":1000},"write":{"type":"fixed","size":4,"queue_size":500},"generic":{"type":"scaling","core":4,"max":128,"keep_alive":"30s","queue_size":-1}}}}}`,
+	}
+	stats := `{"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0","host":"127.0.0.1","roles":["master","data"],"thread_pool":{"search":{"threads":7,"queue":12,"active":0,"rejected":0,"largest":7,"completed":100}}}}}`
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/_nodes/_all/thread_pool,http,transport" {
+				fmt.Fprintln(w, out)
+				return
+			}
+			fmt.Fprintln(w, stats)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nir, err := c.fetchAndDecodeNodeInfo()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node info: %s", err)
+		}
+		t.Logf("[%s] Node Info Response: %+v", ver, nir)
+		pools := nir.Nodes["9_P7yui4SQOkzGhTZCyjxQ"].ThreadPool
+		if pools["write"].QueueSize != 500 {
+			t.Errorf("Wrong write thread pool queue size: %d", pools["write"].QueueSize)
+		}
+		if pools["generic"].QueueSize != -1 {
+			t.Errorf("Wrong generic thread pool queue size: %d", pools["generic"].QueueSize)
+		}
+
+		ch := make(chan prometheus.Metric)
+		go func() {
+			c.Collect(ch)
+			close(ch)
+		}()
+		expected := map[string]float64{"search": 1000, "write": 500}
+		found := 0
+		for metric := range ch {
+			if !strings.Contains(metric.Desc().String(), `"elasticsearch_thread_pool_max_queue_size"`) {
+				continue
+			}
+			found++
+			var m dto.Metric
+			if err := metric.Write(&m); err != nil {
+				t.Fatalf("Failed to write metric: %s", err)
+			}
+			for _, label := range m.GetLabel() {
+				if label.GetName() != "type" {
+					continue
+				}
+				if v, ok := expected[label.GetValue()]; !ok || v != m.GetGauge().GetValue() {
+					t.Errorf("[%s] Wrong max queue size for thread pool %s: %v", ver, label.GetValue(), m.GetGauge().GetValue())
+				}
+			}
+		}
+		if found != len(expected) {
+			t.Errorf("[%s] Expected %d max queue size metrics, unbounded queues should be skipped, found %d", ver, len(expected), found)
+		}
+	}
+}
+
+func TestNodesFieldDataFields(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"mappings":{"properties":{"user":{"type":"text","fielddata":true},"message":{"type":"text","fielddata":true}}}}'
+	//  curl -XPOST http://localhost:9200/twitter/_search -H 'Content-Type: application/json' -d '{"aggs":{"users":{"terms":{"field":"user"}},"words":{"terms":{"field":"message"}}}}'
+	//  curl 'http://localhost:9200/_nodes/stats?fielddata_fields=user,mess*'
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"indices":{"fielddata":{"memory_size_in_bytes":3072,"evictions":2,"fields":{"user":{"memory_size_in_bytes":1024},"message":{"memory_size_in_bytes":2048}}}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/_nodes/stats" && r.URL.Query().Get("fielddata_fields") != "user,mess*" {
+				t.Errorf("Wrong fielddata_fields parameter %q", r.URL.Query().Get("fielddata_fields"))
+			}
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "user,mess*", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Fielddata Response: %+v", ver, nsr)
+		fielddata := nsr.Nodes["9_P7yui4SQOkzGhTZCyjxQ"].Indices.FieldData
+		if fielddata.MemorySize != 3072 || fielddata.Evictions != 2 {
+			t.Errorf("Wrong fielddata totals: %+v", fielddata)
+		}
+
+		ch := make(chan prometheus.Metric)
+		go func() {
+			c.Collect(ch)
+			close(ch)
+		}()
+		expected := map[string]float64{"user": 1024, "message": 2048}
+		found := 0
+		for metric := range ch {
+			if !strings.Contains(metric.Desc().String(), `"elasticsearch_node_field_data_memory_bytes"`) {
+				continue
+			}
+			found++
+			var m dto.Metric
+			if err := metric.Write(&m); err != nil {
+				t.Fatalf("Failed to write metric: %s", err)
+			}
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["node_id"] != "9_P7yui4SQOkzGhTZCyjxQ" || labels["node_name"] != "node-0" {
+				t.Errorf("Wrong node labels %v", labels)
+			}
+			if v := m.GetGauge().GetValue(); v != expected[labels["field_name"]] {
+				t.Errorf("Wrong fielddata memory for field %s: %v", labels["field_name"], v)
+			}
+		}
+		if found != len(expected) {
+			t.Errorf("Expected %d fielddata field metrics, found %d", len(expected), found)
+		}
+	}
+}
+
+func TestNodesSearchSuggest(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/music -H 'Content-Type: application/json' -d '{"mappings":{"properties":{"suggest":{"type":"completion"}}}}'
+	//  curl -XPOST 'http://localhost:9200/music/_search' -H 'Content-Type: application/json' -d '{"suggest":{"song":{"prefix":"nir","completion":{"field":"suggest"}}}}' (repeated)
+	//  curl http://localhost:9200/_nodes/stats/indices/search
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1585735200000,"name":"node-0","transport_address":"127.0.0.1:9300","host":"127.0.0.1","ip":"127.0.0.1:9300","roles":["ingest","master","data","ml"],"indices":{"search":{"open_contexts":0,"query_total":48,"query_time_in_millis":127,"query_current":0,"fetch_total":48,"fetch_time_in_millis":31,"fetch_current":0,"scroll_total":0,"scroll_time_in_millis":0,"scroll_current":0,"suggest_total":42,"suggest_time_in_millis":2500,"suggest_current":0}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Search Response: %+v", ver, nsr)
+
+		expected := map[string]float64{
+			"elasticsearch_indices_search_suggest_total":        42,
+			"elasticsearch_indices_search_suggest_time_seconds": 2.5,
+		}
+		for _, node := range nsr.Nodes {
+			var found int
+			for _, metric := range c.nodeMetrics {
+				for name, want := range expected {
+					if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+						continue
+					}
+					found++
+					if v := metric.Value(node); v != want {
+						t.Errorf("Wrong value for %s: got %v, expected %v", name, v, want)
+					}
+				}
+			}
+			if found != len(expected) {
+				t.Errorf("Missing suggest metrics")
+			}
+		}
+	}
+}
+
+func TestNodesHTTP(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION (and a second node with http.enabled=false)
+	//  curl http://localhost:9200/_nodes/stats/http
+	tcs := map[string]string{
+		"7.13.4": `{"_nodes":{"total":2,"successful":2,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1627984800000,"name":"node-0","transport_address":"172.17.0.2:9300","host":"172.17.0.2","ip":"172.17.0.2:9300","roles":["data","ingest","master"],"http":{"current_open":3,"total_opened":1250,"clients":[{"id":1120,"agent":"Prometheus/2.28.1","local_address":"172.17.0.2:9200","remote_address":"172.17.0.9:43210","last_uri":"/_nodes/stats","opened_time_millis":1627984700000,"last_request_time_millis":1627984790000,"request_count":12,"request_size_bytes":0}]}},"Jx0Vt0hTR0iVbVGRx1oBCA":{"timestamp":1627984800000,"name":"node-1","transport_address":"172.17.0.3:9300","host":"172.17.0.3","ip":"172.17.0.3:9300","roles":["data","ingest","master"]}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node HTTP Response: %+v", ver, nsr)
+
+		expected := map[string]map[string]float64{
+			"node-0": {
+				"elasticsearch_node_http_connections_current_open": 3,
+				"elasticsearch_node_http_connections_opened_total": 1250,
+			},
+			// HTTP is disabled on node-1
+			"node-1": {
+				"elasticsearch_node_http_connections_current_open": 0,
+				"elasticsearch_node_http_connections_opened_total": 0,
+			},
+		}
+		for _, node := range nsr.Nodes {
+			var found int
+			for _, metric := range c.nodeMetrics {
+				for name, want := range expected[node.Name] {
+					if !strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+						continue
+					}
+					found++
+					if v := metric.Value(node); v != want {
+						t.Errorf("Wrong value for %s of %s: got %v, expected %v", name, node.Name, v, want)
+					}
+				}
+			}
+			if found != len(expected[node.Name]) {
+				t.Errorf("Missing HTTP metrics")
+			}
+			if client := getRoles(node)["client"]; client != (node.Name == "node-0") {
+				t.Errorf("Wrong client role of %s: %v", node.Name, client)
+			}
+		}
+	}
+}
+
+func TestNodesDiscovery(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/stats/discovery
+	tcs := map[string]string{
+		"7.10.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1627984800000,"name":"node-0","transport_address":"172.17.0.2:9300","host":"172.17.0.2","ip":"172.17.0.2:9300","roles":["data","ingest","master"],"discovery":{"cluster_state_queue":{"total":2,"pending":1,"committed":1},"published_cluster_states":{"full_states":2,"incompatible_diffs":0,"compatible_diffs":15}}}}}`,
+		"7.17.1": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"timestamp":1627984800000,"name":"node-0","transport_address":"172.17.0.2:9300","host":"172.17.0.2","ip":"172.17.0.2:9300","roles":["data","ingest","master"],"discovery":{"cluster_state_queue":{"total":2,"pending":1,"committed":1},"published_cluster_states":{"full_states":2,"incompatible_diffs":0,"compatible_diffs":15},"serialized_cluster_states":{"full_states":{"count":2,"uncompressed_size_in_bytes":7360,"compressed_size_in_bytes":2285},"diffs":{"count":15,"uncompressed_size_in_bytes":9120,"compressed_size_in_bytes":4350}},"cluster_applier_stats":{"recordings":[{"name":"org.elasticsearch.indices.cluster.IndicesClusterStateService@5c6c2c6f","cumulative_execution_count":17,"cumulative_execution_time_millis":230},{"name":"running task [create-index [twitter]]","cumulative_execution_count":1,"cumulative_execution_time_millis":12}]}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+		nsr, err := c.fetchAndDecodeNodeStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode node stats: %s", err)
+		}
+		t.Logf("[%s] Node Discovery Response: %+v", ver, nsr)
+
+		for _, node := range nsr.Nodes {
+			if node.Discovery == nil {
+				t.Fatalf("[%s] Missing discovery stats", ver)
+			}
+			expected := map[string]float64{
+				"elasticsearch_node_discovery_cluster_applier_stats_queue_size": 2,
+				"elasticsearch_node_discovery_committed_cluster_states_count":   1,
+			}
+			for _, metric := range c.discoveryMetrics {
+				for name, want := range expected {
+					if strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+						if v := metric.Value(node); v != want {
+							t.Errorf("[%s] Wrong value for %s: got %v, expected %v", ver, name, v, want)
+						}
+					}
+				}
+			}
+
+			// the serialized cluster states and applier stats are only reported since 7.16
+			if ver == "7.10.2" {
+				if node.Discovery.SerializedClusterStates != nil || node.Discovery.ClusterApplierStats != nil {
+					t.Errorf("[%s] Unexpected publication stats", ver)
+				}
+				continue
+			}
+			expected = map[string]float64{
+				"elasticsearch_node_discovery_cluster_applier_stats_recordings_count": 18,
+				"elasticsearch_node_discovery_serialized_cluster_states_count":        17,
+			}
+			for _, metric := range c.discoveryPublicationMetrics {
+				for name, want := range expected {
+					if strings.Contains(metric.Desc.String(), `"`+name+`"`) {
+						if v := metric.Value(node); v != want {
+							t.Errorf("[%s] Wrong value for %s: got %v, expected %v", ver, name, v, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNodesBoundAddresses(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "network.bind_host=0.0.0.0" -e "network.publish_host=172.17.0.2" elasticsearch:VERSION
+	//  curl http://localhost:9200/_nodes/_all/thread_pool,http,transport
+	tcs := map[string]string{
+		"7.10.2": `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0","transport_address":"172.17.0.2:9300","host":"172.17.0.2","ip":"172.17.0.2","version":"7.10.2","roles":["data","ingest","master"],"thread_pool":{},"transport":{"bound_address":["[::]:9300","0.0.0.0:9300"],"publish_address":"172.17.0.2:9300","profiles":{}},"http":{"bound_address":["[::]:9200","0.0.0.0:9200"],"publish_address":"172.17.0.2:9200","max_content_length_in_bytes":104857600}}}}`,
+	}
+	stats := `{"cluster_name":"elasticsearch","nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0","host":"172.17.0.2","roles":["master","data"]}}}`
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/_nodes/_all/thread_pool,http,transport" {
+				fmt.Fprintln(w, out)
+				return
+			}
+			fmt.Fprintln(w, stats)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewNodes(log.NewNopLogger(), http.DefaultClient, u, true, "_local", false, "", nil)
+
+		ch := make(chan prometheus.Metric)
+		go func() {
+			c.Collect(ch)
+			close(ch)
+		}()
+		expected := map[string]map[string]string{
+			"elasticsearch_node_http_bound_address_info":      {"[::]:9200": "172.17.0.2:9200", "0.0.0.0:9200": "172.17.0.2:9200"},
+			"elasticsearch_node_transport_bound_address_info": {"[::]:9300": "172.17.0.2:9300", "0.0.0.0:9300": "172.17.0.2:9300"},
+		}
+		found := 0
+		for metric := range ch {
+			for name, addresses := range expected {
+				if !strings.Contains(metric.Desc().String(), `"`+name+`"`) {
+					continue
+				}
+				found++
+				var m dto.Metric
+				if err := metric.Write(&m); err != nil {
+					t.Fatalf("Failed to write metric: %s", err)
+				}
+				labels := map[string]string{}
+				for _, label := range m.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				if publish, ok := addresses[labels["bound_address"]]; !ok || publish != labels["publish_address"] {
+					t.Errorf("[%s] Wrong addresses of %s: %v", ver, name, labels)
+				}
+				if labels["node_id"] != "9_P7yui4SQOkzGhTZCyjxQ" || labels["node_name"] != "node-0" {
+					t.Errorf("[%s] Wrong node labels of %s: %v", ver, name, labels)
+				}
+			}
+		}
+		if found != 4 {
+			t.Errorf("[%s] Wrong number of bound address metrics: %d", ver, found)
+		}
+	}
+}
+
+func TestNodesSearchProfilingIncreased(t *testing.T) {
+	c := NewNodes(log.NewNopLogger(), http.DefaultClient, &url.URL{}, true, "_local", false, "", nil)
+	// profiled requests of one node over consecutive scrapes, the node restarts before the last one
+	for i, tc := range []struct {
+		profiledRequests int64
+		increased        bool
+	}{
+		{0, false},
+		{5, true},
+		{5, false},
+		{7, true},
+		{2, true},
+	} {
+		if increased := c.searchProfilingIncreased("9_P7yui4SQOkzGhTZCyjxQ", tc.profiledRequests); increased != tc.increased {
+			t.Errorf("Wrong profiling increase on scrape %d: got %t, expected %t", i, increased, tc.increased)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pendingTasksAgeBuckets = []float64{1, 5, 10, 30, 60, 120, 300}
+)
+
+// pendingTasksAgeHistogram holds the data of a constant histogram
+type pendingTasksAgeHistogram struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+// PendingTasks information struct
+type PendingTasks struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	age *prometheus.Desc
+}
+
+// NewPendingTasks defines PendingTasks Prometheus metrics
+func NewPendingTasks(logger log.Logger, client *http.Client, url *url.URL) *PendingTasks {
+	constLabels := constLabelsFromURL(url)
+	return &PendingTasks{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "pending_tasks", "up"),
+			Help:        "Was the last scrape of the ElasticSearch pending tasks endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "pending_tasks", "total_scrapes"),
+			Help:        "Current total ElasticSearch pending tasks scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "pending_tasks", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		age: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pending_tasks", "age_seconds"),
+			"Time the pending cluster tasks have been waiting in the queue in seconds",
+			[]string{"priority"}, constLabels,
+		),
+	}
+}
+
+// Describe add PendingTasks metrics descriptions
+func (pt *PendingTasks) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pt.age
+	ch <- pt.up.Desc()
+	ch <- pt.totalScrapes.Desc()
+	ch <- pt.jsonParseFailures.Desc()
+}
+
+func (pt *PendingTasks) fetchAndDecodePendingTasks() (PendingTasksResponse, error) {
+	var ptr PendingTasksResponse
+
+	u := *pt.url
+	u.Path = path.Join(u.Path, "/_cluster/pending_tasks")
+	err := getAndDecodeURL(pt.logger, pt.client, &u, &ptr, pt.jsonParseFailures)
+	return ptr, err
+}
+
+// pendingTasksAgeHistograms aggregates the time in queue of the pending tasks by priority
+func pendingTasksAgeHistograms(tasks []PendingTaskResponse) map[string]*pendingTasksAgeHistogram {
+	histograms := make(map[string]*pendingTasksAgeHistogram)
+	for _, task := range tasks {
+		histogram, ok := histograms[task.Priority]
+		if !ok {
+			histogram = &pendingTasksAgeHistogram{buckets: make(map[float64]uint64, len(pendingTasksAgeBuckets))}
+			for _, bucket := range pendingTasksAgeBuckets {
+				histogram.buckets[bucket] = 0
+			}
+			histograms[task.Priority] = histogram
+		}
+
+		age := float64(task.TimeInQueueMillis) / 1000
+		histogram.count++
+		histogram.sum += age
+		for _, bucket := range pendingTasksAgeBuckets {
+			if age <= bucket {
+				histogram.buckets[bucket]++
+			}
+		}
+	}
+	return histograms
+}
+
+// Collect gets PendingTasks metric values
+func (pt *PendingTasks) Collect(ch chan<- prometheus.Metric) {
+	pt.totalScrapes.Inc()
+	defer func() {
+		ch <- pt.up
+		ch <- pt.totalScrapes
+		ch <- pt.jsonParseFailures
+	}()
+
+	pendingTasksResp, err := pt.fetchAndDecodePendingTasks()
+	if err != nil {
+		pt.up.Set(0)
+		_ = level.Warn(pt.logger).Log(
+			"msg", "failed to fetch and decode pending tasks",
+			"err", err,
+		)
+		return
+	}
+	pt.up.Set(1)
+
+	for priority, histogram := range pendingTasksAgeHistograms(pendingTasksResp.Tasks) {
+		ch <- prometheus.MustNewConstHistogram(
+			pt.age,
+			histogram.count,
+			histogram.sum,
+			histogram.buckets,
+			priority,
+		)
+	}
+}
@@ -0,0 +1,15 @@
+package collector
+
+// PendingTasksResponse is a representation of the cluster level changes which have not yet been executed
+type PendingTasksResponse struct {
+	Tasks []PendingTaskResponse `json:"tasks"`
+}
+
+// PendingTaskResponse is a representation of a single pending cluster task
+type PendingTaskResponse struct {
+	InsertOrder       int64  `json:"insert_order"`
+	Priority          string `json:"priority"`
+	Source            string `json:"source"`
+	Executing         bool   `json:"executing"`
+	TimeInQueueMillis int64  `json:"time_in_queue_millis"`
+}
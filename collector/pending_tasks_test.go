@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestPendingTasks(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  for i in $(seq 1 100); do curl -XPUT http://localhost:9200/foo_$i & done
+	//  curl http://localhost:9200/_cluster/pending_tasks
+	tcs := map[string]string{
+		"7.6.2": `{"tasks":[{"insert_order":101,"priority":"URGENT","source":"create-index [foo_9], cause [api]","executing":true,"time_in_queue_millis":3000,"time_in_queue":"3s"},{"insert_order":46,"priority":"HIGH","source":"shard-started StartedShardEntry{shardId [[foo_2][1]], allocationId [Xvk-DVe4SZSlhHmyU-CTnA], message [after new shard recovery]}","executing":false,"time_in_queue_millis":842,"time_in_queue":"842ms"},{"insert_order":45,"priority":"HIGH","source":"shard-started StartedShardEntry{shardId [[foo_2][0]], allocationId [5H7ZbK4qRHmvYV1yyyXvfA], message [after new shard recovery]}","executing":false,"time_in_queue_millis":45000,"time_in_queue":"45s"},{"insert_order":12,"priority":"LANGUID","source":"cluster_reroute(reroute after starting shards)","executing":false,"time_in_queue_millis":400000,"time_in_queue":"6.6m"}]}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		pt := NewPendingTasks(log.NewNopLogger(), http.DefaultClient, u)
+		ptr, err := pt.fetchAndDecodePendingTasks()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode pending tasks: %s", err)
+		}
+		t.Logf("[%s] Pending Tasks Response: %+v", ver, ptr)
+		if len(ptr.Tasks) != 4 {
+			t.Fatalf("Wrong number of pending tasks")
+		}
+
+		histograms := pendingTasksAgeHistograms(ptr.Tasks)
+		if len(histograms) != 3 {
+			t.Fatalf("Wrong number of priorities")
+		}
+		high := histograms["HIGH"]
+		if high == nil || high.count != 2 {
+			t.Fatalf("Wrong number of HIGH pending tasks")
+		}
+		if high.sum != 45.842 {
+			t.Errorf("Wrong sum of HIGH pending task ages: %v", high.sum)
+		}
+		if high.buckets[1] != 1 || high.buckets[30] != 1 || high.buckets[60] != 2 {
+			t.Errorf("Wrong HIGH pending task age buckets: %v", high.buckets)
+		}
+		if histograms["URGENT"].buckets[1] != 0 || histograms["URGENT"].buckets[5] != 1 {
+			t.Errorf("Wrong URGENT pending task age buckets: %v", histograms["URGENT"].buckets)
+		}
+		if histograms["LANGUID"].buckets[300] != 0 {
+			t.Errorf("LANGUID pending task waiting 400s should exceed all buckets")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Ping information struct
+type Ping struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	status *prometheus.Desc
+}
+
+// NewPing defines Ping Prometheus metrics, the client should use a short timeout
+// so an unreachable endpoint is reported quickly
+func NewPing(logger log.Logger, client *http.Client, url *url.URL) *Ping {
+	constLabels := constLabelsFromURL(url)
+	return &Ping{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		status: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "connectivity", "status"),
+			"Whether the Elasticsearch endpoint answered the last ping (1=reachable, 0=unreachable)",
+			[]string{"endpoint"}, constLabels,
+		),
+	}
+}
+
+// Describe add Ping metrics descriptions
+func (p *Ping) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.status
+}
+
+func (p *Ping) ping() error {
+	u := *p.url
+	u.Path = path.Join(u.Path, "/")
+	res, err := p.client.Get(u.String())
+	if err != nil {
+		return fmt.Errorf("failed to get from %s://%s:%s%s: %s",
+			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
+	}
+
+	defer func() {
+		err = res.Body.Close()
+		if err != nil {
+			_ = level.Warn(p.logger).Log(
+				"msg", "failed to close http.Client",
+				"err", err,
+			)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Collect gets Ping metric values
+func (p *Ping) Collect(ch chan<- prometheus.Metric) {
+	status := 1.0
+	if err := p.ping(); err != nil {
+		status = 0
+		_ = level.Warn(p.logger).Log(
+			"msg", "failed to ping elasticsearch endpoint",
+			"endpoint", p.url.Host,
+			"err", err,
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		p.status,
+		prometheus.GaugeValue,
+		status,
+		p.url.Host,
+	)
+}
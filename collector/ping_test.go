@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestPing(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"name":"node-0","cluster_name":"elasticsearch","version":{"number":"7.6.2"},"tagline":"You Know, for Search"}`)
+	}))
+	defer ok.Close()
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	tcs := map[string]float64{
+		ok.URL:          1,
+		unavailable.URL: 0,
+		slow.URL:        0,
+	}
+	for endpoint, expected := range tcs {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		p := NewPing(log.NewNopLogger(), client, u)
+		ch := make(chan prometheus.Metric, 1)
+		p.Collect(ch)
+		var m dto.Metric
+		if err := (<-ch).Write(&m); err != nil {
+			t.Fatalf("Failed to write metric: %s", err)
+		}
+		if v := m.GetGauge().GetValue(); v != expected {
+			t.Errorf("Wrong connectivity status for %s: got %v, expected %v", endpoint, v, expected)
+		}
+		if label := m.GetLabel()[1]; label.GetName() != "endpoint" || label.GetValue() != u.Host {
+			t.Errorf("Wrong endpoint label %s=%s", label.GetName(), label.GetValue())
+		}
+	}
+}
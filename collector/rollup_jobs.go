@@ -0,0 +1,208 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type rollupJobMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(rollupJob RollupJobResponse) float64
+	Labels func(rollupJob RollupJobResponse) []string
+}
+
+var (
+	rollupJobStates = map[string]float64{
+		"started":  1,
+		"indexing": 1,
+		"stopped":  0,
+		"failed":   -1,
+	}
+
+	defaultRollupJobLabels      = []string{"job_id", "index_pattern", "rollup_index"}
+	defaultRollupJobLabelValues = func(rollupJob RollupJobResponse) []string {
+		return []string{rollupJob.Config.ID, rollupJob.Config.IndexPattern, rollupJob.Config.RollupIndex}
+	}
+)
+
+// RollupJobs information struct
+type RollupJobs struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	rollupJobMetrics []*rollupJobMetric
+}
+
+// NewRollupJobs defines Rollup Jobs Prometheus metrics
+func NewRollupJobs(logger log.Logger, client *http.Client, url *url.URL) *RollupJobs {
+	constLabels := constLabelsFromURL(url)
+	return &RollupJobs{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "rollup_job", "up"),
+			Help:        "Was the last scrape of the ElasticSearch rollup jobs endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "rollup_job", "total_scrapes"),
+			Help:        "Current total ElasticSearch rollup jobs scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "rollup_job", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		rollupJobMetrics: []*rollupJobMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "rollup_job", "state"),
+					"Rollup job state (started=1, stopped=0, failed=-1)",
+					defaultRollupJobLabels, constLabels,
+				),
+				Value: func(rollupJob RollupJobResponse) float64 {
+					return rollupJobStates[rollupJob.Status.JobState]
+				},
+				Labels: defaultRollupJobLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "rollup_job", "pages_processed_total"),
+					"Number of search pages processed by the rollup job",
+					defaultRollupJobLabels, constLabels,
+				),
+				Value: func(rollupJob RollupJobResponse) float64 {
+					return float64(rollupJob.Stats.PagesProcessed)
+				},
+				Labels: defaultRollupJobLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "rollup_job", "documents_processed_total"),
+					"Number of documents processed by the rollup job",
+					defaultRollupJobLabels, constLabels,
+				),
+				Value: func(rollupJob RollupJobResponse) float64 {
+					return float64(rollupJob.Stats.DocumentsProcessed)
+				},
+				Labels: defaultRollupJobLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "rollup_job", "rollups_indexed_total"),
+					"Number of rollup documents indexed by the rollup job",
+					defaultRollupJobLabels, constLabels,
+				),
+				Value: func(rollupJob RollupJobResponse) float64 {
+					return float64(rollupJob.Stats.RollupsIndexed)
+				},
+				Labels: defaultRollupJobLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "rollup_job", "trigger_count_total"),
+					"Number of times the rollup job has been triggered",
+					defaultRollupJobLabels, constLabels,
+				),
+				Value: func(rollupJob RollupJobResponse) float64 {
+					return float64(rollupJob.Stats.TriggerCount)
+				},
+				Labels: defaultRollupJobLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "rollup_job", "search_time_seconds_total"),
+					"Total time spent searching by the rollup job in seconds",
+					defaultRollupJobLabels, constLabels,
+				),
+				Value: func(rollupJob RollupJobResponse) float64 {
+					return float64(rollupJob.Stats.SearchTimeInMs) / 1000
+				},
+				Labels: defaultRollupJobLabelValues,
+			},
+			{
+				Type: prometheus.CounterValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "rollup_job", "index_time_seconds_total"),
+					"Total time spent indexing by the rollup job in seconds",
+					defaultRollupJobLabels, constLabels,
+				),
+				Value: func(rollupJob RollupJobResponse) float64 {
+					return float64(rollupJob.Stats.IndexTimeInMs) / 1000
+				},
+				Labels: defaultRollupJobLabelValues,
+			},
+		},
+	}
+}
+
+// Describe add Rollup Jobs metrics descriptions
+func (r *RollupJobs) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range r.rollupJobMetrics {
+		ch <- metric.Desc
+	}
+	ch <- r.up.Desc()
+	ch <- r.totalScrapes.Desc()
+	ch <- r.jsonParseFailures.Desc()
+}
+
+func (r *RollupJobs) fetchAndDecodeRollupJobs() (RollupJobsResponse, error) {
+	var rjr RollupJobsResponse
+
+	u := *r.url
+	// the get jobs API returns the config, status and stats of every job
+	u.Path = path.Join(u.Path, "/_rollup/job/_all")
+	err := getAndDecodeURL(r.logger, r.client, &u, &rjr, r.jsonParseFailures)
+	return rjr, err
+}
+
+// Collect gets Rollup Jobs metric values
+func (r *RollupJobs) Collect(ch chan<- prometheus.Metric) {
+	r.totalScrapes.Inc()
+	defer func() {
+		ch <- r.up
+		ch <- r.totalScrapes
+		ch <- r.jsonParseFailures
+	}()
+
+	rollupJobsResp, err := r.fetchAndDecodeRollupJobs()
+	if err != nil {
+		r.up.Set(0)
+		_ = level.Warn(r.logger).Log(
+			"msg", "failed to fetch and decode rollup jobs",
+			"err", err,
+		)
+		return
+	}
+	r.up.Set(1)
+
+	for _, rollupJob := range rollupJobsResp.Jobs {
+		for _, metric := range r.rollupJobMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(rollupJob),
+				metric.Labels(rollupJob)...,
+			)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package collector
+
+// RollupJobsResponse is a representation of the rollup jobs
+type RollupJobsResponse struct {
+	Jobs []RollupJobResponse `json:"jobs"`
+}
+
+// RollupJobResponse is a representation of a single rollup job with its config, status and stats
+type RollupJobResponse struct {
+	Config RollupJobConfigResponse `json:"config"`
+	Status RollupJobStatusResponse `json:"status"`
+	Stats  RollupJobStatsResponse  `json:"stats"`
+}
+
+// RollupJobConfigResponse is a representation of the rollup job configuration
+type RollupJobConfigResponse struct {
+	ID           string `json:"id"`
+	IndexPattern string `json:"index_pattern"`
+	RollupIndex  string `json:"rollup_index"`
+}
+
+// RollupJobStatusResponse is a representation of the rollup job status
+type RollupJobStatusResponse struct {
+	JobState string `json:"job_state"`
+}
+
+// RollupJobStatsResponse is a representation of the rollup job stats
+type RollupJobStatsResponse struct {
+	PagesProcessed     int64 `json:"pages_processed"`
+	DocumentsProcessed int64 `json:"documents_processed"`
+	RollupsIndexed     int64 `json:"rollups_indexed"`
+	TriggerCount       int64 `json:"trigger_count"`
+	IndexFailures      int64 `json:"index_failures"`
+	IndexTimeInMs      int64 `json:"index_time_in_ms"`
+	IndexTotal         int64 `json:"index_total"`
+	SearchFailures     int64 `json:"search_failures"`
+	SearchTimeInMs     int64 `json:"search_time_in_ms"`
+	SearchTotal        int64 `json:"search_total"`
+}
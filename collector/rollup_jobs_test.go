@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestRollupJobs(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_rollup/job/sensor -H 'Content-Type: application/json' -d '{"index_pattern":"sensor-*","rollup_index":"sensor_rollup","cron":"*/30 * * * * ?","page_size":1000,"groups":{"date_histogram":{"field":"timestamp","fixed_interval":"1h"}},"metrics":[{"field":"temperature","metrics":["min","max","sum"]}]}'
+	//  curl -XPOST http://localhost:9200/_rollup/job/sensor/_start
+	//  curl http://localhost:9200/_rollup/job/_all
+	tcs := map[string]string{
+		"7.6.2": `{"jobs":[{"config":{"id":"sensor","index_pattern":"sensor-*","rollup_index":"sensor_rollup","cron":"*/30 * * * * ?","groups":{"date_histogram":{"fixed_interval":"1h","field":"timestamp","time_zone":"UTC"}},"metrics":[{"field":"temperature","metrics":["min","max","sum"]}],"timeout":"20s","page_size":1000},"status":{"job_state":"started","upgraded_doc_id":true},"stats":{"pages_processed":12,"documents_processed":4200,"rollups_indexed":48,"trigger_count":7,"index_time_in_ms":350,"index_total":12,"index_failures":0,"search_time_in_ms":1250,"search_total":12,"search_failures":0,"processing_time_in_ms":15,"processing_total":12}}]}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		r := NewRollupJobs(log.NewNopLogger(), http.DefaultClient, u)
+		rjr, err := r.fetchAndDecodeRollupJobs()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode rollup jobs: %s", err)
+		}
+		t.Logf("[%s] Rollup Jobs Response: %+v", ver, rjr)
+		if len(rjr.Jobs) != 1 {
+			t.Fatalf("Wrong number of rollup jobs")
+		}
+		job := rjr.Jobs[0]
+		if job.Config.ID != "sensor" || job.Config.IndexPattern != "sensor-*" || job.Config.RollupIndex != "sensor_rollup" {
+			t.Errorf("Wrong rollup job config")
+		}
+		if rollupJobStates[job.Status.JobState] != 1 {
+			t.Errorf("Wrong rollup job state")
+		}
+		if job.Stats.PagesProcessed != 12 {
+			t.Errorf("Wrong number of pages processed")
+		}
+		if job.Stats.DocumentsProcessed != 4200 {
+			t.Errorf("Wrong number of documents processed")
+		}
+		if job.Stats.SearchTimeInMs != 1250 {
+			t.Errorf("Wrong search time")
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/blang/semver"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/gojuno/elasticsearch_exporter/pkg/clusterinfo"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// searchableSnapshotsMinVersion is the first version with searchable snapshots
+	searchableSnapshotsMinVersion = semver.MustParse("7.10.0")
+	// searchableSnapshotsSharedCacheMinVersion is the first version with the shared cache stats of partially mounted indices
+	searchableSnapshotsSharedCacheMinVersion = semver.MustParse("7.13.0")
+
+	defaultSearchableSnapshotShardLabels = []string{"node_id", "index", "shard"}
+	defaultSearchableSnapshotNodeLabels  = []string{"node_id"}
+)
+
+// SearchableSnapshots information struct
+type SearchableSnapshots struct {
+	logger        log.Logger
+	client        *http.Client
+	url           *url.URL
+	clusterInfoCh chan *clusterinfo.Response
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	cacheHitBytes  *prometheus.Desc
+	cacheMissBytes *prometheus.Desc
+	cacheEvictions *prometheus.Desc
+	cacheSize      *prometheus.Desc
+
+	mu sync.Mutex
+	// version is the last version received from the cluster info, nil until the first update
+	version *semver.Version
+}
+
+// NewSearchableSnapshots defines searchable snapshots Prometheus metrics. It receives the version of
+// the cluster from the cluster info, the stats are not fetched from versions without searchable snapshots.
+func NewSearchableSnapshots(logger log.Logger, client *http.Client, url *url.URL) *SearchableSnapshots {
+	constLabels := constLabelsFromURL(url)
+	searchableSnapshots := &SearchableSnapshots{
+		logger:        logger,
+		client:        client,
+		url:           url,
+		clusterInfoCh: make(chan *clusterinfo.Response),
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "searchable_snapshot", "up"),
+			Help:        "Was the last scrape of the ElasticSearch searchable snapshots endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "searchable_snapshot", "total_scrapes"),
+			Help:        "Current total ElasticSearch searchable snapshots scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "searchable_snapshot", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		cacheHitBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "searchable_snapshot", "cache_hit_bytes_total"),
+			"Total bytes of the mounted shard served from the cache",
+			defaultSearchableSnapshotShardLabels, constLabels,
+		),
+		cacheMissBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "searchable_snapshot", "cache_miss_bytes_total"),
+			"Total bytes of the mounted shard read from the snapshot repository, into the cache or directly",
+			defaultSearchableSnapshotShardLabels, constLabels,
+		),
+		cacheEvictions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "searchable_snapshot", "cache_evictions_total"),
+			"Total number of regions evicted from the shared cache of the node",
+			defaultSearchableSnapshotNodeLabels, constLabels,
+		),
+		cacheSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "searchable_snapshot", "cache_size_bytes"),
+			"Size of the shared cache of the node in bytes",
+			defaultSearchableSnapshotNodeLabels, constLabels,
+		),
+	}
+
+	// start go routine to fetch clusterinfo updates and save the version
+	go func() {
+		for ci := range searchableSnapshots.clusterInfoCh {
+			if ci != nil {
+				searchableSnapshots.setVersion(ci.Version.Number)
+			}
+		}
+	}()
+	return searchableSnapshots
+}
+
+// ClusterLabelUpdates returns a pointer to a channel to receive cluster info updates. It implements the
+// (not exported) clusterinfo.consumer interface
+func (s *SearchableSnapshots) ClusterLabelUpdates() *chan *clusterinfo.Response {
+	return &s.clusterInfoCh
+}
+
+// String implements the stringer interface. It is part of the clusterinfo.consumer interface
+func (s *SearchableSnapshots) String() string {
+	return namespace + "searchable_snapshots"
+}
+
+func (s *SearchableSnapshots) setVersion(version semver.Version) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version = &version
+}
+
+// supports returns whether the cluster is at least minVersion, assuming it is while the version is unknown
+func (s *SearchableSnapshots) supports(minVersion semver.Version) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.version == nil || s.version.GTE(minVersion)
+}
+
+// Describe add searchable snapshots metrics descriptions
+func (s *SearchableSnapshots) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.cacheHitBytes
+	ch <- s.cacheMissBytes
+	ch <- s.cacheEvictions
+	ch <- s.cacheSize
+	ch <- s.up.Desc()
+	ch <- s.totalScrapes.Desc()
+	ch <- s.jsonParseFailures.Desc()
+}
+
+func (s *SearchableSnapshots) fetchAndDecodeSearchableSnapshotsStats() (SearchableSnapshotsStatsResponse, error) {
+	var sssr SearchableSnapshotsStatsResponse
+
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_searchable_snapshots/stats")
+	u.RawQuery = "level=shards"
+	err := getAndDecodeURL(s.logger, s.client, &u, &sssr, s.jsonParseFailures)
+	return sssr, err
+}
+
+func (s *SearchableSnapshots) fetchAndDecodeCacheStats() (SearchableSnapshotsCacheStatsResponse, error) {
+	var scsr SearchableSnapshotsCacheStatsResponse
+
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_searchable_snapshots/cache/stats")
+	err := getAndDecodeURL(s.logger, s.client, &u, &scsr, s.jsonParseFailures)
+	return scsr, err
+}
+
+// Collect gets searchable snapshots metric values
+func (s *SearchableSnapshots) Collect(ch chan<- prometheus.Metric) {
+	s.totalScrapes.Inc()
+	defer func() {
+		ch <- s.up
+		ch <- s.totalScrapes
+		ch <- s.jsonParseFailures
+	}()
+
+	if !s.supports(searchableSnapshotsMinVersion) {
+		s.up.Set(1)
+		_ = level.Debug(s.logger).Log(
+			"msg", "searchable snapshots are only available starting with 7.10, skipping",
+		)
+		return
+	}
+
+	statsResp, err := s.fetchAndDecodeSearchableSnapshotsStats()
+	if err != nil {
+		s.up.Set(0)
+		_ = level.Warn(s.logger).Log(
+			"msg", "failed to fetch and decode searchable snapshots stats",
+			"err", err,
+		)
+		return
+	}
+	s.up.Set(1)
+
+	for key, bytes := range searchableSnapshotCacheBytesByShard(statsResp) {
+		ch <- prometheus.MustNewConstMetric(
+			s.cacheHitBytes,
+			prometheus.CounterValue,
+			float64(bytes.hit),
+			key.nodeID, key.index, key.shard,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			s.cacheMissBytes,
+			prometheus.CounterValue,
+			float64(bytes.miss),
+			key.nodeID, key.index, key.shard,
+		)
+	}
+
+	if !s.supports(searchableSnapshotsSharedCacheMinVersion) {
+		return
+	}
+	cacheStatsResp, err := s.fetchAndDecodeCacheStats()
+	if err != nil {
+		_ = level.Warn(s.logger).Log(
+			"msg", "failed to fetch and decode searchable snapshots cache stats",
+			"err", err,
+		)
+		return
+	}
+	for nodeID, node := range cacheStatsResp.Nodes {
+		ch <- prometheus.MustNewConstMetric(
+			s.cacheEvictions,
+			prometheus.CounterValue,
+			float64(node.SharedCache.Evictions),
+			nodeID,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			s.cacheSize,
+			prometheus.GaugeValue,
+			float64(node.SharedCache.SizeInBytes),
+			nodeID,
+		)
+	}
+}
@@ -0,0 +1,90 @@
+package collector
+
+// SearchableSnapshotsStatsResponse is a representation of the searchable snapshots stats API at shard level
+type SearchableSnapshotsStatsResponse struct {
+	Indices map[string]SearchableSnapshotsIndexStatsResponse `json:"indices"`
+}
+
+// SearchableSnapshotsIndexStatsResponse holds the stats of the copies of every shard of a mounted index, keyed by shard number
+type SearchableSnapshotsIndexStatsResponse struct {
+	Shards map[string][]SearchableSnapshotsShardStatsResponse `json:"shards"`
+}
+
+// SearchableSnapshotsShardStatsResponse defines the stats of a single shard copy, broken down by file
+type SearchableSnapshotsShardStatsResponse struct {
+	Shard struct {
+		State   string `json:"state"`
+		Primary bool   `json:"primary"`
+		Node    string `json:"node"`
+	} `json:"shard"`
+	Files []SearchableSnapshotsFileStatsResponse `json:"files"`
+}
+
+// SearchableSnapshotsFileStatsResponse defines the reads of the files of a shard copy
+type SearchableSnapshotsFileStatsResponse struct {
+	// CachedBytesRead are the bytes served from the cache
+	CachedBytesRead SearchableSnapshotsBytesResponse `json:"cached_bytes_read"`
+	// CachedBytesWritten are the bytes fetched from the repository into the cache after a miss
+	CachedBytesWritten SearchableSnapshotsBytesResponse `json:"cached_bytes_written"`
+	// DirectBytesRead are the bytes read from the repository without going through the cache
+	DirectBytesRead SearchableSnapshotsBytesResponse `json:"direct_bytes_read"`
+}
+
+// SearchableSnapshotsBytesResponse defines the number of reads or writes and their total size in bytes
+type SearchableSnapshotsBytesResponse struct {
+	Count int64 `json:"count"`
+	Sum   int64 `json:"sum"`
+}
+
+// SearchableSnapshotsCacheStatsResponse is a representation of the searchable snapshots cache stats API, keyed by node ID
+type SearchableSnapshotsCacheStatsResponse struct {
+	Nodes map[string]struct {
+		SharedCache SearchableSnapshotsSharedCacheResponse `json:"shared_cache"`
+	} `json:"nodes"`
+}
+
+// SearchableSnapshotsSharedCacheResponse defines the shared cache of a node used by partially mounted indices
+type SearchableSnapshotsSharedCacheResponse struct {
+	Reads             int64 `json:"reads"`
+	BytesReadInBytes  int64 `json:"bytes_read_in_bytes"`
+	Writes            int64 `json:"writes"`
+	BytesWritten      int64 `json:"bytes_written_in_bytes"`
+	Evictions         int64 `json:"evictions"`
+	NumRegions        int64 `json:"num_regions"`
+	SizeInBytes       int64 `json:"size_in_bytes"`
+	RegionSizeInBytes int64 `json:"region_size_in_bytes"`
+}
+
+// searchableSnapshotShardKey identifies a shard copy of a mounted index on a node
+type searchableSnapshotShardKey struct {
+	nodeID, index, shard string
+}
+
+// searchableSnapshotCacheBytes holds the bytes served from the cache and the bytes read from the repository
+type searchableSnapshotCacheBytes struct {
+	hit, miss int64
+}
+
+// searchableSnapshotCacheBytesByShard sums the cache hits and misses of the files of every shard copy.
+// Misses are the bytes fetched into the cache and the bytes read directly from the repository.
+// Unassigned shard copies are skipped.
+func searchableSnapshotCacheBytesByShard(stats SearchableSnapshotsStatsResponse) map[searchableSnapshotShardKey]searchableSnapshotCacheBytes {
+	result := make(map[searchableSnapshotShardKey]searchableSnapshotCacheBytes)
+	for index, indexStats := range stats.Indices {
+		for shard, copies := range indexStats.Shards {
+			for _, shardCopy := range copies {
+				if shardCopy.Shard.Node == "" {
+					continue
+				}
+				key := searchableSnapshotShardKey{shardCopy.Shard.Node, index, shard}
+				bytes := result[key]
+				for _, file := range shardCopy.Files {
+					bytes.hit += file.CachedBytesRead.Sum
+					bytes.miss += file.CachedBytesWritten.Sum + file.DirectBytesRead.Sum
+				}
+				result[key] = bytes
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSearchableSnapshots(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "xpack.searchable.snapshot.shared_cache.size=1mb" elasticsearch:VERSION (and a second node)
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  curl -XPUT http://localhost:9200/_snapshot/backups -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/backups"}}'
+	//  curl -XPUT 'http://localhost:9200/_snapshot/backups/snapshot-1?wait_for_completion=true'
+	//  curl -XPOST 'http://localhost:9200/_snapshot/backups/snapshot-1/_mount?wait_for_completion=true' -H 'Content-Type: application/json' -d '{"index":"logs","renamed_index":"logs-mounted","index_settings":{"index.number_of_replicas":2}}'
+	//  curl 'http://localhost:9200/_searchable_snapshots/stats?level=shards'
+	//  curl http://localhost:9200/_searchable_snapshots/cache/stats
+	tcs := map[string]map[string]string{
+		"7.13.4": {
+			"/_searchable_snapshots/stats":       `{"_shards":{"total":4,"successful":3,"failed":0},"total":[],"indices":{"logs-mounted":{"total":[],"shards":{"0":[{"snapshot_uuid":"Zq8AD7xWRvGyL0ZaW7UiSw","index_uuid":"qFgp6tRnSWSo3vBnZK8R4w","shard":{"state":"STARTED","primary":true,"node":"9_P7yui4SQOkzGhTZCyjxQ"},"files":[{"file_ext":"cfs","num_files":1,"total_size":4096,"open_count":2,"close_count":2,"contiguous_bytes_read":{"count":1,"sum":100,"min":100,"max":100},"non_contiguous_bytes_read":{"count":0,"sum":0,"min":0,"max":0},"cached_bytes_read":{"count":4,"sum":3000,"min":500,"max":1000},"index_cache_bytes_read":{"count":0,"sum":0,"min":0,"max":0},"cached_bytes_written":{"count":1,"sum":1024,"min":1024,"max":1024,"time_in_nanos":52000},"direct_bytes_read":{"count":0,"sum":0,"min":0,"max":0,"time_in_nanos":0},"optimized_bytes_read":{"count":0,"sum":0,"min":0,"max":0,"time_in_nanos":0},"forward_seeks":{"small":{"count":0,"sum":0,"min":0,"max":0},"large":{"count":0,"sum":0,"min":0,"max":0}},"backward_seeks":{"small":{"count":0,"sum":0,"min":0,"max":0},"large":{"count":0,"sum":0,"min":0,"max":0}},"blob_store_bytes_requested":{"count":1,"sum":1024,"min":1024,"max":1024},"current_index_cache_fills":0},{"file_ext":"si","num_files":1,"total_size":350,"open_count":1,"close_count":1,"cached_bytes_read":{"count":1,"sum":350,"min":350,"max":350},"cached_bytes_written":{"count":0,"sum":0,"min":0,"max":0,"time_in_nanos":0},"direct_bytes_read":{"count":1,"sum":200,"min":200,"max":200,"time_in_nanos":10000}}]},{"snapshot_uuid":"Zq8AD7xWRvGyL0ZaW7UiSw","index_uuid":"qFgp6tRnSWSo3vBnZK8R4w","shard":{"state":"STARTED","primary":false,"node":"Jx0Vt0hTR0iVbVGRx1oBCA"},"files":[{"file_ext":"cfs","num_files":1,"total_size":4096,"cached_bytes_read":{"count":0,"sum":0,"min":0,"max":0},"cached_bytes_written":{"count":1,"sum":4096,"min":4096,"max":4096,"time_in_nanos":80000},"direct_bytes_read":{"count":0,"sum":0,"min":0,"max":0,"time_in_nanos":0}}]},{"snapshot_uuid":"Zq8AD7xWRvGyL0ZaW7UiSw","index_uuid":"qFgp6tRnSWSo3vBnZK8R4w","shard":{"state":"UNASSIGNED","primary":false,"node":null},"files":[]}],"1":[{"snapshot_uuid":"Zq8AD7xWRvGyL0ZaW7UiSw","index_uuid":"qFgp6tRnSWSo3vBnZK8R4w","shard":{"state":"STARTED","primary":true,"node":"Jx0Vt0hTR0iVbVGRx1oBCA"},"files":[{"file_ext":"cfs","num_files":1,"total_size":2048,"cached_bytes_read":{"count":2,"sum":2048,"min":1024,"max":1024},"cached_bytes_written":{"count":0,"sum":0,"min":0,"max":0,"time_in_nanos":0},"direct_bytes_read":{"count":0,"sum":0,"min":0,"max":0,"time_in_nanos":0}}]}]}}}}`,
+			"/_searchable_snapshots/cache/stats": `{"nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"shared_cache":{"reads":5,"bytes_read_in_bytes":3350,"writes":1,"bytes_written_in_bytes":1024,"evictions":3,"num_regions":64,"size_in_bytes":1048576,"region_size_in_bytes":16384}},"Jx0Vt0hTR0iVbVGRx1oBCA":{"shared_cache":{"reads":2,"bytes_read_in_bytes":2048,"writes":1,"bytes_written_in_bytes":4096,"evictions":0,"num_regions":64,"size_in_bytes":1048576,"region_size_in_bytes":16384}}}}`,
+		},
+	}
+	for ver, out := range tcs {
+		var requests int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprintln(w, out[r.URL.Path])
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		s := NewSearchableSnapshots(log.NewNopLogger(), http.DefaultClient, u)
+		sssr, err := s.fetchAndDecodeSearchableSnapshotsStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode searchable snapshots stats: %s", err)
+		}
+		t.Logf("[%s] Searchable Snapshots Stats Response: %+v", ver, sssr)
+
+		bytes := searchableSnapshotCacheBytesByShard(sssr)
+		expected := map[searchableSnapshotShardKey]searchableSnapshotCacheBytes{
+			{"9_P7yui4SQOkzGhTZCyjxQ", "logs-mounted", "0"}: {hit: 3350, miss: 1224},
+			{"Jx0Vt0hTR0iVbVGRx1oBCA", "logs-mounted", "0"}: {hit: 0, miss: 4096},
+			{"Jx0Vt0hTR0iVbVGRx1oBCA", "logs-mounted", "1"}: {hit: 2048, miss: 0},
+		}
+		if len(bytes) != len(expected) {
+			t.Fatalf("[%s] Wrong number of shard copies: %v", ver, bytes)
+		}
+		for key, want := range expected {
+			if got := bytes[key]; got != want {
+				t.Errorf("[%s] Wrong cache bytes of %v: got %+v, expected %+v", ver, key, got, want)
+			}
+		}
+
+		scsr, err := s.fetchAndDecodeCacheStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode searchable snapshots cache stats: %s", err)
+		}
+		node := scsr.Nodes["9_P7yui4SQOkzGhTZCyjxQ"].SharedCache
+		if node.Evictions != 3 || node.SizeInBytes != 1048576 {
+			t.Errorf("[%s] Wrong shared cache stats: %+v", ver, node)
+		}
+
+		// up, total_scrapes, json_parse_failures, 2 metrics per shard copy and 2 per node
+		versions := []struct {
+			version           string
+			requests, metrics int
+		}{
+			// the cluster info was not received yet
+			{"", 2, 3 + 6 + 4},
+			{"7.9.3", 0, 3},
+			{"7.12.1", 1, 3 + 6},
+			{"7.13.4", 2, 3 + 6 + 4},
+		}
+		for _, tc := range versions {
+			s := NewSearchableSnapshots(log.NewNopLogger(), http.DefaultClient, u)
+			if tc.version != "" {
+				s.setVersion(semver.MustParse(tc.version))
+			}
+			requests = 0
+			ch := make(chan prometheus.Metric, 32)
+			s.Collect(ch)
+			close(ch)
+			if requests != tc.requests {
+				t.Errorf("[%s] Wrong number of requests for version %q: got %d, expected %d", ver, tc.version, requests, tc.requests)
+			}
+			if len(ch) != tc.metrics {
+				t.Errorf("[%s] Wrong number of metrics for version %q: got %d, expected %d", ver, tc.version, len(ch), tc.metrics)
+			}
+		}
+	}
+}
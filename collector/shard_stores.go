@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	shardStoresFetchBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+)
+
+// ShardStores information struct
+type ShardStores struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	fetchDuration   prometheus.Histogram
+	storeExceptions prometheus.Gauge
+}
+
+// NewShardStores defines ShardStores Prometheus metrics
+func NewShardStores(logger log.Logger, client *http.Client, url *url.URL) *ShardStores {
+	constLabels := constLabelsFromURL(url)
+	return &ShardStores{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "shard_stores", "up"),
+			Help:        "Was the last scrape of the ElasticSearch shard stores endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "shard_stores", "total_scrapes"),
+			Help:        "Current total ElasticSearch shard stores scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "shard_stores", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		fetchDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        prometheus.BuildFQName(namespace, "shard_stores", "fetch_duration_seconds"),
+			Help:        "Time to fetch the store copies of the shards of red indices, high values indicate a master node under pressure",
+			Buckets:     shardStoresFetchBuckets,
+			ConstLabels: constLabels,
+		}),
+		storeExceptions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "shard_stores", "exception_count"),
+			Help:        "Number of shard store copies of red indices which failed to open",
+			ConstLabels: constLabels,
+		}),
+	}
+}
+
+// Describe add ShardStores metrics descriptions
+func (s *ShardStores) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.fetchDuration.Desc()
+	ch <- s.storeExceptions.Desc()
+	ch <- s.up.Desc()
+	ch <- s.totalScrapes.Desc()
+	ch <- s.jsonParseFailures.Desc()
+}
+
+func (s *ShardStores) fetchAndDecodeShardStores() (ShardStoresResponse, error) {
+	var ssr ShardStoresResponse
+
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_shard_stores")
+	u.RawQuery = "status=red"
+	err := getAndDecodeURL(s.logger, s.client, &u, &ssr, s.jsonParseFailures)
+	return ssr, err
+}
+
+// Collect gets ShardStores metric values
+func (s *ShardStores) Collect(ch chan<- prometheus.Metric) {
+	s.totalScrapes.Inc()
+	defer func() {
+		ch <- s.up
+		ch <- s.totalScrapes
+		ch <- s.jsonParseFailures
+		ch <- s.fetchDuration
+	}()
+
+	// failed fetches are observed as well, timeouts are the most telling sign of a busy master
+	start := time.Now()
+	shardStoresResp, err := s.fetchAndDecodeShardStores()
+	s.fetchDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.up.Set(0)
+		_ = level.Warn(s.logger).Log(
+			"msg", "failed to fetch and decode shard stores",
+			"err", err,
+		)
+		return
+	}
+	s.up.Set(1)
+
+	s.storeExceptions.Set(float64(shardStoresResp.storeExceptions()))
+	ch <- s.storeExceptions
+}
@@ -0,0 +1,45 @@
+package collector
+
+// ShardStoresResponse is a representation of the index shard stores API
+type ShardStoresResponse struct {
+	Indices map[string]ShardStoresIndexResponse `json:"indices"`
+}
+
+// ShardStoresIndexResponse is a representation of the shard stores of a single index
+type ShardStoresIndexResponse struct {
+	Shards map[string]ShardStoresShardResponse `json:"shards"`
+}
+
+// ShardStoresShardResponse is a representation of the store copies of a single shard
+type ShardStoresShardResponse struct {
+	Stores []ShardStoreResponse `json:"stores"`
+}
+
+// ShardStoreResponse is a representation of a single store copy of a shard, the node holding
+// the copy is reported under its node id as key and ignored
+type ShardStoreResponse struct {
+	AllocationID   string                       `json:"allocation_id"`
+	Allocation     string                       `json:"allocation"`
+	StoreException *ShardStoreExceptionResponse `json:"store_exception"`
+}
+
+// ShardStoreExceptionResponse is a representation of the error opening a shard store copy
+type ShardStoreExceptionResponse struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// storeExceptions returns the number of store copies which failed to open
+func (s ShardStoresResponse) storeExceptions() int {
+	var count int
+	for _, index := range s.Indices {
+		for _, shard := range index.Shards {
+			for _, store := range shard.Stores {
+				if store.StoreException != nil {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestShardStores(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter -H 'Content-Type: application/json' -d '{"settings":{"number_of_shards":2,"number_of_replicas":0}}'
+	//  docker stop; corrupt a segment file of shard 0; docker start
+	//  curl 'http://localhost:9200/_shard_stores?status=red'
+	tcs := map[string]string{
+		"6.8.8":  `{"indices":{"twitter":{"shards":{"0":{"stores":[{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"9_P7yui","ephemeral_id":"sW0e4xMVQbmXqs5oL4zD4Q","transport_address":"172.17.0.2:9300","attributes":{"ml.machine_memory":"2083807232","xpack.installed":"true","ml.max_open_jobs":"20","ml.enabled":"true"}},"allocation_id":"2iNySv_OQVePRX-yaRH_lQ","allocation":"primary","store_exception":{"type":"corrupt_index_exception","reason":"failed engine (reason: [corrupt file (source: [start])]) (resource=preexisting_corruption)"}}]}}}}}`,
+		"7.10.2": `{"indices":{"twitter":{"shards":{"0":{"stores":[{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0","ephemeral_id":"sW0e4xMVQbmXqs5oL4zD4Q","transport_address":"172.17.0.2:9300","attributes":{"xpack.installed":"true","transform.node":"true"}},"allocation_id":"2iNySv_OQVePRX-yaRH_lQ","allocation":"primary","store_exception":{"type":"corrupt_index_exception","reason":"failed engine (reason: [corrupt file (source: [start])]) (resource=preexisting_corruption)"}}]},"1":{"stores":[]}}}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("status") != "red" {
+				t.Errorf("Shard stores of all indices requested")
+			}
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		s := NewShardStores(log.NewNopLogger(), http.DefaultClient, u)
+		ssr, err := s.fetchAndDecodeShardStores()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode shard stores: %s", err)
+		}
+		t.Logf("[%s] Shard Stores Response: %+v", ver, ssr)
+		store := ssr.Indices["twitter"].Shards["0"].Stores[0]
+		if store.Allocation != "primary" || store.StoreException == nil || store.StoreException.Type != "corrupt_index_exception" {
+			t.Errorf("Wrong store copy of shard 0: %+v", store)
+		}
+		if n := ssr.storeExceptions(); n != 1 {
+			t.Errorf("Wrong number of store exceptions: %d", n)
+		}
+
+		ch := make(chan prometheus.Metric, 10)
+		s.Collect(ch)
+		close(ch)
+		var m dto.Metric
+		if err := s.fetchDuration.Write(&m); err != nil {
+			t.Fatalf("Failed to read fetch duration: %s", err)
+		}
+		if m.GetHistogram().GetSampleCount() != 1 {
+			t.Errorf("Wrong number of observed fetches: %d", m.GetHistogram().GetSampleCount())
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// esTimeUnits maps the Elasticsearch time units to seconds, longest suffixes first
+	esTimeUnits = []struct {
+		suffix  string
+		seconds float64
+	}{
+		{"micros", 1e-6},
+		{"nanos", 1e-9},
+		{"ms", 1e-3},
+		{"d", 86400},
+		{"h", 3600},
+		{"m", 60},
+		{"s", 1},
+	}
+
+	defaultSLMPolicyLabels = []string{"policy"}
+)
+
+// parseESDuration parses an Elasticsearch time value like "30d" or "500ms" into seconds
+func parseESDuration(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	for _, unit := range esTimeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(value, unit.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid time value %q: %s", value, err)
+			}
+			return v * unit.seconds, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time value %q: missing unit", value)
+}
+
+// SLM information struct
+type SLM struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	policyInfo             *prometheus.Desc
+	nextExecutionTimestamp *prometheus.Desc
+	lastSuccessTimestamp   *prometheus.Desc
+	lastFailureTimestamp   *prometheus.Desc
+	lastExecutionFailed    *prometheus.Desc
+	retentionMinCount      *prometheus.Desc
+	retentionMaxCount      *prometheus.Desc
+	retentionMaxAgeSeconds *prometheus.Desc
+}
+
+// NewSLM defines SLM Prometheus metrics
+func NewSLM(logger log.Logger, client *http.Client, url *url.URL) *SLM {
+	constLabels := constLabelsFromURL(url)
+	return &SLM{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "slm", "up"),
+			Help:        "Was the last scrape of the ElasticSearch SLM endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "slm", "total_scrapes"),
+			Help:        "Current total ElasticSearch SLM scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "slm", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		policyInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_info"),
+			"Constant metric with the schedule and repository of the SLM policy as labels",
+			append(defaultSLMPolicyLabels, "schedule", "repository"), constLabels,
+		),
+		nextExecutionTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_next_execution_timestamp"),
+			"Unix timestamp of the next scheduled execution of the SLM policy",
+			defaultSLMPolicyLabels, constLabels,
+		),
+		lastSuccessTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_last_success_timestamp"),
+			"Unix timestamp of the last successful execution of the SLM policy",
+			defaultSLMPolicyLabels, constLabels,
+		),
+		lastFailureTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_last_failure_timestamp"),
+			"Unix timestamp of the last failed execution of the SLM policy",
+			defaultSLMPolicyLabels, constLabels,
+		),
+		lastExecutionFailed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_last_execution_failed"),
+			"Whether the most recent execution of the SLM policy failed",
+			defaultSLMPolicyLabels, constLabels,
+		),
+		retentionMinCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_retention_min_count"),
+			"Minimum number of snapshots kept by the SLM policy retention",
+			defaultSLMPolicyLabels, constLabels,
+		),
+		retentionMaxCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_retention_max_count"),
+			"Maximum number of snapshots kept by the SLM policy retention",
+			defaultSLMPolicyLabels, constLabels,
+		),
+		retentionMaxAgeSeconds: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "slm", "policy_retention_max_age_seconds"),
+			"Age in seconds after which snapshots are deleted by the SLM policy retention",
+			defaultSLMPolicyLabels, constLabels,
+		),
+	}
+}
+
+// Describe add SLM metrics descriptions
+func (s *SLM) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.policyInfo
+	ch <- s.nextExecutionTimestamp
+	ch <- s.lastSuccessTimestamp
+	ch <- s.lastFailureTimestamp
+	ch <- s.lastExecutionFailed
+	ch <- s.retentionMinCount
+	ch <- s.retentionMaxCount
+	ch <- s.retentionMaxAgeSeconds
+	ch <- s.up.Desc()
+	ch <- s.totalScrapes.Desc()
+	ch <- s.jsonParseFailures.Desc()
+}
+
+func (s *SLM) fetchAndDecodeSLMPolicies() (SLMPoliciesResponse, error) {
+	var spr SLMPoliciesResponse
+
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_slm/policy")
+	err := getAndDecodeURL(s.logger, s.client, &u, &spr, s.jsonParseFailures)
+	return spr, err
+}
+
+// Collect gets SLM metric values
+func (s *SLM) Collect(ch chan<- prometheus.Metric) {
+	s.totalScrapes.Inc()
+	defer func() {
+		ch <- s.up
+		ch <- s.totalScrapes
+		ch <- s.jsonParseFailures
+	}()
+
+	policiesResp, err := s.fetchAndDecodeSLMPolicies()
+	if err != nil {
+		s.up.Set(0)
+		_ = level.Warn(s.logger).Log(
+			"msg", "failed to fetch and decode SLM policies",
+			"err", err,
+		)
+		return
+	}
+	s.up.Set(1)
+
+	for policyName, policy := range policiesResp {
+		ch <- prometheus.MustNewConstMetric(
+			s.policyInfo,
+			prometheus.GaugeValue,
+			1,
+			policyName, policy.Policy.Schedule, policy.Policy.Repository,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			s.nextExecutionTimestamp,
+			prometheus.GaugeValue,
+			float64(policy.NextExecutionMillis)/1000,
+			policyName,
+		)
+
+		if policy.LastSuccess != nil {
+			ch <- prometheus.MustNewConstMetric(
+				s.lastSuccessTimestamp,
+				prometheus.GaugeValue,
+				float64(policy.LastSuccess.Time)/1000,
+				policyName,
+			)
+		}
+		if policy.LastFailure != nil {
+			ch <- prometheus.MustNewConstMetric(
+				s.lastFailureTimestamp,
+				prometheus.GaugeValue,
+				float64(policy.LastFailure.Time)/1000,
+				policyName,
+			)
+		}
+		var lastExecutionFailed float64
+		if policy.LastExecutionFailed() {
+			lastExecutionFailed = 1
+			_ = level.Debug(s.logger).Log(
+				"msg", "last execution of SLM policy failed",
+				"policy", policyName,
+				"snapshot", policy.LastFailure.SnapshotName,
+				"details", policy.LastFailure.Details,
+			)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			s.lastExecutionFailed,
+			prometheus.GaugeValue,
+			lastExecutionFailed,
+			policyName,
+		)
+
+		retention := policy.Policy.Retention
+		if retention == nil {
+			continue
+		}
+		if retention.MinCount != nil {
+			ch <- prometheus.MustNewConstMetric(
+				s.retentionMinCount,
+				prometheus.GaugeValue,
+				float64(*retention.MinCount),
+				policyName,
+			)
+		}
+		if retention.MaxCount != nil {
+			ch <- prometheus.MustNewConstMetric(
+				s.retentionMaxCount,
+				prometheus.GaugeValue,
+				float64(*retention.MaxCount),
+				policyName,
+			)
+		}
+		if retention.ExpireAfter != nil {
+			maxAge, err := parseESDuration(*retention.ExpireAfter)
+			if err != nil {
+				_ = level.Warn(s.logger).Log(
+					"msg", "failed to parse SLM retention expire_after",
+					"policy", policyName,
+					"err", err,
+				)
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				s.retentionMaxAgeSeconds,
+				prometheus.GaugeValue,
+				maxAge,
+				policyName,
+			)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package collector
+
+// SLMPoliciesResponse is a representation of the snapshot lifecycle management policies
+type SLMPoliciesResponse map[string]SLMPolicyResponse
+
+// SLMPolicyResponse is a representation of a single snapshot lifecycle management policy
+type SLMPolicyResponse struct {
+	Version             int64                   `json:"version"`
+	ModifiedDateMillis  int64                   `json:"modified_date_millis"`
+	Policy              SLMPolicyDefinitionData `json:"policy"`
+	NextExecutionMillis int64                   `json:"next_execution_millis"`
+	// LastSuccess and LastFailure are only reported once the policy was executed
+	LastSuccess *SLMPolicyInvocationRecord `json:"last_success"`
+	LastFailure *SLMPolicyInvocationRecord `json:"last_failure"`
+}
+
+// SLMPolicyInvocationRecord is a representation of an execution of a snapshot lifecycle management policy
+type SLMPolicyInvocationRecord struct {
+	SnapshotName string `json:"snapshot_name"`
+	Time         int64  `json:"time"`
+	Details      string `json:"details"`
+}
+
+// LastExecutionFailed returns true if the most recent execution of the policy failed
+func (p SLMPolicyResponse) LastExecutionFailed() bool {
+	if p.LastFailure == nil {
+		return false
+	}
+	return p.LastSuccess == nil || p.LastFailure.Time > p.LastSuccess.Time
+}
+
+// SLMPolicyDefinitionData is a representation of the definition of a snapshot lifecycle management policy
+type SLMPolicyDefinitionData struct {
+	Name       string              `json:"name"`
+	Schedule   string              `json:"schedule"`
+	Repository string              `json:"repository"`
+	Retention  *SLMRetentionConfig `json:"retention"`
+}
+
+// SLMRetentionConfig is a representation of the retention rules of a snapshot lifecycle management policy.
+// Every rule is optional.
+type SLMRetentionConfig struct {
+	ExpireAfter *string `json:"expire_after"`
+	MinCount    *int64  `json:"min_count"`
+	MaxCount    *int64  `json:"max_count"`
+}
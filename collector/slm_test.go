@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestSLMPolicies(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/my_repository -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/snapshots"}}'
+	//  curl -XPUT http://localhost:9200/_slm/policy/daily-snapshots -H 'Content-Type: application/json' -d '{"schedule":"0 30 1 * * ?","name":"<daily-snap-{now/d}>","repository":"my_repository","retention":{"expire_after":"30d","min_count":5,"max_count":50}}'
+	//  curl -XPUT http://localhost:9200/_slm/policy/hourly-snapshots -H 'Content-Type: application/json' -d '{"schedule":"0 0 * * * ?","name":"<hourly-snap-{now/h}>","repository":"my_repository"}'
+	//  curl http://localhost:9200/_slm/policy
+	tcs := map[string]string{
+		"7.6.2": `{"daily-snapshots":{"version":1,"modified_date_millis":1585735200000,"policy":{"name":"<daily-snap-{now/d}>","schedule":"0 30 1 * * ?","repository":"my_repository","retention":{"expire_after":"30d","min_count":5,"max_count":50}},"next_execution_millis":1585791000000,"stats":{"policy":"daily-snapshots","snapshots_taken":0,"snapshots_failed":0,"snapshots_deleted":0,"snapshot_deletion_failures":0}},"hourly-snapshots":{"version":1,"modified_date_millis":1585735200000,"policy":{"name":"<hourly-snap-{now/h}>","schedule":"0 0 * * * ?","repository":"my_repository","retention":{}},"next_execution_millis":1585738800000,"stats":{"policy":"hourly-snapshots","snapshots_taken":0,"snapshots_failed":0,"snapshots_deleted":0,"snapshot_deletion_failures":0}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		s := NewSLM(log.NewNopLogger(), http.DefaultClient, u)
+		spr, err := s.fetchAndDecodeSLMPolicies()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode SLM policies: %s", err)
+		}
+		t.Logf("[%s] SLM Policies Response: %+v", ver, spr)
+		if len(spr) != 2 {
+			t.Fatalf("Wrong number of policies")
+		}
+		daily := spr["daily-snapshots"]
+		if daily.Policy.Schedule != "0 30 1 * * ?" {
+			t.Errorf("Wrong schedule")
+		}
+		if daily.NextExecutionMillis != 1585791000000 {
+			t.Errorf("Wrong next execution")
+		}
+		retention := daily.Policy.Retention
+		if retention == nil || retention.MinCount == nil || *retention.MinCount != 5 {
+			t.Fatalf("Wrong retention min_count")
+		}
+		if retention.MaxCount == nil || *retention.MaxCount != 50 {
+			t.Errorf("Wrong retention max_count")
+		}
+		if maxAge, err := parseESDuration(*retention.ExpireAfter); err != nil || maxAge != 2592000 {
+			t.Errorf("Wrong retention max age: %v, %v", maxAge, err)
+		}
+		hourly := spr["hourly-snapshots"].Policy.Retention
+		if hourly == nil || hourly.MinCount != nil || hourly.MaxCount != nil || hourly.ExpireAfter != nil {
+			t.Errorf("Hourly policy should have no retention rules")
+		}
+	}
+}
+
+func TestParseESDuration(t *testing.T) {
+	for value, expected := range map[string]float64{
+		"30d":   2592000,
+		"12h":   43200,
+		"15m":   900,
+		"90s":   90,
+		"500ms": 0.5,
+	} {
+		v, err := parseESDuration(value)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %s", value, err)
+		}
+		if v != expected {
+			t.Errorf("Wrong value for %q: got %v, expected %v", value, v, expected)
+		}
+	}
+	if _, err := parseESDuration("30"); err == nil {
+		t.Errorf("Expected error for time value without unit")
+	}
+}
+
+func TestSLMPolicyLastExecution(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/my_repository -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/snapshots"}}'
+	//  curl -XPUT http://localhost:9200/_slm/policy/daily-snapshots -H 'Content-Type: application/json' -d '{"schedule":"0 30 1 * * ?","name":"<daily-snap-{now/d}>","repository":"my_repository"}'
+	//  curl -XPOST http://localhost:9200/_slm/policy/daily-snapshots/_execute (before and after chmod 000 /tmp/snapshots)
+	//  curl http://localhost:9200/_slm/policy
+	tcs := map[string]string{
+		"7.6.2": `{"daily-snapshots":{"version":1,"modified_date_millis":1585735200000,"policy":{"name":"<daily-snap-{now/d}>","schedule":"0 30 1 * * ?","repository":"my_repository"},"last_success":{"snapshot_name":"daily-snap-2020.04.01-vxq2dw5nqxubqkiej-k8ww","time_string":"2020-04-01T10:05:00.000Z","time":1585735500000},"last_failure":{"snapshot_name":"daily-snap-2020.04.02-lzx9vjytqwcxrhuoiw5uxa","time_string":"2020-04-02T01:30:00.000Z","time":1585791000000,"details":"{\"type\":\"repository_exception\",\"reason\":\"[my_repository] cannot create blob store\"}"},"next_execution_millis":1585877400000,"stats":{"policy":"daily-snapshots","snapshots_taken":1,"snapshots_failed":1,"snapshots_deleted":0,"snapshot_deletion_failures":0}},"hourly-snapshots":{"version":1,"modified_date_millis":1585735200000,"policy":{"name":"<hourly-snap-{now/h}>","schedule":"0 0 * * * ?","repository":"my_repository"},"last_success":{"snapshot_name":"hourly-snap-2020.04.01-1sv7kdrjtdyxzm0qbgifvq","time_string":"2020-04-01T11:00:00.000Z","time":1585738800000},"last_failure":{"snapshot_name":"hourly-snap-2020.04.01-mxvdttbbr8qvkqbjy1t4gw","time_string":"2020-04-01T10:00:00.000Z","time":1585735200000,"details":"{\"type\":\"concurrent_snapshot_execution_exception\",\"reason\":\"[my_repository:hourly-snap-2020.04.01-mxvdttbbr8qvkqbjy1t4gw] a snapshot is already running\"}"},"next_execution_millis":1585742400000,"stats":{"policy":"hourly-snapshots","snapshots_taken":1,"snapshots_failed":1,"snapshots_deleted":0,"snapshot_deletion_failures":0}},"weekly-snapshots":{"version":1,"modified_date_millis":1585735200000,"policy":{"name":"<weekly-snap-{now/d}>","schedule":"0 0 2 ? * SUN","repository":"my_repository"},"next_execution_millis":1586052000000,"stats":{"policy":"weekly-snapshots","snapshots_taken":0,"snapshots_failed":0,"snapshots_deleted":0,"snapshot_deletion_failures":0}}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		s := NewSLM(log.NewNopLogger(), http.DefaultClient, u)
+		spr, err := s.fetchAndDecodeSLMPolicies()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode SLM policies: %s", err)
+		}
+		t.Logf("[%s] SLM Policies Response: %+v", ver, spr)
+		daily := spr["daily-snapshots"]
+		if daily.LastSuccess == nil || daily.LastSuccess.Time != 1585735500000 {
+			t.Errorf("Wrong last success")
+		}
+		if daily.LastFailure == nil || daily.LastFailure.Details == "" {
+			t.Errorf("Wrong last failure")
+		}
+		if !daily.LastExecutionFailed() {
+			t.Errorf("Last execution of the daily policy should have failed")
+		}
+		if spr["hourly-snapshots"].LastExecutionFailed() {
+			t.Errorf("Last execution of the hourly policy succeeded after the failure")
+		}
+		if spr["weekly-snapshots"].LastExecutionFailed() {
+			t.Errorf("Policy without executions should not be failed")
+		}
+	}
+}
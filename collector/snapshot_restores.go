@@ -0,0 +1,184 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// restoreStalledScrapes is the number of consecutive scrapes without restored bytes after which
+// a restore is considered stalled
+const restoreStalledScrapes = 3
+
+// restoreState holds the restored bytes of an index seen on the previous scrape
+type restoreState struct {
+	bytesDone int64
+	unchanged int
+}
+
+// SnapshotRestores information struct
+type SnapshotRestores struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	inProgress *prometheus.Desc
+	bytesTotal *prometheus.Desc
+	bytesDone  *prometheus.Desc
+	stalled    *prometheus.Desc
+
+	mu sync.Mutex
+	// previous holds the restored bytes of the indices restored on the previous scrape
+	previous map[string]restoreState
+}
+
+// NewSnapshotRestores defines SnapshotRestores Prometheus metrics
+func NewSnapshotRestores(logger log.Logger, client *http.Client, url *url.URL) *SnapshotRestores {
+	constLabels := constLabelsFromURL(url)
+	return &SnapshotRestores{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "snapshot_restores", "up"),
+			Help:        "Was the last scrape of the ElasticSearch recovery endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "snapshot_restores", "total_scrapes"),
+			Help:        "Current total ElasticSearch snapshot restores scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "snapshot_restores", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		inProgress: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot", "restores_in_progress"),
+			"Number of indices currently being restored from a snapshot",
+			nil, constLabels,
+		),
+		bytesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot", "restore_bytes_total"),
+			"Total bytes of the shards of an index being restored from a snapshot",
+			[]string{"index"}, constLabels,
+		),
+		bytesDone: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot", "restore_bytes_done"),
+			"Restored bytes of the shards of an index being restored from a snapshot",
+			[]string{"index"}, constLabels,
+		),
+		stalled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot", "restore_stalled"),
+			fmt.Sprintf("Whether the restore of an index did not progress for %d consecutive scrapes (1=stalled, 0=progressing)", restoreStalledScrapes),
+			[]string{"index"}, constLabels,
+		),
+
+		previous: make(map[string]restoreState),
+	}
+}
+
+// Describe add SnapshotRestores metrics descriptions
+func (s *SnapshotRestores) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.inProgress
+	ch <- s.bytesTotal
+	ch <- s.bytesDone
+	ch <- s.stalled
+	ch <- s.up.Desc()
+	ch <- s.totalScrapes.Desc()
+	ch <- s.jsonParseFailures.Desc()
+}
+
+func (s *SnapshotRestores) fetchAndDecodeSnapshotRestores() (SnapshotRestoresResponse, error) {
+	var srr SnapshotRestoresResponse
+
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_recovery")
+	u.RawQuery = "active_only=true"
+	err := getAndDecodeURL(s.logger, s.client, &u, &srr, s.jsonParseFailures)
+	return srr, err
+}
+
+// trackRestores compares the restored bytes of every index with the previous scrape and returns
+// whether each restore stalled. Indices which are no longer restored are forgotten.
+func (s *SnapshotRestores) trackRestores(restores map[string]restoreProgress) map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stalled := make(map[string]bool, len(restores))
+	current := make(map[string]restoreState, len(restores))
+	for index, progress := range restores {
+		state := restoreState{bytesDone: progress.bytesDone}
+		if previous, ok := s.previous[index]; ok && previous.bytesDone == progress.bytesDone {
+			state.unchanged = previous.unchanged + 1
+		}
+		current[index] = state
+		stalled[index] = state.unchanged >= restoreStalledScrapes
+	}
+	s.previous = current
+	return stalled
+}
+
+// Collect gets SnapshotRestores metric values
+func (s *SnapshotRestores) Collect(ch chan<- prometheus.Metric) {
+	s.totalScrapes.Inc()
+	defer func() {
+		ch <- s.up
+		ch <- s.totalScrapes
+		ch <- s.jsonParseFailures
+	}()
+
+	snapshotRestoresResp, err := s.fetchAndDecodeSnapshotRestores()
+	if err != nil {
+		s.up.Set(0)
+		_ = level.Warn(s.logger).Log(
+			"msg", "failed to fetch and decode snapshot restores",
+			"err", err,
+		)
+		return
+	}
+	s.up.Set(1)
+
+	restores := snapshotRestoresResp.restores()
+	stalled := s.trackRestores(restores)
+	ch <- prometheus.MustNewConstMetric(
+		s.inProgress,
+		prometheus.GaugeValue,
+		float64(len(restores)),
+	)
+	for index, progress := range restores {
+		var restoreStalled float64
+		if stalled[index] {
+			restoreStalled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			s.bytesTotal,
+			prometheus.GaugeValue,
+			float64(progress.bytesTotal),
+			index,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			s.bytesDone,
+			prometheus.GaugeValue,
+			float64(progress.bytesDone),
+			index,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			s.stalled,
+			prometheus.GaugeValue,
+			restoreStalled,
+			index,
+		)
+	}
+}
@@ -0,0 +1,53 @@
+package collector
+
+import "strings"
+
+// SnapshotRestoresResponse is a representation of the indices recovery API, keyed by index
+type SnapshotRestoresResponse map[string]SnapshotRestoresIndexResponse
+
+// SnapshotRestoresIndexResponse is a representation of the shard recoveries of a single index
+type SnapshotRestoresIndexResponse struct {
+	Shards []SnapshotRestoresShardResponse `json:"shards"`
+}
+
+// SnapshotRestoresShardResponse is a representation of a single shard recovery
+type SnapshotRestoresShardResponse struct {
+	ID    int64                              `json:"id"`
+	Type  string                             `json:"type"`
+	Stage string                             `json:"stage"`
+	Index SnapshotRestoresShardIndexResponse `json:"index"`
+}
+
+// SnapshotRestoresShardIndexResponse is a representation of the recovered files of a shard
+type SnapshotRestoresShardIndexResponse struct {
+	Size SnapshotRestoresShardSizeResponse `json:"size"`
+}
+
+// SnapshotRestoresShardSizeResponse is a representation of the recovered bytes of a shard
+type SnapshotRestoresShardSizeResponse struct {
+	TotalInBytes     int64 `json:"total_in_bytes"`
+	RecoveredInBytes int64 `json:"recovered_in_bytes"`
+}
+
+// restoreProgress holds the bytes of an index restored from a snapshot
+type restoreProgress struct {
+	bytesTotal, bytesDone int64
+}
+
+// restores sums the bytes of the shard recoveries from a snapshot per index, indices without
+// such recoveries are left out
+func (s SnapshotRestoresResponse) restores() map[string]restoreProgress {
+	restores := make(map[string]restoreProgress)
+	for index, recoveries := range s {
+		for _, shard := range recoveries.Shards {
+			if !strings.EqualFold(shard.Type, "SNAPSHOT") {
+				continue
+			}
+			progress := restores[index]
+			progress.bytesTotal += shard.Index.Size.TotalInBytes
+			progress.bytesDone += shard.Index.Size.RecoveredInBytes
+			restores[index] = progress
+		}
+	}
+	return restores
+}
@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestSnapshotRestores(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "path.repo=/tmp" elasticsearch:VERSION (and a second node)
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl -XPUT http://localhost:9200/_snapshot/test1/snapshot_1?wait_for_completion=true
+	//  curl -XDELETE http://localhost:9200/twitter
+	//  curl -XPOST http://localhost:9200/_snapshot/test1/snapshot_1/_restore
+	//  curl 'http://localhost:9200/_recovery?active_only=true' (while a replica of logs relocates)
+	tcs := map[string]string{
+		"7.10.2": `{"twitter":{"shards":[{"id":0,"type":"SNAPSHOT","stage":"INDEX","primary":true,"start_time_in_millis":1617271200000,"total_time_in_millis":12000,"source":{"repository":"test1","snapshot":"snapshot_1","version":"7.10.2","index":"twitter","restoreUUID":"3mWZ6YqCQ0K1o9Z5i4dHbg"},"target":{"id":"9_P7yui4SQOkzGhTZCyjxQ","host":"172.17.0.2","transport_address":"172.17.0.2:9300","ip":"172.17.0.2","name":"9_P7yui"},"index":{"size":{"total_in_bytes":1048576,"reused_in_bytes":0,"recovered_in_bytes":524288,"percent":"50.0%"},"files":{"total":10,"reused":0,"recovered":5,"percent":"50.0%"},"total_time_in_millis":11900,"source_throttle_time_in_millis":0,"target_throttle_time_in_millis":0},"translog":{"recovered":0,"total":0,"percent":"100.0%","total_on_start":0,"total_time_in_millis":0},"verify_index":{"check_index_time_in_millis":0,"total_time_in_millis":0}},{"id":1,"type":"SNAPSHOT","stage":"INDEX","primary":true,"start_time_in_millis":1617271200000,"total_time_in_millis":12000,"source":{"repository":"test1","snapshot":"snapshot_1","version":"7.10.2","index":"twitter","restoreUUID":"3mWZ6YqCQ0K1o9Z5i4dHbg"},"target":{"id":"Jx0Vt0hTR0iVbVGRx1oBCA","host":"172.17.0.3","transport_address":"172.17.0.3:9300","ip":"172.17.0.3","name":"Jx0Vt0h"},"index":{"size":{"total_in_bytes":2097152,"reused_in_bytes":0,"recovered_in_bytes":1048576,"percent":"50.0%"},"files":{"total":12,"reused":0,"recovered":6,"percent":"50.0%"},"total_time_in_millis":11900,"source_throttle_time_in_millis":0,"target_throttle_time_in_millis":0},"translog":{"recovered":0,"total":0,"percent":"100.0%","total_on_start":0,"total_time_in_millis":0},"verify_index":{"check_index_time_in_millis":0,"total_time_in_millis":0}}]},"logs":{"shards":[{"id":0,"type":"PEER","stage":"INDEX","primary":false,"start_time_in_millis":1617271200000,"total_time_in_millis":3000,"source":{"id":"9_P7yui4SQOkzGhTZCyjxQ","host":"172.17.0.2","transport_address":"172.17.0.2:9300","ip":"172.17.0.2","name":"9_P7yui"},"target":{"id":"Jx0Vt0hTR0iVbVGRx1oBCA","host":"172.17.0.3","transport_address":"172.17.0.3:9300","ip":"172.17.0.3","name":"Jx0Vt0h"},"index":{"size":{"total_in_bytes":4096,"reused_in_bytes":0,"recovered_in_bytes":1024,"percent":"25.0%"},"files":{"total":4,"reused":0,"recovered":1,"percent":"25.0%"},"total_time_in_millis":2900,"source_throttle_time_in_millis":0,"target_throttle_time_in_millis":0},"translog":{"recovered":0,"total":0,"percent":"100.0%","total_on_start":0,"total_time_in_millis":0},"verify_index":{"check_index_time_in_millis":0,"total_time_in_millis":0}}]}}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		s := NewSnapshotRestores(log.NewNopLogger(), http.DefaultClient, u)
+		srr, err := s.fetchAndDecodeSnapshotRestores()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode snapshot restores: %s", err)
+		}
+		t.Logf("[%s] Snapshot Restores Response: %+v", ver, srr)
+
+		restores := srr.restores()
+		if len(restores) != 1 {
+			t.Fatalf("[%s] Peer recoveries are counted as restores: %v", ver, restores)
+		}
+		if progress := restores["twitter"]; progress.bytesTotal != 3145728 || progress.bytesDone != 1572864 {
+			t.Errorf("[%s] Wrong restore progress of twitter: %+v", ver, progress)
+		}
+	}
+}
+
+func TestSnapshotRestoresStalled(t *testing.T) {
+	s := NewSnapshotRestores(log.NewNopLogger(), http.DefaultClient, &url.URL{})
+	scrapes := []map[string]restoreProgress{
+		{"twitter": {100, 10}, "logs": {100, 10}},
+		{"twitter": {100, 10}, "logs": {100, 20}},
+		{"twitter": {100, 10}, "logs": {100, 20}},
+		{"twitter": {100, 10}, "logs": {100, 30}},
+	}
+	var stalled map[string]bool
+	for _, restores := range scrapes {
+		stalled = s.trackRestores(restores)
+	}
+	if !stalled["twitter"] || stalled["logs"] {
+		t.Errorf("Wrong stalled restores: %v", stalled)
+	}
+
+	// a restore which finished and started again is not stalled
+	s.trackRestores(map[string]restoreProgress{"logs": {100, 30}})
+	if stalled = s.trackRestores(map[string]restoreProgress{"twitter": {100, 10}}); stalled["twitter"] {
+		t.Errorf("Restarted restore is stalled: %v", stalled)
+	}
+}
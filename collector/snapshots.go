@@ -1,11 +1,17 @@
 package collector
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -26,17 +32,215 @@ type repositoryMetric struct {
 	Labels func(repositoryName string) []string
 }
 
+type snapshotInProgressMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(snapshotStatus SnapshotStatusDataResponse) float64
+	Labels func(repositoryName string, snapshotStatus SnapshotStatusDataResponse) []string
+}
+
+type repositorySettingMetric struct {
+	Type    prometheus.ValueType
+	Desc    *prometheus.Desc
+	Setting string
+	Labels  func(repositoryName, repositoryType string) []string
+}
+
+type repositoryStateMetric struct {
+	Type   prometheus.ValueType
+	Desc   *prometheus.Desc
+	Value  func(snapshotsStats SnapshotStatsResponse, state string) float64
+	Labels func(repositoryName string, state string) []string
+}
+
 var (
+	snapshotStates = []string{"SUCCESS", "FAILED", "PARTIAL", "INCOMPATIBLE", "IN_PROGRESS"}
+
 	defaultSnapshotLabels      = []string{"repository", "state", "version"}
 	defaultSnapshotLabelValues = func(repositoryName string, snapshotStats SnapshotStatDataResponse) []string {
 		return []string{repositoryName, snapshotStats.State, snapshotStats.Version}
 	}
+	defaultSnapshotInProgressLabels      = []string{"repository", "snapshot"}
+	defaultSnapshotInProgressLabelValues = func(repositoryName string, snapshotStatus SnapshotStatusDataResponse) []string {
+		return []string{repositoryName, snapshotStatus.Snapshot}
+	}
 	defaultSnapshotRepositoryLabels      = []string{"repository"}
 	defaultSnapshotRepositoryLabelValues = func(repositoryName string) []string {
 		return []string{repositoryName}
 	}
+	defaultSnapshotRepositoryTypeLabels      = []string{"repository", "type"}
+	defaultSnapshotRepositoryTypeLabelValues = func(repositoryName, repositoryType string) []string {
+		return []string{repositoryName, repositoryType}
+	}
+
+	// esByteUnits maps the Elasticsearch byte size units to bytes, longest suffixes first
+	esByteUnits = []struct {
+		suffix string
+		bytes  float64
+	}{
+		{"kb", 1 << 10},
+		{"mb", 1 << 20},
+		{"gb", 1 << 30},
+		{"tb", 1 << 40},
+		{"pb", 1 << 50},
+		{"k", 1 << 10},
+		{"m", 1 << 20},
+		{"g", 1 << 30},
+		{"t", 1 << 40},
+		{"p", 1 << 50},
+		{"b", 1},
+	}
 )
 
+// repositoryVerification is the result of the last verification of a snapshot repository
+type repositoryVerification struct {
+	verifiedAt time.Time
+	accessible bool
+}
+
+// snapshotListCache holds the snapshot lists of the repositories until cacheExpiry, listing all
+// snapshots of large repositories is expensive and they change infrequently
+type snapshotListCache struct {
+	ttl         time.Duration
+	mu          sync.RWMutex
+	cacheExpiry time.Time
+	snapshots   map[string]SnapshotStatsResponse
+}
+
+// get returns the cached snapshot lists if they have not expired and cover all repositories
+func (c *snapshotListCache) get(repositories SnapshotRepositoriesResponse, now time.Time) (map[string]SnapshotStatsResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.snapshots == nil || !now.Before(c.cacheExpiry) {
+		return nil, false
+	}
+	snapshots := make(map[string]SnapshotStatsResponse, len(repositories))
+	for repository := range repositories {
+		ssr, ok := c.snapshots[repository]
+		if !ok {
+			return nil, false
+		}
+		snapshots[repository] = ssr
+	}
+	return snapshots, true
+}
+
+func (c *snapshotListCache) set(snapshots map[string]SnapshotStatsResponse, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.snapshots = snapshots
+	c.cacheExpiry = now.Add(c.ttl)
+}
+
+func (c *snapshotListCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.snapshots = nil
+	c.cacheExpiry = time.Time{}
+}
+
+// parseESByteSize parses an Elasticsearch byte size value like "40mb" or "1gb" into bytes
+func parseESByteSize(value string) (float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, unit := range esByteUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(value, unit.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid byte size value %q: %s", value, err)
+			}
+			return v * unit.bytes, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid byte size value %q: missing unit", value)
+}
+
+// recentSnapshotsSizes returns the total sizes of the last n snapshots which report their size
+func recentSnapshotsSizes(snapshotsStats SnapshotStatsResponse, n int) []int64 {
+	var sizes []int64
+	for i := len(snapshotsStats.Snapshots) - 1; i >= 0 && len(sizes) < n; i-- {
+		if stats := snapshotsStats.Snapshots[i].Stats; stats != nil {
+			sizes = append(sizes, stats.Total.SizeInBytes)
+		}
+	}
+	return sizes
+}
+
+// snapshotReuseRatio returns the total size of the last snapshot reporting its size divided by its
+// incremental size, the bytes it added to the repository. It is false when no snapshot reports its
+// size or the last one added nothing.
+func snapshotReuseRatio(snapshotsStats SnapshotStatsResponse) (float64, bool) {
+	for i := len(snapshotsStats.Snapshots) - 1; i >= 0; i-- {
+		stats := snapshotsStats.Snapshots[i].Stats
+		if stats == nil {
+			continue
+		}
+		if stats.Incremental.SizeInBytes == 0 {
+			return 0, false
+		}
+		return float64(stats.Total.SizeInBytes) / float64(stats.Incremental.SizeInBytes), true
+	}
+	return 0, false
+}
+
+// snapshotGrowthWindow is the period over which the daily growth of a repository is averaged
+const snapshotGrowthWindow = 7 * 24 * time.Hour
+
+// snapshotBytesPerDay estimates the daily growth of a repository as the average daily incremental size
+// of the snapshots started during the last week. The incremental size is what a snapshot added to the
+// repository, the total size includes the files shared with earlier snapshots.
+func snapshotBytesPerDay(snapshotsStats SnapshotStatsResponse, now time.Time) float64 {
+	since := now.Add(-snapshotGrowthWindow)
+	var sum int64
+	for _, snapshot := range snapshotsStats.Snapshots {
+		if snapshot.Stats == nil || time.Unix(0, snapshot.StartTimeInMillis*int64(time.Millisecond)).Before(since) {
+			continue
+		}
+		sum += snapshot.Stats.Incremental.SizeInBytes
+	}
+	return float64(sum) / (snapshotGrowthWindow.Hours() / 24)
+}
+
+// snapshotRepositoryUsedBytes estimates the used bytes of a repository as the sum of the incremental
+// sizes of the loaded snapshots. Files only referenced by deleted snapshots are removed from the
+// repository, so this can overestimate after deletions, and it underestimates with a limited history depth.
+func snapshotRepositoryUsedBytes(snapshotsStats SnapshotStatsResponse) float64 {
+	var sum int64
+	for _, snapshot := range snapshotsStats.Snapshots {
+		if snapshot.Stats != nil {
+			sum += snapshot.Stats.Incremental.SizeInBytes
+		}
+	}
+	return float64(sum)
+}
+
+// ParseRepositoryCapacities parses a comma separated list of repository=size pairs, like
+// "backups=2tb,archive=10tb", into the capacity in bytes per repository
+func ParseRepositoryCapacities(s string) (map[string]float64, error) {
+	capacities := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid repository capacity %q: expected repository=size", pair)
+		}
+		capacity, err := parseESByteSize(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid capacity of repository %q: %s", parts[0], err)
+		}
+		capacities[strings.TrimSpace(parts[0])] = capacity
+	}
+	return capacities, nil
+}
+
 // Snapshots information struct
 type Snapshots struct {
 	logger log.Logger
@@ -46,18 +250,69 @@ type Snapshots struct {
 	up                              prometheus.Gauge
 	totalScrapes, jsonParseFailures prometheus.Counter
 
-	snapshotMetrics   []*snapshotMetric
-	repositoryMetrics []*repositoryMetric
+	snapshotMetrics       []*snapshotMetric
+	repositoryMetrics     []*repositoryMetric
+	repositorySizeMetrics []*repositoryMetric
+	repositoryStateMetric *repositoryStateMetric
+	inProgressMetrics     []*snapshotInProgressMetric
+
+	repositorySettingMetrics []*repositorySettingMetric
+
+	repositoryAccessible    *prometheus.Desc
+	repositoryDaysUntilFull *prometheus.Desc
+	repositoryReuseRatio    *prometheus.Desc
+	repositoryCompress      *prometheus.Desc
+
+	repositoryCapacities    map[string]float64
+	historyDepth            int
+	verifyInterval          time.Duration
+	mu                      sync.Mutex
+	repositoryVerifications map[string]repositoryVerification
+	snapshotListCache       *snapshotListCache
+
+	buffer *metricsBuffer
 }
 
-// NewSnapshots defines Snapshots Prometheus metrics
-func NewSnapshots(logger log.Logger, client *http.Client, url *url.URL) *Snapshots {
+// NewSnapshots defines Snapshots Prometheus metrics. The average snapshot size is computed over the
+// last recentSnapshots snapshots of each repository. Only the last historyDepth snapshots of each
+// repository are loaded, a zero historyDepth loads all snapshots. Repositories are verified at most
+// once every verifyInterval, a zero verifyInterval disables the verification. The estimated days until
+// a repository is full are only exported for the repositories in repositoryCapacities. The snapshot
+// lists are reused for cacheTTL, a zero cacheTTL fetches them on every scrape.
+func NewSnapshots(logger log.Logger, client *http.Client, url *url.URL, recentSnapshots int, historyDepth int, verifyInterval time.Duration, repositoryCapacities map[string]float64, cacheTTL time.Duration) *Snapshots {
 	constLabels := constLabelsFromURL(url)
-	return &Snapshots{
+	snapshots := &Snapshots{
 		logger: logger,
 		client: client,
 		url:    url,
 
+		repositoryCapacities: repositoryCapacities,
+		repositoryDaysUntilFull: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot_repository", "estimated_days_until_full"),
+			"Estimated days until the repository reaches its configured capacity at the current daily growth",
+			defaultSnapshotRepositoryLabels, constLabels,
+		),
+
+		historyDepth:            historyDepth,
+		verifyInterval:          verifyInterval,
+		repositoryVerifications: make(map[string]repositoryVerification),
+		snapshotListCache:       &snapshotListCache{ttl: cacheTTL},
+		repositoryReuseRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot_stats", "snapshot_reuse_ratio"),
+			"Total size of the last snapshot divided by the bytes it added to the repository, higher values mean more files were reused from earlier snapshots",
+			defaultSnapshotRepositoryLabels, constLabels,
+		),
+		repositoryCompress: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot_repository", "compress"),
+			"Whether the repository compresses the snapshot metadata files (1=enabled, 0=disabled), only exported when configured on the repository",
+			defaultSnapshotRepositoryTypeLabels, constLabels,
+		),
+		repositoryAccessible: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snapshot_stats", "repository_accessible"),
+			"Whether all nodes could access the repository on its last verification (1=accessible, 0=verification failed)",
+			defaultSnapshotRepositoryLabels, constLabels,
+		),
+
 		up: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name:        prometheus.BuildFQName(namespace, "snapshot_stats", "up"),
 			Help:        "Was the last scrape of the ElasticSearch snapshots endpoint successful.",
@@ -168,6 +423,9 @@ func NewSnapshots(logger log.Logger, client *http.Client, url *url.URL) *Snapsho
 					defaultSnapshotRepositoryLabels, constLabels,
 				),
 				Value: func(snapshotsStats SnapshotStatsResponse) float64 {
+					if snapshotsStats.Total > 0 {
+						return float64(snapshotsStats.Total)
+					}
 					return float64(len(snapshotsStats.Snapshots))
 				},
 				Labels: defaultSnapshotRepositoryLabelValues,
@@ -180,15 +438,153 @@ func NewSnapshots(logger log.Logger, client *http.Client, url *url.URL) *Snapsho
 					defaultSnapshotRepositoryLabels, constLabels,
 				),
 				Value: func(snapshotsStats SnapshotStatsResponse) float64 {
-					if len(snapshotsStats.Snapshots) == 0 {
-						return 0
+					return float64(snapshotsStats.OldestStartTimeInMillis / 1000)
+				},
+				Labels: defaultSnapshotRepositoryLabelValues,
+			},
+		},
+		repositorySizeMetrics: []*repositoryMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_stats", "snapshot_size_bytes"),
+					"Total size in bytes of the last snapshot",
+					defaultSnapshotRepositoryLabels, constLabels,
+				),
+				Value: func(snapshotsStats SnapshotStatsResponse) float64 {
+					return float64(recentSnapshotsSizes(snapshotsStats, 1)[0])
+				},
+				Labels: defaultSnapshotRepositoryLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_stats", "avg_recent_size_bytes"),
+					"Average total size in bytes of the most recent snapshots",
+					defaultSnapshotRepositoryLabels, constLabels,
+				),
+				Value: func(snapshotsStats SnapshotStatsResponse) float64 {
+					sizes := recentSnapshotsSizes(snapshotsStats, recentSnapshots)
+					var sum int64
+					for _, size := range sizes {
+						sum += size
 					}
-					return float64(snapshotsStats.Snapshots[0].StartTimeInMillis / 1000)
+					return float64(sum) / float64(len(sizes))
+				},
+				Labels: defaultSnapshotRepositoryLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_repository", "bytes_per_day"),
+					"Estimated daily growth of the repository, the average daily incremental size of the snapshots of the last week",
+					defaultSnapshotRepositoryLabels, constLabels,
+				),
+				Value: func(snapshotsStats SnapshotStatsResponse) float64 {
+					return snapshotBytesPerDay(snapshotsStats, time.Now())
 				},
 				Labels: defaultSnapshotRepositoryLabelValues,
 			},
 		},
+		repositoryStateMetric: &repositoryStateMetric{
+			Type: prometheus.GaugeValue,
+			Desc: prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, "snapshot_stats", "snapshots_by_state"),
+				"Number of snapshots in a repository by state",
+				[]string{"repository", "state"}, constLabels,
+			),
+			Value: func(snapshotsStats SnapshotStatsResponse, state string) float64 {
+				var count int
+				for _, snapshot := range snapshotsStats.Snapshots {
+					if snapshot.State == state {
+						count++
+					}
+				}
+				return float64(count)
+			},
+			Labels: func(repositoryName string, state string) []string {
+				return []string{repositoryName, state}
+			},
+		},
+		repositorySettingMetrics: []*repositorySettingMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_repository", "max_snapshot_rate_bytes_per_sec"),
+					"Configured maximum rate in bytes per second at which snapshots are written to the repository",
+					defaultSnapshotRepositoryTypeLabels, constLabels,
+				),
+				Setting: "max_snapshot_bytes_per_sec",
+				Labels:  defaultSnapshotRepositoryTypeLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_repository", "max_restore_rate_bytes_per_sec"),
+					"Configured maximum rate in bytes per second at which snapshots are restored from the repository",
+					defaultSnapshotRepositoryTypeLabels, constLabels,
+				),
+				Setting: "max_restore_bytes_per_sec",
+				Labels:  defaultSnapshotRepositoryTypeLabelValues,
+			},
+		},
+		inProgressMetrics: []*snapshotInProgressMetric{
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_stats", "in_progress_shards_started"),
+					"Number of started shards of the running snapshot",
+					defaultSnapshotInProgressLabels, constLabels,
+				),
+				Value: func(snapshotStatus SnapshotStatusDataResponse) float64 {
+					return float64(snapshotStatus.ShardsStats.Started)
+				},
+				Labels: defaultSnapshotInProgressLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_stats", "in_progress_shards_total"),
+					"Total number of shards of the running snapshot",
+					defaultSnapshotInProgressLabels, constLabels,
+				),
+				Value: func(snapshotStatus SnapshotStatusDataResponse) float64 {
+					return float64(snapshotStatus.ShardsStats.Total)
+				},
+				Labels: defaultSnapshotInProgressLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_stats", "in_progress_bytes_done"),
+					"Bytes already written by the running snapshot",
+					defaultSnapshotInProgressLabels, constLabels,
+				),
+				Value: func(snapshotStatus SnapshotStatusDataResponse) float64 {
+					return float64(snapshotStatus.Stats.BytesDone())
+				},
+				Labels: defaultSnapshotInProgressLabelValues,
+			},
+			{
+				Type: prometheus.GaugeValue,
+				Desc: prometheus.NewDesc(
+					prometheus.BuildFQName(namespace, "snapshot_stats", "in_progress_bytes_total"),
+					"Total bytes the running snapshot has to write",
+					defaultSnapshotInProgressLabels, constLabels,
+				),
+				Value: func(snapshotStatus SnapshotStatusDataResponse) float64 {
+					return float64(snapshotStatus.Stats.BytesTotal())
+				},
+				Labels: defaultSnapshotInProgressLabelValues,
+			},
+		},
 	}
+	snapshots.buffer = newMetricsBuffer(snapshots.collect, prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        prometheus.BuildFQName(namespace, "snapshot_stats", "last_refresh_timestamp_seconds"),
+		Help:        "Time of the last successful background refresh of the snapshot metrics in seconds since epoch.",
+		ConstLabels: constLabels,
+	}))
+	return snapshots
 }
 
 // Describe add Snapshots metrics descriptions
@@ -196,15 +592,141 @@ func (s *Snapshots) Describe(ch chan<- *prometheus.Desc) {
 	for _, metric := range s.snapshotMetrics {
 		ch <- metric.Desc
 	}
+	for _, metric := range s.repositoryMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range s.repositorySizeMetrics {
+		ch <- metric.Desc
+	}
+	ch <- s.repositoryStateMetric.Desc
+	for _, metric := range s.inProgressMetrics {
+		ch <- metric.Desc
+	}
+	for _, metric := range s.repositorySettingMetrics {
+		ch <- metric.Desc
+	}
+	ch <- s.repositoryAccessible
+	ch <- s.repositoryDaysUntilFull
+	ch <- s.repositoryReuseRatio
+	ch <- s.repositoryCompress
+	s.buffer.Describe(ch)
 	ch <- s.up.Desc()
 	ch <- s.totalScrapes.Desc()
 	ch <- s.jsonParseFailures.Desc()
 }
 
-func (s *Snapshots) getAndParseURL(u *url.URL, data interface{}) error {
-	res, err := s.client.Get(u.String())
+func (s *Snapshots) fetchAndDecodeSnapshotsStats() (map[string]SnapshotStatsResponse, SnapshotRepositoriesResponse, error) {
+	mssr := make(map[string]SnapshotStatsResponse)
+
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_snapshot")
+	var srr SnapshotRepositoriesResponse
+	err := getAndDecodeURL(s.logger, s.client, &u, &srr, s.jsonParseFailures)
+	if err != nil {
+		s.snapshotListCache.invalidate()
+		return nil, nil, err
+	}
+	if cached, ok := s.snapshotListCache.get(srr, time.Now()); ok {
+		return cached, srr, nil
+	}
+
+	var failed bool
+	for repository := range srr {
+		u := *s.url
+		u.Path = path.Join(u.Path, "/_snapshot", repository, "/_all")
+		if s.historyDepth > 0 {
+			u.RawQuery = fmt.Sprintf("size=%d&sort=start_time&order=desc", s.historyDepth)
+		}
+		var ssr SnapshotStatsResponse
+		err := getAndDecodeURL(s.logger, s.client, &u, &ssr, s.jsonParseFailures)
+		if err != nil {
+			failed = true
+			continue
+		}
+		// the order of the snapshots is not guaranteed, the metrics expect the oldest snapshot first
+		sort.SliceStable(ssr.Snapshots, func(i, j int) bool {
+			return ssr.Snapshots[i].StartTimeInMillis < ssr.Snapshots[j].StartTimeInMillis
+		})
+		if s.historyDepth > 0 && ssr.Total > int64(len(ssr.Snapshots)) {
+			oldest, err := s.fetchAndDecodeOldestSnapshot(repository)
+			if err != nil {
+				failed = true
+				continue
+			}
+			ssr.OldestStartTimeInMillis = oldest
+		} else if len(ssr.Snapshots) > 0 {
+			ssr.OldestStartTimeInMillis = ssr.Snapshots[0].StartTimeInMillis
+		}
+		mssr[repository] = ssr
+	}
+
+	// incomplete snapshot lists are never cached
+	if failed {
+		s.snapshotListCache.invalidate()
+	} else {
+		s.snapshotListCache.set(mssr, time.Now())
+	}
+	return mssr, srr, nil
+}
+
+// fetchAndDecodeOldestSnapshot returns the start time of the oldest snapshot of the repository,
+// which is not loaded when the history depth is below the number of snapshots
+func (s *Snapshots) fetchAndDecodeOldestSnapshot(repository string) (int64, error) {
+	var ssr SnapshotStatsResponse
+
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_snapshot", repository, "/_all")
+	u.RawQuery = "size=1&sort=start_time&order=asc"
+	if err := getAndDecodeURL(s.logger, s.client, &u, &ssr, s.jsonParseFailures); err != nil {
+		return 0, err
+	}
+	if len(ssr.Snapshots) == 0 {
+		return 0, nil
+	}
+	return ssr.Snapshots[0].StartTimeInMillis, nil
+}
+
+func (s *Snapshots) fetchAndDecodeSnapshotsStatus(repository string) (SnapshotsStatusResponse, error) {
+	var ssr SnapshotsStatusResponse
+
+	u := *s.url
+	// without a snapshot name only the currently running snapshots are returned
+	u.Path = path.Join(u.Path, "/_snapshot", repository, "/_status")
+	err := getAndDecodeURL(s.logger, s.client, &u, &ssr, s.jsonParseFailures)
+	return ssr, err
+}
+
+// verifyRepository checks that all nodes can access the repository. The check does actual I/O
+// on the repository, so its result is reused until verifyInterval has passed.
+func (s *Snapshots) verifyRepository(repository string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.repositoryVerifications[repository]; ok && time.Since(last.verifiedAt) < s.verifyInterval {
+		return last.accessible
+	}
+
+	err := s.postVerifyRepository(repository)
 	if err != nil {
-		return fmt.Errorf("failed to get from %s://%s:%s%s: %s",
+		_ = level.Warn(s.logger).Log(
+			"msg", "failed to verify snapshot repository",
+			"repository", repository,
+			"err", err,
+		)
+	}
+	s.repositoryVerifications[repository] = repositoryVerification{
+		verifiedAt: time.Now(),
+		accessible: err == nil,
+	}
+	return err == nil
+}
+
+func (s *Snapshots) postVerifyRepository(repository string) error {
+	u := *s.url
+	u.Path = path.Join(u.Path, "/_snapshot", repository, "/_verify")
+	res, err := s.client.Post(u.String(), "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to post to %s://%s:%s%s: %s",
 			u.Scheme, u.Hostname(), u.Port(), u.Path, err)
 	}
 
@@ -219,42 +741,29 @@ func (s *Snapshots) getAndParseURL(u *url.URL, data interface{}) error {
 	}()
 
 	if res.StatusCode != http.StatusOK {
+		var er errorResponse
+		if json.NewDecoder(res.Body).Decode(&er) == nil && er.Error.Reason != "" {
+			return fmt.Errorf("HTTP Request failed with code %d: %s", res.StatusCode, er.Error.Reason)
+		}
 		return fmt.Errorf("HTTP Request failed with code %d", res.StatusCode)
 	}
-
-	if err := json.NewDecoder(res.Body).Decode(data); err != nil {
-		s.jsonParseFailures.Inc()
-		return err
-	}
 	return nil
 }
 
-func (s *Snapshots) fetchAndDecodeSnapshotsStats() (map[string]SnapshotStatsResponse, error) {
-	mssr := make(map[string]SnapshotStatsResponse)
-
-	u := *s.url
-	u.Path = path.Join(u.Path, "/_snapshot")
-	var srr SnapshotRepositoriesResponse
-	err := s.getAndParseURL(&u, &srr)
-	if err != nil {
-		return nil, err
-	}
-	for repository := range srr {
-		u := *s.url
-		u.Path = path.Join(u.Path, "/_snapshot", repository, "/_all")
-		var ssr SnapshotStatsResponse
-		err := s.getAndParseURL(&u, &ssr)
-		if err != nil {
-			continue
-		}
-		mssr[repository] = ssr
-	}
-
-	return mssr, nil
+// Run refreshes the snapshot metrics in the background every interval until ctx is cancelled.
+// Collect then serves the last complete set of metrics instead of querying the cluster, so a slow
+// cluster never leads to a partial set of metrics.
+func (s *Snapshots) Run(ctx context.Context, interval time.Duration) {
+	s.buffer.Run(ctx, interval)
 }
 
-// Collect gets Snapshots metric values
+// Collect gets Snapshots metric values, from the buffer filled by Run if available
 func (s *Snapshots) Collect(ch chan<- prometheus.Metric) {
+	s.buffer.Collect(ch)
+}
+
+// collect fetches the snapshots and sends the snapshot metrics, it returns the error of the snapshot requests
+func (s *Snapshots) collect(ch chan<- prometheus.Metric) error {
 	s.totalScrapes.Inc()
 	defer func() {
 		ch <- s.up
@@ -263,17 +772,55 @@ func (s *Snapshots) Collect(ch chan<- prometheus.Metric) {
 	}()
 
 	// indices
-	snapshotsStatsResp, err := s.fetchAndDecodeSnapshotsStats()
+	snapshotsStatsResp, repositoriesResp, err := s.fetchAndDecodeSnapshotsStats()
 	if err != nil {
 		s.up.Set(0)
 		_ = level.Warn(s.logger).Log(
 			"msg", "failed to fetch and decode snapshot stats",
 			"err", err,
 		)
-		return
+		return err
 	}
 	s.up.Set(1)
 
+	// Repository settings, only reported when configured on the repository
+	for repositoryName, repository := range repositoriesResp {
+		for _, metric := range s.repositorySettingMetrics {
+			setting, ok := repository.Settings[metric.Setting]
+			if !ok {
+				continue
+			}
+			value, err := parseESByteSize(setting)
+			if err != nil {
+				_ = level.Warn(s.logger).Log(
+					"msg", "failed to parse snapshot repository setting",
+					"repository", repositoryName,
+					"setting", metric.Setting,
+					"err", err,
+				)
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				value,
+				metric.Labels(repositoryName, repository.Type)...,
+			)
+		}
+		if compress, ok := repository.Settings["compress"]; ok {
+			var enabled float64
+			if compress == "true" {
+				enabled = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				s.repositoryCompress,
+				prometheus.GaugeValue,
+				enabled,
+				defaultSnapshotRepositoryTypeLabelValues(repositoryName, repository.Type)...,
+			)
+		}
+	}
+
 	// Snapshots stats
 	for repositoryName, snapshotStats := range snapshotsStatsResp {
 		for _, metric := range s.repositoryMetrics {
@@ -284,6 +831,59 @@ func (s *Snapshots) Collect(ch chan<- prometheus.Metric) {
 				metric.Labels(repositoryName)...,
 			)
 		}
+		for _, state := range snapshotStates {
+			ch <- prometheus.MustNewConstMetric(
+				s.repositoryStateMetric.Desc,
+				s.repositoryStateMetric.Type,
+				s.repositoryStateMetric.Value(snapshotStats, state),
+				s.repositoryStateMetric.Labels(repositoryName, state)...,
+			)
+		}
+		s.collectSnapshotsInProgress(ch, repositoryName)
+
+		if s.verifyInterval > 0 {
+			var accessible float64
+			if s.verifyRepository(repositoryName) {
+				accessible = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				s.repositoryAccessible,
+				prometheus.GaugeValue,
+				accessible,
+				defaultSnapshotRepositoryLabelValues(repositoryName)...,
+			)
+		}
+
+		// Snapshot sizes, only reported by some versions
+		if len(recentSnapshotsSizes(snapshotStats, 1)) > 0 {
+			for _, metric := range s.repositorySizeMetrics {
+				ch <- prometheus.MustNewConstMetric(
+					metric.Desc,
+					metric.Type,
+					metric.Value(snapshotStats),
+					metric.Labels(repositoryName)...,
+				)
+			}
+			if ratio, ok := snapshotReuseRatio(snapshotStats); ok {
+				ch <- prometheus.MustNewConstMetric(
+					s.repositoryReuseRatio,
+					prometheus.GaugeValue,
+					ratio,
+					defaultSnapshotRepositoryLabelValues(repositoryName)...,
+				)
+			}
+			if capacity, ok := s.repositoryCapacities[repositoryName]; ok {
+				if bytesPerDay := snapshotBytesPerDay(snapshotStats, time.Now()); bytesPerDay > 0 {
+					ch <- prometheus.MustNewConstMetric(
+						s.repositoryDaysUntilFull,
+						prometheus.GaugeValue,
+						(capacity-snapshotRepositoryUsedBytes(snapshotStats))/bytesPerDay,
+						defaultSnapshotRepositoryLabelValues(repositoryName)...,
+					)
+				}
+			}
+		}
+
 		if len(snapshotStats.Snapshots) == 0 {
 			continue
 		}
@@ -298,4 +898,28 @@ func (s *Snapshots) Collect(ch chan<- prometheus.Metric) {
 			)
 		}
 	}
+	return nil
+}
+
+func (s *Snapshots) collectSnapshotsInProgress(ch chan<- prometheus.Metric, repositoryName string) {
+	snapshotsStatusResp, err := s.fetchAndDecodeSnapshotsStatus(repositoryName)
+	if err != nil {
+		_ = level.Warn(s.logger).Log(
+			"msg", "failed to fetch and decode running snapshots status",
+			"repository", repositoryName,
+			"err", err,
+		)
+		return
+	}
+
+	for _, snapshotStatus := range snapshotsStatusResp.Snapshots {
+		for _, metric := range s.inProgressMetrics {
+			ch <- prometheus.MustNewConstMetric(
+				metric.Desc,
+				metric.Type,
+				metric.Value(snapshotStatus),
+				metric.Labels(repositoryName, snapshotStatus)...,
+			)
+		}
+	}
 }
@@ -5,6 +5,11 @@ import "time"
 // SnapshotStatsResponse is a representation of the snapshots stats
 type SnapshotStatsResponse struct {
 	Snapshots []SnapshotStatDataResponse `json:"snapshots"`
+	// Total is the number of snapshots in the repository, only reported starting with 7.14
+	Total int64 `json:"total"`
+	// OldestStartTimeInMillis is the start time of the oldest snapshot of the repository, which is
+	// not part of Snapshots when only the most recent snapshots are loaded
+	OldestStartTimeInMillis int64 `json:"-"`
 }
 
 // SnapshotStatDataResponse is a representation of the single snapshot stat
@@ -26,6 +31,8 @@ type SnapshotStatDataResponse struct {
 		Failed     int64 `json:"failed"`
 		Successful int64 `json:"successful"`
 	} `json:"shards"`
+	// Stats is only reported by versions which include the snapshot size in the snapshot list
+	Stats *SnapshotStatusStatsResponse `json:"stats"`
 }
 
 // SnapshotRepositoriesResponse is a representation snapshots repositories
@@ -33,3 +40,57 @@ type SnapshotRepositoriesResponse map[string]struct {
 	Type     string            `json:"type"`
 	Settings map[string]string `json:"settings"`
 }
+
+// SnapshotsStatusResponse is a representation of the status of the currently running snapshots
+type SnapshotsStatusResponse struct {
+	Snapshots []SnapshotStatusDataResponse `json:"snapshots"`
+}
+
+// SnapshotStatusDataResponse is a representation of the status of a single running snapshot
+type SnapshotStatusDataResponse struct {
+	Snapshot    string `json:"snapshot"`
+	Repository  string `json:"repository"`
+	UUID        string `json:"uuid"`
+	State       string `json:"state"`
+	ShardsStats struct {
+		Initializing int64 `json:"initializing"`
+		Started      int64 `json:"started"`
+		Finalizing   int64 `json:"finalizing"`
+		Done         int64 `json:"done"`
+		Failed       int64 `json:"failed"`
+		Total        int64 `json:"total"`
+	} `json:"shards_stats"`
+	Stats SnapshotStatusStatsResponse `json:"stats"`
+}
+
+// SnapshotStatusStatsResponse is a representation of the file stats of a running snapshot.
+// Elasticsearch 7.4 moved the flat size fields into the incremental, processed and total objects.
+type SnapshotStatusStatsResponse struct {
+	Incremental          SnapshotStatusFileStatsResponse `json:"incremental"`
+	Processed            SnapshotStatusFileStatsResponse `json:"processed"`
+	Total                SnapshotStatusFileStatsResponse `json:"total"`
+	TotalSizeInBytes     int64                           `json:"total_size_in_bytes"`
+	ProcessedSizeInBytes int64                           `json:"processed_size_in_bytes"`
+}
+
+// SnapshotStatusFileStatsResponse is a representation of a file count and size pair
+type SnapshotStatusFileStatsResponse struct {
+	FileCount   int64 `json:"file_count"`
+	SizeInBytes int64 `json:"size_in_bytes"`
+}
+
+// BytesDone returns the number of bytes already written by the running snapshot
+func (s SnapshotStatusStatsResponse) BytesDone() int64 {
+	if s.Processed.SizeInBytes > 0 || s.Incremental.SizeInBytes > 0 {
+		return s.Processed.SizeInBytes
+	}
+	return s.ProcessedSizeInBytes
+}
+
+// BytesTotal returns the number of bytes the running snapshot has to write
+func (s SnapshotStatusStatsResponse) BytesTotal() int64 {
+	if s.Incremental.SizeInBytes > 0 {
+		return s.Incremental.SizeInBytes
+	}
+	return s.TotalSizeInBytes
+}
@@ -5,9 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestSnapshots(t *testing.T) {
@@ -43,8 +47,8 @@ func TestSnapshots(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to parse URL: %s", err)
 		}
-		s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u)
-		stats, err := s.fetchAndDecodeSnapshotsStats()
+		s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 5, 0, 0, nil, 0)
+		stats, _, err := s.fetchAndDecodeSnapshotsStats()
 		if err != nil {
 			t.Fatalf("Failed to fetch or decode snapshots stats: %s", err)
 		}
@@ -70,6 +74,423 @@ func TestSnapshots(t *testing.T) {
 		if len(repositoryStats.Snapshots) != 1 {
 			t.Errorf("Bad number of repository snapshots")
 		}
+		if s.repositoryStateMetric.Value(repositoryStats, "SUCCESS") != 1 {
+			t.Errorf("Bad number of successful repository snapshots")
+		}
+		if s.repositoryStateMetric.Value(repositoryStats, "PARTIAL") != 0 {
+			t.Errorf("Bad number of partial repository snapshots")
+		}
+	}
+
+}
+
+func TestSnapshotsStatus(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e path.repo="/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -d '{"type": "fs","settings":{"location": "/tmp/test1"}}'
+	//  curl -XPUT http://localhost:9200/_snapshot/test1/snapshot_1
+	//  curl http://localhost:9200/_snapshot/test1/_status
+	tcs := map[string]string{
+		"6.8.8": `{"snapshots":[{"snapshot":"snapshot_1","repository":"test1","uuid":"gHkt6F0zQeWdGiNSv5_4Ig","state":"STARTED","include_global_state":true,"shards_stats":{"initializing":0,"started":2,"finalizing":0,"done":8,"failed":0,"total":10},"stats":{"number_of_files":40,"processed_files":30,"total_size_in_bytes":4096,"processed_size_in_bytes":3072,"start_time_in_millis":1585735200000,"time_in_millis":1200},"indices":{}}]}`,
+		"7.6.2": `{"snapshots":[{"snapshot":"snapshot_1","repository":"test1","uuid":"gHkt6F0zQeWdGiNSv5_4Ig","state":"STARTED","include_global_state":true,"shards_stats":{"initializing":0,"started":2,"finalizing":0,"done":8,"failed":0,"total":10},"stats":{"incremental":{"file_count":40,"size_in_bytes":4096},"processed":{"file_count":30,"size_in_bytes":3072},"total":{"file_count":60,"size_in_bytes":8192},"start_time_in_millis":1585735200000,"time_in_millis":1200},"indices":{}}]}`,
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 5, 0, 0, nil, 0)
+		ssr, err := s.fetchAndDecodeSnapshotsStatus("test1")
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode snapshots status: %s", err)
+		}
+		t.Logf("[%s] Snapshots Status Response: %+v", ver, ssr)
+		if len(ssr.Snapshots) != 1 {
+			t.Fatalf("Bad number of running snapshots")
+		}
+		expected := []float64{2, 10, 3072, 4096}
+		for i, metric := range s.inProgressMetrics {
+			if v := metric.Value(ssr.Snapshots[0]); v != expected[i] {
+				t.Errorf("Bad value for in progress metric %d: got %v, expected %v", i, v, expected[i])
+			}
+		}
+	}
+}
+
+func TestSnapshotsRecentSizes(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl -XPUT "http://localhost:9200/_snapshot/test1/snapshot_1?wait_for_completion=true" (repeated for snapshot_2 and snapshot_3)
+	//  curl http://localhost:9200/_snapshot/test1/_all
+	out := []string{
+		`{"test1":{"type":"fs","settings":{"location":"/tmp/test1"}}}`,
+		`{"snapshots":[{"snapshot":"snapshot_1","uuid":"5gJc2JbYTLWl8LuUu8mK2g","version_id":7060299,"version":"7.6.2","indices":["foo_1"],"state":"SUCCESS","start_time_in_millis":1585735200000,"end_time_in_millis":1585735260000,"duration_in_millis":60000,"failures":[],"shards":{"total":1,"failed":0,"successful":1},"stats":{"incremental":{"file_count":10,"size_in_bytes":1000},"total":{"file_count":10,"size_in_bytes":1000}}},{"snapshot":"snapshot_2","uuid":"tc8oVxE4Tk2WAqCHvnk0yA","version_id":7060299,"version":"7.6.2","indices":["foo_1"],"state":"SUCCESS","start_time_in_millis":1585821600000,"end_time_in_millis":1585821660000,"duration_in_millis":60000,"failures":[],"shards":{"total":1,"failed":0,"successful":1},"stats":{"incremental":{"file_count":2,"size_in_bytes":500},"total":{"file_count":12,"size_in_bytes":2000}}},{"snapshot":"snapshot_3","uuid":"iO1Hm2xfQp6QkW3Te4Jo9Q","version_id":7060299,"version":"7.6.2","indices":["foo_1"],"state":"SUCCESS","start_time_in_millis":1585908000000,"end_time_in_millis":1585908060000,"duration_in_millis":60000,"failures":[],"shards":{"total":1,"failed":0,"successful":1},"stats":{"incremental":{"file_count":3,"size_in_bytes":1000},"total":{"file_count":15,"size_in_bytes":3000}}}]}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/_snapshot" {
+			fmt.Fprint(w, out[0])
+			return
+		}
+		fmt.Fprint(w, out[1])
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 2, 0, 0, nil, 0)
+	stats, _, err := s.fetchAndDecodeSnapshotsStats()
+	if err != nil {
+		t.Fatalf("Failed to fetch or decode snapshots stats: %s", err)
+	}
+	repositoryStats := stats["test1"]
+	expected := []float64{3000, 2500}
+	for i, metric := range s.repositorySizeMetrics[:len(expected)] {
+		if v := metric.Value(repositoryStats); v != expected[i] {
+			t.Errorf("Wrong value for repository size metric %d: got %v, expected %v", i, v, expected[i])
+		}
+	}
+	// a day after snapshot_3, all snapshots started during the last week
+	if v := snapshotBytesPerDay(repositoryStats, time.Unix(1585994400, 0)); v != 2500.0/7 {
+		t.Errorf("Wrong bytes per day: %v", v)
+	}
+	// a week and an hour after snapshot_1 started
+	if v := snapshotBytesPerDay(repositoryStats, time.Unix(1586343600, 0)); v != 1500.0/7 {
+		t.Errorf("Wrong bytes per day without snapshot_1: %v", v)
+	}
+	if v := snapshotRepositoryUsedBytes(repositoryStats); v != 2500 {
+		t.Errorf("Wrong repository used bytes: %v", v)
+	}
+	// snapshot_3 added 1000 of its 3000 bytes
+	if ratio, ok := snapshotReuseRatio(repositoryStats); !ok || ratio != 3 {
+		t.Errorf("Wrong snapshot reuse ratio: %v", ratio)
+	}
+	repositoryStats.Snapshots[0].Stats = nil
+	if sizes := recentSnapshotsSizes(repositoryStats, 5); len(sizes) != 2 {
+		t.Errorf("Snapshots without stats should be skipped, got %v", sizes)
+	}
+}
+
+func TestSnapshotsVerifyRepository(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl -XPOST http://localhost:9200/_snapshot/test1/_verify (before and after chmod 000 /tmp/test1)
+	tcs := map[string]struct {
+		status   int
+		body     string
+		expected bool
+	}{
+		"accessible": {http.StatusOK, `{"nodes":{"9_P7yui4SQOkzGhTZCyjxQ":{"name":"node-0"}}}`, true},
+		"failed":     {http.StatusInternalServerError, `{"error":{"root_cause":[{"type":"repository_verification_exception","reason":"[test1] path  is not accessible on master node"}],"type":"repository_verification_exception","reason":"[test1] path  is not accessible on master node","caused_by":{"type":"access_denied_exception","reason":"/tmp/test1/tests-6tIbKbBqRBeyzoZcRnMdgA"}},"status":500}`, false},
+	}
+	for name, tc := range tcs {
+		var verifications int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/_snapshot/test1/_verify" {
+				t.Errorf("[%s] Unexpected request %s %s", name, r.Method, r.URL.Path)
+			}
+			verifications++
+			w.WriteHeader(tc.status)
+			fmt.Fprint(w, tc.body)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 5, 0, time.Hour, nil, 0)
+		for i := 0; i < 2; i++ {
+			if accessible := s.verifyRepository("test1"); accessible != tc.expected {
+				t.Errorf("[%s] Wrong repository accessibility: got %v, expected %v", name, accessible, tc.expected)
+			}
+		}
+		if verifications != 1 {
+			t.Errorf("[%s] Repository should be verified once per interval, got %d verifications", name, verifications)
+		}
+
+		s.repositoryVerifications["test1"] = repositoryVerification{verifiedAt: time.Now().Add(-2 * time.Hour)}
+		s.verifyRepository("test1")
+		if verifications != 2 {
+			t.Errorf("[%s] Repository should be verified again after the interval, got %d verifications", name, verifications)
+		}
+	}
+}
+
+func TestSnapshotsBuffered(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl http://localhost:9200/_snapshot
+	//  curl http://localhost:9200/_snapshot/test1/_all
+	//  curl http://localhost:9200/_snapshot/test1/_status
+	out := map[string]string{
+		"/_snapshot":               `{"test1":{"type":"fs","settings":{"location":"/tmp/test1"}}}`,
+		"/_snapshot/test1/_all":    `{"snapshots":[]}`,
+		"/_snapshot/test1/_status": `{"snapshots":[]}`,
+	}
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, out[r.URL.Path])
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 5, 0, 0, nil, 0)
+	collect := func() int {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			s.Collect(ch)
+			close(ch)
+		}()
+		var count int
+		for range ch {
+			count++
+		}
+		return count
+	}
+
+	// without a buffer the cluster is queried on every scrape
+	live := collect()
+	if requests != 3 {
+		t.Fatalf("Expected 3 requests for a live collect, got %d", requests)
+	}
+
+	s.buffer.refresh(time.Now())
+	requests = 0
+	for i := 0; i < 2; i++ {
+		// the buffered metrics are served with the last refresh time
+		if count := collect(); count != live+1 {
+			t.Errorf("Wrong number of buffered metrics: got %d, expected %d", count, live+1)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Buffered collects should not query the cluster, got %d requests", requests)
+	}
+}
+
+func TestSnapshotsCacheTTL(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl http://localhost:9200/_snapshot
+	//  curl http://localhost:9200/_snapshot/test1/_all
+	out := map[string]string{
+		"/_snapshot":            `{"test1":{"type":"fs","settings":{"location":"/tmp/test1"}}}`,
+		"/_snapshot/test1/_all": `{"snapshots":[]}`,
+	}
+	var listRequests int
+	var unavailable bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/_snapshot/test1/_all" {
+			listRequests++
+		}
+		fmt.Fprint(w, out[r.URL.Path])
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 5, 0, 0, nil, time.Hour)
+	fetch := func() {
+		if _, _, err := s.fetchAndDecodeSnapshotsStats(); err != nil {
+			t.Fatalf("Failed to fetch or decode snapshots stats: %s", err)
+		}
+	}
+
+	fetch()
+	fetch()
+	if listRequests != 1 {
+		t.Errorf("Expected the snapshot list to be served from the cache, got %d requests", listRequests)
+	}
+
+	// a failed request invalidates the cache
+	unavailable = true
+	if _, _, err := s.fetchAndDecodeSnapshotsStats(); err == nil {
+		t.Fatalf("Expected an error while the cluster is unavailable")
+	}
+	unavailable = false
+	fetch()
+	if listRequests != 2 {
+		t.Errorf("Expected the snapshot list to be fetched again after an error, got %d requests", listRequests)
+	}
+
+	// a new repository is not in the cache yet
+	out["/_snapshot"] = `{"test1":{"type":"fs","settings":{"location":"/tmp/test1"}},"test2":{"type":"fs","settings":{"location":"/tmp/test2"}}}`
+	out["/_snapshot/test2/_all"] = `{"snapshots":[]}`
+	fetch()
+	if listRequests != 3 {
+		t.Errorf("Expected the snapshot lists to be fetched again for a new repository, got %d requests", listRequests)
+	}
+}
+
+func TestSnapshotsRepositorySettings(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/backups -H 'Content-Type: application/json' -d '{"type":"s3","settings":{"bucket":"backups","compress":true,"max_snapshot_bytes_per_sec":"20mb","max_restore_bytes_per_sec":"1gb"}}'
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl http://localhost:9200/_snapshot
+	out := `{"backups":{"type":"s3","settings":{"bucket":"backups","compress":"true","max_snapshot_bytes_per_sec":"20mb","max_restore_bytes_per_sec":"1gb"}},"test1":{"type":"fs","settings":{"location":"/tmp/test1"}}}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_snapshot" {
+			fmt.Fprint(w, out)
+			return
+		}
+		fmt.Fprint(w, `{"snapshots":[]}`)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 5, 0, 0, nil, 0)
+	_, repositories, err := s.fetchAndDecodeSnapshotsStats()
+	if err != nil {
+		t.Fatalf("Failed to fetch or decode snapshot repositories: %s", err)
+	}
+	if repositories["backups"].Type != "s3" {
+		t.Errorf("Wrong repository type %q", repositories["backups"].Type)
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		s.Collect(ch)
+		close(ch)
+	}()
+	expected := map[string]float64{
+		"max_snapshot_rate_bytes_per_sec": 20 * 1024 * 1024,
+		"max_restore_rate_bytes_per_sec":  1024 * 1024 * 1024,
+		"compress":                        1,
 	}
+	found := map[string]bool{}
+	for metric := range ch {
+		for name, value := range expected {
+			if !strings.Contains(metric.Desc().String(), "\"elasticsearch_snapshot_repository_"+name+"\"") {
+				continue
+			}
+			var m dto.Metric
+			if err := metric.Write(&m); err != nil {
+				t.Fatalf("Failed to write metric: %s", err)
+			}
+			if found[name] {
+				t.Errorf("Setting %s should only be reported for the repository configuring it", name)
+			}
+			found[name] = true
+			if v := m.GetGauge().GetValue(); v != value {
+				t.Errorf("Wrong value for %s: got %v, expected %v", name, v, value)
+			}
+		}
+	}
+	for name := range expected {
+		if !found[name] {
+			t.Errorf("Missing metric %s", name)
+		}
+	}
+
+	for value, bytes := range map[string]float64{"40mb": 40 << 20, "512b": 512, "2G": 2 << 30, "0b": 0} {
+		if v, err := parseESByteSize(value); err != nil || v != bytes {
+			t.Errorf("Wrong byte size for %q: got %v (%v), expected %v", value, v, err, bytes)
+		}
+	}
+	if _, err := parseESByteSize("fast"); err == nil {
+		t.Errorf("Expected an error for an invalid byte size")
+	}
+}
+
+func TestSnapshotsHistoryDepth(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" -e "path.repo=/tmp" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/_snapshot/test1 -H 'Content-Type: application/json' -d '{"type":"fs","settings":{"location":"/tmp/test1"}}'
+	//  curl -XPUT "http://localhost:9200/_snapshot/test1/snapshot_1?wait_for_completion=true" (repeated for snapshot_2 and snapshot_3)
+	//  curl 'http://localhost:9200/_snapshot/test1/_all?size=2&sort=start_time&order=desc'
+	//  curl 'http://localhost:9200/_snapshot/test1/_all?size=1&sort=start_time&order=asc'
+	out := []string{
+		`{"test1":{"type":"fs","settings":{"location":"/tmp/test1"}}}`,
+		`{"snapshots":[{"snapshot":"snapshot_3","uuid":"iO1Hm2xfQp6QkW3Te4Jo9Q","repository":"test1","version_id":7140099,"version":"7.14.0","indices":["foo_1"],"data_streams":[],"include_global_state":true,"state":"SUCCESS","start_time_in_millis":1585908000000,"end_time_in_millis":1585908060000,"duration_in_millis":60000,"failures":[],"shards":{"total":1,"failed":0,"successful":1},"feature_states":[]},{"snapshot":"snapshot_2","uuid":"tc8oVxE4Tk2WAqCHvnk0yA","repository":"test1","version_id":7140099,"version":"7.14.0","indices":["foo_1"],"data_streams":[],"include_global_state":true,"state":"SUCCESS","start_time_in_millis":1585821600000,"end_time_in_millis":1585821660000,"duration_in_millis":60000,"failures":[],"shards":{"total":1,"failed":0,"successful":1},"feature_states":[]}],"next":"c25hcHNob3RfMix0ZXN0MSwxNTg1ODIxNjAwMDAw","total":3,"remaining":1}`,
+		`{"snapshots":[{"snapshot":"snapshot_1","uuid":"5gJc2JbYTLWl8LuUu8mK2g","repository":"test1","version_id":7140099,"version":"7.14.0","indices":["foo_1"],"data_streams":[],"include_global_state":true,"state":"SUCCESS","start_time_in_millis":1585735200000,"end_time_in_millis":1585735260000,"duration_in_millis":60000,"failures":[],"shards":{"total":1,"failed":0,"successful":1},"feature_states":[]}],"next":"c25hcHNob3RfMSx0ZXN0MSwxNTg1NzM1MjAwMDAw","total":3,"remaining":2}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/_snapshot" {
+			fmt.Fprint(w, out[0])
+			return
+		}
+		if r.URL.Path == "/_snapshot/test1/_all" {
+			switch r.URL.RawQuery {
+			case "size=2&sort=start_time&order=desc":
+				fmt.Fprint(w, out[1])
+			case "size=1&sort=start_time&order=asc":
+				fmt.Fprint(w, out[2])
+			default:
+				t.Errorf("Wrong snapshots query: %s", r.URL.RawQuery)
+			}
+			return
+		}
+		http.Error(w, "", http.StatusNotFound)
+	}))
+	defer ts.Close()
 
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %s", err)
+	}
+	s := NewSnapshots(log.NewNopLogger(), http.DefaultClient, u, 5, 2, 0, nil, 0)
+	stats, _, err := s.fetchAndDecodeSnapshotsStats()
+	if err != nil {
+		t.Fatalf("Failed to fetch or decode snapshots stats: %s", err)
+	}
+	repositoryStats := stats["test1"]
+	if len(repositoryStats.Snapshots) != 2 {
+		t.Fatalf("Wrong number of loaded snapshots")
+	}
+	if repositoryStats.Snapshots[0].Snapshot != "snapshot_2" || repositoryStats.Snapshots[1].Snapshot != "snapshot_3" {
+		t.Errorf("Snapshots are not sorted by start time: %s, %s", repositoryStats.Snapshots[0].Snapshot, repositoryStats.Snapshots[1].Snapshot)
+	}
+
+	expected := map[string]float64{
+		"number_of_snapshots":       3,
+		"oldest_snapshot_timestamp": 1585735200,
+	}
+	for _, metric := range s.repositoryMetrics {
+		for name, want := range expected {
+			if strings.Contains(metric.Desc.String(), `"elasticsearch_snapshot_stats_`+name+`"`) {
+				if v := metric.Value(repositoryStats); v != want {
+					t.Errorf("Wrong value for %s: got %v, expected %v", name, v, want)
+				}
+			}
+		}
+	}
+}
+
+func TestParseRepositoryCapacities(t *testing.T) {
+	capacities, err := ParseRepositoryCapacities("backups=2tb, archive = 512gb,")
+	if err != nil {
+		t.Fatalf("Failed to parse repository capacities: %s", err)
+	}
+	if len(capacities) != 2 || capacities["backups"] != 2<<40 || capacities["archive"] != 512<<30 {
+		t.Errorf("Wrong repository capacities: %v", capacities)
+	}
+	for _, invalid := range []string{"backups", "=2tb", "backups=2", "backups=lots"} {
+		if _, err := ParseRepositoryCapacities(invalid); err == nil {
+			t.Errorf("Invalid repository capacity %q was accepted", invalid)
+		}
+	}
 }
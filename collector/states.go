@@ -0,0 +1,10 @@
+package collector
+
+// stateValue returns the value of a state reported by Elasticsearch. States missing from the
+// mapping, e.g. introduced by a newer version, return unknown instead of looking like a known state.
+func stateValue(states map[string]float64, state string, unknown float64) float64 {
+	if value, ok := states[state]; ok {
+		return value
+	}
+	return unknown
+}
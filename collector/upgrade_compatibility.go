@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	deprecationCategories = []string{"cluster_settings", "node_settings", "index", "ml_settings", "templates", "ilm_policies", "data_streams"}
+)
+
+// UpgradeCompatibility information struct
+type UpgradeCompatibility struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	// deprecations holds the metric of every deprecation level
+	deprecations map[string]*prometheus.Desc
+}
+
+// NewUpgradeCompatibility defines UpgradeCompatibility Prometheus metrics
+func NewUpgradeCompatibility(logger log.Logger, client *http.Client, url *url.URL) *UpgradeCompatibility {
+	constLabels := constLabelsFromURL(url)
+	newDeprecationsDesc := func(deprecationLevel, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "deprecations", deprecationLevel+"_total"),
+			help,
+			[]string{"category"}, constLabels,
+		)
+	}
+	return &UpgradeCompatibility{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "upgrade_compatibility", "up"),
+			Help:        "Was the last scrape of the ElasticSearch deprecation info endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "upgrade_compatibility", "total_scrapes"),
+			Help:        "Current total ElasticSearch upgrade compatibility scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "upgrade_compatibility", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		deprecations: map[string]*prometheus.Desc{
+			"critical": newDeprecationsDesc("critical", "Number of critical deprecations, which block the upgrade to the next major version"),
+			"warning":  newDeprecationsDesc("warning", "Number of deprecation warnings, which should be resolved before the upgrade to the next major version"),
+			"info":     newDeprecationsDesc("info", "Number of informational deprecations"),
+		},
+	}
+}
+
+// Describe add UpgradeCompatibility metrics descriptions
+func (u *UpgradeCompatibility) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range u.deprecations {
+		ch <- desc
+	}
+	ch <- u.up.Desc()
+	ch <- u.totalScrapes.Desc()
+	ch <- u.jsonParseFailures.Desc()
+}
+
+func (u *UpgradeCompatibility) fetchAndDecodeDeprecations() (DeprecationsResponse, error) {
+	var dr DeprecationsResponse
+
+	du := *u.url
+	du.Path = path.Join(du.Path, "/_migration/deprecations")
+	err := getAndDecodeURL(u.logger, u.client, &du, &dr, u.jsonParseFailures)
+	return dr, err
+}
+
+// Collect gets UpgradeCompatibility metric values
+func (u *UpgradeCompatibility) Collect(ch chan<- prometheus.Metric) {
+	u.totalScrapes.Inc()
+	defer func() {
+		ch <- u.up
+		ch <- u.totalScrapes
+		ch <- u.jsonParseFailures
+	}()
+
+	deprecationsResp, err := u.fetchAndDecodeDeprecations()
+	if err != nil {
+		u.up.Set(0)
+		_ = level.Warn(u.logger).Log(
+			"msg", "failed to fetch and decode deprecations",
+			"err", err,
+		)
+		return
+	}
+	u.up.Set(1)
+
+	// every category is exported for every level, so pipelines can check for zero critical deprecations
+	counts := deprecationsResp.countByCategoryAndLevel()
+	for deprecationLevel, desc := range u.deprecations {
+		for _, category := range deprecationCategories {
+			ch <- prometheus.MustNewConstMetric(
+				desc,
+				prometheus.GaugeValue,
+				float64(counts[deprecationKey{category, deprecationLevel}]),
+				category,
+			)
+		}
+	}
+}
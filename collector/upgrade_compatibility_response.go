@@ -0,0 +1,52 @@
+package collector
+
+// DeprecationsResponse is a representation of the deprecation info API
+type DeprecationsResponse struct {
+	ClusterSettings []DeprecationResponse `json:"cluster_settings"`
+	NodeSettings    []DeprecationResponse `json:"node_settings"`
+	// IndexSettings holds the deprecations of every index with deprecated settings or mappings
+	IndexSettings map[string][]DeprecationResponse `json:"index_settings"`
+	MLSettings    []DeprecationResponse            `json:"ml_settings"`
+	// Templates, ILMPolicies and DataStreams hold the deprecations by template, policy and
+	// data stream name, reported since 7.16 (templates and ILM policies) and 8.0 (data streams)
+	Templates   map[string][]DeprecationResponse `json:"templates"`
+	ILMPolicies map[string][]DeprecationResponse `json:"ilm_policies"`
+	DataStreams map[string][]DeprecationResponse `json:"data_streams"`
+}
+
+// DeprecationResponse is a representation of a single deprecation
+type DeprecationResponse struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Details string `json:"details"`
+}
+
+// deprecationKey identifies the category and level deprecations are counted by
+type deprecationKey struct {
+	category, level string
+}
+
+// countByCategoryAndLevel counts the deprecations by category, named after the fields of the
+// response except for index, which covers mappings as well as settings, and level
+func (d DeprecationsResponse) countByCategoryAndLevel() map[deprecationKey]int {
+	counts := make(map[deprecationKey]int)
+	count := func(category string, deprecations []DeprecationResponse) {
+		for _, deprecation := range deprecations {
+			counts[deprecationKey{category, deprecation.Level}]++
+		}
+	}
+	countByName := func(category string, deprecationsByName map[string][]DeprecationResponse) {
+		for _, deprecations := range deprecationsByName {
+			count(category, deprecations)
+		}
+	}
+	count("cluster_settings", d.ClusterSettings)
+	count("node_settings", d.NodeSettings)
+	countByName("index", d.IndexSettings)
+	count("ml_settings", d.MLSettings)
+	countByName("templates", d.Templates)
+	countByName("ilm_policies", d.ILMPolicies)
+	countByName("data_streams", d.DataStreams)
+	return counts
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestUpgradeCompatibility(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPUT http://localhost:9200/twitter (restored from a 6.x snapshot)
+	//  curl -XPUT http://localhost:9200/_ilm/policy/logs -H 'Content-Type: application/json' -d '{"policy":{"phases":{"frozen":{"actions":{"freeze":{}}}}}}' (8.x)
+	//  curl http://localhost:9200/_migration/deprecations
+	tcs := map[string]string{
+		"7.17.0": `{"cluster_settings":[{"level":"warning","message":"Realm order will be required in next major release.","url":"https://ela.st/es-deprecation-7-realm-orders-required","details":"Specify the realm order for all realms [file1].","resolve_during_rolling_upgrade":false}],"node_settings":[{"level":"critical","message":"Setting [node.data] is deprecated","url":"https://ela.st/es-deprecation-7-node-roles","details":"Remove the [node.data] setting and set [node.roles] instead.","resolve_during_rolling_upgrade":false},{"level":"info","message":"Setting [http.content_type.required] is deprecated","url":"https://ela.st/es-deprecation-7-http-content-type-required","details":"Remove the [http.content_type.required] setting.","resolve_during_rolling_upgrade":false}],"index_settings":{"twitter":[{"level":"critical","message":"Index created before 7.0","url":"https://ela.st/es-deprecation-7-reindex","details":"This index was created with version 6.8.13 and is not compatible with 8.0. Reindex or remove the index before upgrading.","resolve_during_rolling_upgrade":false}],"logs":[{"level":"critical","message":"Index created before 7.0","url":"https://ela.st/es-deprecation-7-reindex","details":"This index was created with version 6.8.13 and is not compatible with 8.0. Reindex or remove the index before upgrading.","resolve_during_rolling_upgrade":false},{"level":"warning","message":"translog retention settings are ignored","url":"https://ela.st/es-deprecation-7-translog-retention","details":"translog retention settings [index.translog.retention.size] and [index.translog.retention.age] are ignored because translog is no longer used in peer recoveries with soft-deletes enabled (default in 7.0 or later)","resolve_during_rolling_upgrade":false}]},"ml_settings":[]}`,
+		"8.18.0": `{"cluster_settings":[],"node_settings":[],"index_settings":{"logs-old":[{"level":"critical","message":"Old index with a compatibility version < 7.0","url":"https://www.elastic.co/guide/en/elasticsearch/reference/master/migrating-8.0.html#breaking-changes-8.0","details":"This index has version: 6.8.23","resolve_during_rolling_upgrade":false}]},"data_streams":{"logs-app":[{"level":"critical","message":"Old data stream with a compatibility version < 8.0","url":"https://www.elastic.co/guide/en/elasticsearch/reference/master/breaking-changes-9.0.html","details":"This data stream has backing indices that were created before Elasticsearch 8.0.0","resolve_during_rolling_upgrade":false}]},"templates":{"legacy-logs":[{"level":"warning","message":"Configuring source mode in mappings is deprecated.","url":"https://ela.st/migrate-source-mode","details":"Configuring source mode in mappings is deprecated and will be removed in future versions. Use [index.mapping.source.mode] index setting instead.","resolve_during_rolling_upgrade":false}]},"ilm_policies":{"logs":[{"level":"warning","message":"ILM policy [logs] contains the action 'freeze' that is deprecated and will be removed in a future version.","url":"https://ela.st/es-deprecation-7-frozen-index","details":"This action is already a noop so it can be safely removed, because frozen indices no longer offer any advantages. Consider cold or frozen tiers in place of frozen indices.","resolve_during_rolling_upgrade":false}]},"ml_settings":[]}`,
+	}
+	expectedCounts := map[string]map[deprecationKey]int{
+		"7.17.0": {
+			{"cluster_settings", "warning"}: 1,
+			{"node_settings", "critical"}:   1,
+			{"node_settings", "info"}:       1,
+			{"index", "critical"}:           2,
+			{"index", "warning"}:            1,
+		},
+		"8.18.0": {
+			{"index", "critical"}:        1,
+			{"data_streams", "critical"}: 1,
+			{"templates", "warning"}:     1,
+			{"ilm_policies", "warning"}:  1,
+		},
+	}
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		c := NewUpgradeCompatibility(log.NewNopLogger(), http.DefaultClient, u)
+		dr, err := c.fetchAndDecodeDeprecations()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode deprecations: %s", err)
+		}
+		t.Logf("[%s] Deprecations Response: %+v", ver, dr)
+
+		counts := dr.countByCategoryAndLevel()
+		expected := expectedCounts[ver]
+		if len(counts) != len(expected) {
+			t.Fatalf("[%s] Wrong deprecation counts: %v", ver, counts)
+		}
+		for key, want := range expected {
+			if counts[key] != want {
+				t.Errorf("[%s] Wrong number of %s %s deprecations: %d", ver, key.level, key.category, counts[key])
+			}
+		}
+	}
+}
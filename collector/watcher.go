@@ -0,0 +1,170 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	watcherTimeBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60}
+)
+
+// watcherTimeHistogram holds the data of a constant histogram
+type watcherTimeHistogram struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+// Watcher information struct
+type Watcher struct {
+	logger log.Logger
+	client *http.Client
+	url    *url.URL
+
+	up                              prometheus.Gauge
+	totalScrapes, jsonParseFailures prometheus.Counter
+
+	executionTime *prometheus.Desc
+	queuedTime    *prometheus.Desc
+}
+
+// NewWatcher defines Watcher Prometheus metrics
+func NewWatcher(logger log.Logger, client *http.Client, url *url.URL) *Watcher {
+	constLabels := constLabelsFromURL(url)
+	return &Watcher{
+		logger: logger,
+		client: client,
+		url:    url,
+
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        prometheus.BuildFQName(namespace, "watcher", "up"),
+			Help:        "Was the last scrape of the ElasticSearch Watcher stats endpoint successful.",
+			ConstLabels: constLabels,
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "watcher", "total_scrapes"),
+			Help:        "Current total ElasticSearch Watcher stats scrapes.",
+			ConstLabels: constLabels,
+		}),
+		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        prometheus.BuildFQName(namespace, "watcher", "json_parse_failures"),
+			Help:        "Number of errors while parsing JSON.",
+			ConstLabels: constLabels,
+		}),
+		executionTime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "watcher", "execution_time_seconds"),
+			"Time the currently executing watches have been executing in seconds",
+			[]string{"watch_id"}, constLabels,
+		),
+		queuedTime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "watcher", "queued_time_seconds"),
+			"Time the queued watches have been waiting since they were triggered in seconds",
+			[]string{"watch_id"}, constLabels,
+		),
+	}
+}
+
+// Describe add Watcher metrics descriptions
+func (w *Watcher) Describe(ch chan<- *prometheus.Desc) {
+	ch <- w.executionTime
+	ch <- w.queuedTime
+	ch <- w.up.Desc()
+	ch <- w.totalScrapes.Desc()
+	ch <- w.jsonParseFailures.Desc()
+}
+
+func (w *Watcher) fetchAndDecodeWatcherStats() (WatcherStatsResponse, error) {
+	var wsr WatcherStatsResponse
+
+	u := *w.url
+	u.Path = path.Join(u.Path, "/_watcher/stats")
+	u.RawQuery = "metric=current_watches,queued_watches"
+	err := getAndDecodeURL(w.logger, w.client, &u, &wsr, w.jsonParseFailures)
+	return wsr, err
+}
+
+// watcherTimeHistograms aggregates the time since start of the given watches across all nodes by watch id,
+// a start ahead of now counts as 0
+func watcherTimeHistograms(watches []WatcherStatsWatchResponse, start func(watch WatcherStatsWatchResponse) time.Time, now time.Time) map[string]*watcherTimeHistogram {
+	histograms := make(map[string]*watcherTimeHistogram)
+	for _, watch := range watches {
+		histogram, ok := histograms[watch.WatchID]
+		if !ok {
+			histogram = &watcherTimeHistogram{buckets: make(map[float64]uint64, len(watcherTimeBuckets))}
+			for _, bucket := range watcherTimeBuckets {
+				histogram.buckets[bucket] = 0
+			}
+			histograms[watch.WatchID] = histogram
+		}
+
+		// the start is taken from the clock of the node, which can be ahead of the clock of the exporter
+		seconds := now.Sub(start(watch)).Seconds()
+		if seconds < 0 {
+			seconds = 0
+		}
+		histogram.count++
+		histogram.sum += seconds
+		for _, bucket := range watcherTimeBuckets {
+			if seconds <= bucket {
+				histogram.buckets[bucket]++
+			}
+		}
+	}
+	return histograms
+}
+
+// Collect gets Watcher metric values
+func (w *Watcher) Collect(ch chan<- prometheus.Metric) {
+	w.totalScrapes.Inc()
+	defer func() {
+		ch <- w.up
+		ch <- w.totalScrapes
+		ch <- w.jsonParseFailures
+	}()
+
+	watcherStatsResp, err := w.fetchAndDecodeWatcherStats()
+	if err != nil {
+		w.up.Set(0)
+		_ = level.Warn(w.logger).Log(
+			"msg", "failed to fetch and decode Watcher stats",
+			"err", err,
+		)
+		return
+	}
+	w.up.Set(1)
+
+	var current, queued []WatcherStatsWatchResponse
+	for _, node := range watcherStatsResp.Stats {
+		current = append(current, node.CurrentWatches...)
+		queued = append(queued, node.QueuedWatches...)
+	}
+
+	now := time.Now()
+	executionStart := func(watch WatcherStatsWatchResponse) time.Time { return watch.ExecutionTime }
+	for watchID, histogram := range watcherTimeHistograms(current, executionStart, now) {
+		ch <- prometheus.MustNewConstHistogram(
+			w.executionTime,
+			histogram.count,
+			histogram.sum,
+			histogram.buckets,
+			watchID,
+		)
+	}
+	triggered := func(watch WatcherStatsWatchResponse) time.Time { return watch.TriggeredTime }
+	for watchID, histogram := range watcherTimeHistograms(queued, triggered, now) {
+		ch <- prometheus.MustNewConstHistogram(
+			w.queuedTime,
+			histogram.count,
+			histogram.sum,
+			histogram.buckets,
+			watchID,
+		)
+	}
+}
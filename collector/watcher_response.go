@@ -0,0 +1,28 @@
+package collector
+
+import "time"
+
+// WatcherStatsResponse is a representation of the Watcher stats of all nodes
+type WatcherStatsResponse struct {
+	ClusterName     string                     `json:"cluster_name"`
+	ManuallyStopped bool                       `json:"manually_stopped"`
+	Stats           []WatcherStatsNodeResponse `json:"stats"`
+}
+
+// WatcherStatsNodeResponse is a representation of the Watcher stats of a single node
+type WatcherStatsNodeResponse struct {
+	NodeID         string                      `json:"node_id"`
+	WatcherState   string                      `json:"watcher_state"`
+	WatchCount     int64                       `json:"watch_count"`
+	CurrentWatches []WatcherStatsWatchResponse `json:"current_watches"`
+	QueuedWatches  []WatcherStatsWatchResponse `json:"queued_watches"`
+}
+
+// WatcherStatsWatchResponse is a representation of a watch executing or queued on a node
+type WatcherStatsWatchResponse struct {
+	WatchID        string    `json:"watch_id"`
+	WatchRecordID  string    `json:"watch_record_id"`
+	TriggeredTime  time.Time `json:"triggered_time"`
+	ExecutionTime  time.Time `json:"execution_time"`
+	ExecutionPhase string    `json:"execution_phase"`
+}
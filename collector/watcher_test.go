@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestWatcherStats(t *testing.T) {
+	// Testcases created using:
+	//  docker run -d -p 9200:9200 -e "discovery.type=single-node" elasticsearch:VERSION
+	//  curl -XPOST http://localhost:9200/_license/start_trial?acknowledge=true
+	//  curl -XPUT http://localhost:9200/_watcher/watch/slow_condition -H 'Content-Type: application/json' -d '{"trigger":{"schedule":{"interval":"1s"}},"condition":{"script":"Thread.sleep(20000); return true"}}'
+	//  curl 'http://localhost:9200/_watcher/stats?metric=current_watches,queued_watches'
+	tcs := map[string]string{
+		"7.6.2": `{"_nodes":{"total":2,"successful":2,"failed":0},"cluster_name":"elasticsearch","manually_stopped":false,"stats":[{"node_id":"9_P7yui4SQOkzGhTZCyjxQ","watcher_state":"started","watch_count":2,"execution_thread_pool":{"queue_size":1,"max_size":1},"current_watches":[{"watch_id":"slow_condition","watch_record_id":"slow_condition_3-2020-04-01T10:00:00.000Z","triggered_time":"2020-04-01T09:59:59.500Z","execution_time":"2020-04-01T10:00:00.000Z","execution_phase":"condition"}],"queued_watches":[{"watch_id":"slow_condition","watch_record_id":"slow_condition_4-2020-04-01T10:00:01.000Z","triggered_time":"2020-04-01T10:00:01.000Z","execution_time":"2020-04-01T10:00:01.000Z"}]},{"node_id":"yYpjx5JnT4yeRAzkb6M5cg","watcher_state":"started","watch_count":1,"execution_thread_pool":{"queue_size":0,"max_size":1},"current_watches":[{"watch_id":"slow_condition","watch_record_id":"slow_condition_2-2020-04-01T09:59:58.000Z","triggered_time":"2020-04-01T09:59:58.000Z","execution_time":"2020-04-01T09:59:58.000Z","execution_phase":"condition"},{"watch_id":"cluster_health","watch_record_id":"cluster_health_7-2020-04-01T10:00:19.950Z","triggered_time":"2020-04-01T10:00:19.950Z","execution_time":"2020-04-01T10:00:19.950Z","execution_phase":"actions"}],"queued_watches":[]}]}`,
+	}
+	now := time.Date(2020, 4, 1, 10, 0, 20, 0, time.UTC)
+	for ver, out := range tcs {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, out)
+		}))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL: %s", err)
+		}
+		w := NewWatcher(log.NewNopLogger(), http.DefaultClient, u)
+		wsr, err := w.fetchAndDecodeWatcherStats()
+		if err != nil {
+			t.Fatalf("Failed to fetch or decode Watcher stats: %s", err)
+		}
+		t.Logf("[%s] Watcher Stats Response: %+v", ver, wsr)
+		if len(wsr.Stats) != 2 {
+			t.Fatalf("Wrong number of nodes")
+		}
+
+		current := append(wsr.Stats[0].CurrentWatches, wsr.Stats[1].CurrentWatches...)
+		histograms := watcherTimeHistograms(current, func(watch WatcherStatsWatchResponse) time.Time { return watch.ExecutionTime }, now)
+		if len(histograms) != 2 {
+			t.Fatalf("Wrong number of executing watches")
+		}
+		slow := histograms["slow_condition"]
+		if slow.count != 2 || slow.sum != 42 {
+			t.Errorf("Wrong execution time of slow_condition: count %d, sum %v", slow.count, slow.sum)
+		}
+		if slow.buckets[10] != 0 || slow.buckets[30] != 2 {
+			t.Errorf("Wrong execution time buckets of slow_condition: %v", slow.buckets)
+		}
+		if fast := histograms["cluster_health"]; fast.buckets[0.1] != 1 {
+			t.Errorf("Wrong execution time buckets of cluster_health: %v", fast.buckets)
+		}
+
+		queued := watcherTimeHistograms(wsr.Stats[0].QueuedWatches, func(watch WatcherStatsWatchResponse) time.Time { return watch.TriggeredTime }, now)
+		if q := queued["slow_condition"]; q == nil || q.count != 1 || q.sum != 19 || q.buckets[5] != 0 || q.buckets[30] != 1 {
+			t.Errorf("Wrong queued time of slow_condition: %+v", q)
+		}
+
+		// a node clock ahead of the exporter must not produce negative times
+		skewed := watcherTimeHistograms(wsr.Stats[0].QueuedWatches, func(watch WatcherStatsWatchResponse) time.Time { return watch.TriggeredTime }, now.Add(-time.Minute))
+		if q := skewed["slow_condition"]; q == nil || q.sum != 0 || q.buckets[0.1] != 1 {
+			t.Errorf("Wrong queued time of slow_condition with clock skew: %+v", q)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// dangerousHeaders are headers set by the HTTP client itself, overriding them likely breaks requests
+var dangerousHeaders = map[string]bool{
+	"Content-Type":      true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+}
+
+// parseExtraHeaders parses key=value pairs into HTTP headers. Values may contain commas and
+// equal signs, a header is added once per pair. It returns the names of the headers which
+// are known to be dangerous to override.
+func parseExtraHeaders(pairs []string) (http.Header, []string, error) {
+	headers := http.Header{}
+	var dangerous []string
+	for _, pair := range pairs {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, nil, fmt.Errorf("invalid header %q, expected key=value", pair)
+		}
+		key := strings.TrimSpace(kv[0])
+		if key == "" {
+			return nil, nil, fmt.Errorf("empty header name in %q", pair)
+		}
+		key = http.CanonicalHeaderKey(key)
+		if dangerousHeaders[key] {
+			dangerous = append(dangerous, key)
+		}
+		headers.Add(key, strings.TrimSpace(kv[1]))
+	}
+	return headers, dangerous, nil
+}
+
+// headerRoundTripper adds headers to every request before handing it to the next RoundTripper
+type headerRoundTripper struct {
+	headers http.Header
+	next    http.RoundTripper
+}
+
+func newHeaderRoundTripper(headers http.Header, next http.RoundTripper) http.RoundTripper {
+	return &headerRoundTripper{
+		headers: headers,
+		next:    next,
+	}
+}
+
+func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request, so work on a shallow copy with its own headers
+	r2 := new(http.Request)
+	*r2 = *req
+	r2.Header = make(http.Header, len(req.Header))
+	for key, values := range req.Header {
+		r2.Header[key] = append([]string(nil), values...)
+	}
+	for key, values := range rt.headers {
+		// the client sends req.Host and ignores a Host header
+		if key == "Host" {
+			r2.Host = values[len(values)-1]
+			continue
+		}
+		r2.Header.Del(key)
+		for _, value := range values {
+			r2.Header.Add(key, value)
+		}
+	}
+	return rt.next.RoundTrip(r2)
+}
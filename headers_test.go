@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseExtraHeaders(t *testing.T) {
+	tcs := map[string]struct {
+		pairs     []string
+		headers   http.Header
+		dangerous int
+		ok        bool
+	}{
+		"empty":          {nil, http.Header{}, 0, true},
+		"single":         {[]string{"x-api-key=secret"}, http.Header{"X-Api-Key": {"secret"}}, 0, true},
+		"comma in value": {[]string{"Accept=application/json, text/plain"}, http.Header{"Accept": {"application/json, text/plain"}}, 0, true},
+		"equal in value": {[]string{"Authorization=Bearer abc=="}, http.Header{"Authorization": {"Bearer abc=="}}, 0, true},
+		"repeated":       {[]string{"X-Tag=a", "x-tag=b"}, http.Header{"X-Tag": {"a", "b"}}, 0, true},
+		"dangerous":      {[]string{"Content-Type=text/plain"}, http.Header{"Content-Type": {"text/plain"}}, 1, true},
+		"missing value":  {[]string{"X-Api-Key"}, nil, 0, false},
+		"missing name":   {[]string{"=secret"}, nil, 0, false},
+	}
+	for name, tc := range tcs {
+		headers, dangerous, err := parseExtraHeaders(tc.pairs)
+		if (err == nil) != tc.ok {
+			t.Errorf("[%s] Unexpected error: %v", name, err)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		if fmt.Sprint(headers) != fmt.Sprint(tc.headers) {
+			t.Errorf("[%s] Wrong headers: got %v, expected %v", name, headers, tc.headers)
+		}
+		if len(dangerous) != tc.dangerous {
+			t.Errorf("[%s] Wrong dangerous headers: %v", name, dangerous)
+		}
+	}
+}
+
+func TestHeaderRoundTripper(t *testing.T) {
+	var got *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer ts.Close()
+
+	headers, _, err := parseExtraHeaders([]string{"X-Api-Key=secret", "Host=es.example.com"})
+	if err != nil {
+		t.Fatalf("Failed to parse headers: %s", err)
+	}
+	client := &http.Client{Transport: newHeaderRoundTripper(headers, http.DefaultTransport)}
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %s", err)
+	}
+	req.Header.Set("X-Api-Key", "overridden")
+	host := req.Host
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %s", err)
+	}
+	res.Body.Close()
+
+	if v := got.Header.Get("X-Api-Key"); v != "secret" {
+		t.Errorf("Wrong X-Api-Key header: %q", v)
+	}
+	if got.Host != "es.example.com" {
+		t.Errorf("Wrong host: %q", got.Host)
+	}
+	if req.Header.Get("X-Api-Key") != "overridden" || req.Host != host {
+		t.Errorf("The original request was modified: %v", req)
+	}
+}
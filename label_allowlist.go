@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// parseLabelAllowlist parses a comma separated list of label=regex pairs. The regular
+// expressions are anchored like in Prometheus relabeling and must not contain commas.
+func parseLabelAllowlist(s string) (map[string]*regexp.Regexp, error) {
+	allowlist := map[string]*regexp.Regexp{}
+	if strings.TrimSpace(s) == "" {
+		return allowlist, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid label allowlist entry %q, expected label=regex", pair)
+		}
+		label := strings.TrimSpace(kv[0])
+		if label == "" {
+			return nil, fmt.Errorf("empty label name in %q", pair)
+		}
+		re, err := regexp.Compile("^(?:" + strings.TrimSpace(kv[1]) + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for label %q: %s", label, err)
+		}
+		allowlist[label] = re
+	}
+	return allowlist, nil
+}
+
+// labelAllowlistCollector drops the metrics of the wrapped collector which have a label
+// of the allowlist with a value not matching its regex. Metrics without the label are kept.
+type labelAllowlistCollector struct {
+	allowlist map[string]*regexp.Regexp
+	next      prometheus.Collector
+}
+
+// newLabelAllowlistCollector wraps the collector, it is returned as is for an empty allowlist
+func newLabelAllowlistCollector(allowlist map[string]*regexp.Regexp, next prometheus.Collector) prometheus.Collector {
+	if len(allowlist) == 0 {
+		return next
+	}
+	return &labelAllowlistCollector{
+		allowlist: allowlist,
+		next:      next,
+	}
+}
+
+func (c *labelAllowlistCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.next.Describe(ch)
+}
+
+func (c *labelAllowlistCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		c.next.Collect(metrics)
+		close(metrics)
+	}()
+	for metric := range metrics {
+		if c.allowed(metric) {
+			ch <- metric
+		}
+	}
+}
+
+func (c *labelAllowlistCollector) allowed(metric prometheus.Metric) bool {
+	var m dto.Metric
+	if err := metric.Write(&m); err != nil {
+		// let the registry report the broken metric
+		return true
+	}
+	for _, label := range m.GetLabel() {
+		if re, ok := c.allowlist[label.GetName()]; ok && !re.MatchString(label.GetValue()) {
+			return false
+		}
+	}
+	return true
+}
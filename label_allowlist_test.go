@@ -0,0 +1,81 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type constCollector []prometheus.Metric
+
+func (c constCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c {
+		ch <- metric.Desc()
+	}
+}
+
+func (c constCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, metric := range c {
+		ch <- metric
+	}
+}
+
+func TestParseLabelAllowlist(t *testing.T) {
+	tcs := map[string]struct {
+		allowlist string
+		labels    int
+		ok        bool
+	}{
+		"empty":         {"", 0, true},
+		"single":        {"index=logs-.*", 1, true},
+		"multiple":      {"index=logs-.*, pipeline = (geoip|useragent)", 2, true},
+		"missing regex": {"index", 0, false},
+		"missing label": {"=logs-.*", 0, false},
+		"invalid regex": {"index=logs-(", 0, false},
+	}
+	for name, tc := range tcs {
+		allowlist, err := parseLabelAllowlist(tc.allowlist)
+		if (err == nil) != tc.ok {
+			t.Errorf("[%s] Unexpected error: %v", name, err)
+			continue
+		}
+		if len(allowlist) != tc.labels {
+			t.Errorf("[%s] Wrong number of labels: %d", name, len(allowlist))
+		}
+	}
+}
+
+func TestLabelAllowlistCollector(t *testing.T) {
+	allowlist, err := parseLabelAllowlist("index=logs-.*,pipeline=geoip")
+	if err != nil {
+		t.Fatalf("Failed to parse label allowlist: %s", err)
+	}
+	indexDocs := prometheus.NewDesc("elasticsearch_indices_docs", "", []string{"index"}, nil)
+	pipelineDocs := prometheus.NewDesc("elasticsearch_ingest_pipeline_docs_total", "", []string{"pipeline"}, nil)
+	nodeDocs := prometheus.NewDesc("elasticsearch_indices_docs_node", "", []string{"name"}, nil)
+	c := newLabelAllowlistCollector(allowlist, constCollector{
+		prometheus.MustNewConstMetric(indexDocs, prometheus.GaugeValue, 1, "logs-2020.04.01"),
+		prometheus.MustNewConstMetric(indexDocs, prometheus.GaugeValue, 1, "user-jane.doe@example.com"),
+		// the regex is anchored, a partial match is not allowed
+		prometheus.MustNewConstMetric(indexDocs, prometheus.GaugeValue, 1, "old-logs-2020.04.01"),
+		prometheus.MustNewConstMetric(pipelineDocs, prometheus.CounterValue, 1, "geoip"),
+		prometheus.MustNewConstMetric(pipelineDocs, prometheus.CounterValue, 1, "customer-4711"),
+		prometheus.MustNewConstMetric(nodeDocs, prometheus.GaugeValue, 1, "node-0"),
+	})
+
+	ch := make(chan prometheus.Metric, 10)
+	c.Collect(ch)
+	close(ch)
+	var kept int
+	for range ch {
+		kept++
+	}
+	if kept != 3 {
+		t.Errorf("Wrong number of kept metrics: %d", kept)
+	}
+
+	if c := newLabelAllowlistCollector(map[string]*regexp.Regexp{}, constCollector{}); c == nil {
+		t.Errorf("Empty allowlist should return the collector as is")
+	}
+}
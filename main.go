@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
 	"time"
 
@@ -39,9 +40,27 @@ func main() {
 		esNode = kingpin.Flag("es.node",
 			"Node's name of which metrics should be exposed.").
 			Default("_local").Envar("ES_NODE").String()
+		esNodesQuickStats = kingpin.Flag("es.nodes.quick_stats",
+			"Only fetch thread pool stats from the nodes stats API, reducing the payload for frequent checks.").
+			Default("false").Envar("ES_NODES_QUICK_STATS").Bool()
+		esNodesAttributes = kingpin.Flag("es.nodes.attributes",
+			"Comma separated list of node attributes, e.g. zone,rack, added as labels to the per node metrics.").
+			Default("").Envar("ES_NODES_ATTRIBUTES").String()
+		esNodesFielddataFields = kingpin.Flag("es.nodes.fielddata_fields",
+			"Comma separated list of fields, wildcards allowed, whose fielddata memory is exported per node and field.").
+			Default("").Envar("ES_NODES_FIELDDATA_FIELDS").String()
 		esExportIndices = kingpin.Flag("es.indices",
 			"Export stats for indices in the cluster.").
 			Default("false").Envar("ES_INDICES").Bool()
+		esIndicesAggregateOnly = kingpin.Flag("es.indices.aggregate_only",
+			"Only export the stats aggregated over all indices, without the per index breakdown (implies --es.indices).").
+			Default("false").Envar("ES_INDICES_AGGREGATE_ONLY").Bool()
+		esIndicesRefreshInterval = kingpin.Flag("es.indices.refresh_interval",
+			"Interval of the background refresh of the index stats served on scrapes, 0 queries the cluster on every scrape.").
+			Default("0s").Envar("ES_INDICES_REFRESH_INTERVAL").Duration()
+		esIndicesVerboseSegments = kingpin.Flag("es.indices.verbose_segments",
+			"Fetch the segments of every shard to export the size of the largest segment of each index.").
+			Default("false").Envar("ES_INDICES_VERBOSE_SEGMENTS").Bool()
 		esExportIndicesSettings = kingpin.Flag("es.indices_settings",
 			"Export stats for settings of all indices of the cluster.").
 			Default("false").Envar("ES_INDICES_SETTINGS").Bool()
@@ -54,6 +73,126 @@ func main() {
 		esExportSnapshots = kingpin.Flag("es.snapshots",
 			"Export stats for the cluster snapshots.").
 			Default("false").Envar("ES_SNAPSHOTS").Bool()
+		esSnapshotsRecentCount = kingpin.Flag("es.snapshots.recent_count",
+			"Number of most recent snapshots used to compute the average snapshot size, at least 1.").
+			Default("5").Envar("ES_SNAPSHOTS_RECENT_COUNT").Int()
+		esSnapshotsHistoryDepth = kingpin.Flag("es.snapshots.history_depth",
+			"Number of most recent snapshots loaded per repository, 0 loads all snapshots. Requires Elasticsearch 7.14 or later.").
+			Default("0").Envar("ES_SNAPSHOTS_HISTORY_DEPTH").Int()
+		esSnapshotsRefreshInterval = kingpin.Flag("es.snapshots.refresh_interval",
+			"Interval of the background refresh of the snapshot stats served on scrapes, 0 queries the cluster on every scrape.").
+			Default("0s").Envar("ES_SNAPSHOTS_REFRESH_INTERVAL").Duration()
+		esSnapshotsVerifyInterval = kingpin.Flag("es.snapshots.verify_interval",
+			"Minimum interval between verifications that all nodes can access a snapshot repository, 0 disables the verification.").
+			Default("5m").Envar("ES_SNAPSHOTS_VERIFY_INTERVAL").Duration()
+		esSnapshotsRepositoryCapacity = kingpin.Flag("es.snapshots.repository_capacity",
+			"Comma separated list of repository=size capacities, like backups=2tb, used to estimate the days until the repositories are full.").
+			Default("").Envar("ES_SNAPSHOTS_REPOSITORY_CAPACITY").String()
+		esSnapshotsCacheTTL = kingpin.Flag("es.snapshots.cache_ttl",
+			"Time the snapshot lists of the repositories are reused before listing them again, 0 lists them on every scrape.").
+			Default("0s").Envar("ES_SNAPSHOTS_CACHE_TTL").Duration()
+		esExportML = kingpin.Flag("es.ml",
+			"Export stats for ML datafeeds, trained models and data frame analytics jobs of the cluster.").
+			Default("false").Envar("ES_ML").Bool()
+		esExportRollupJobs = kingpin.Flag("es.rollup_jobs",
+			"Export stats for rollup jobs of the cluster.").
+			Default("false").Envar("ES_ROLLUP_JOBS").Bool()
+		esExportDataStream = kingpin.Flag("es.data_stream",
+			"Export stats for data streams of the cluster.").
+			Default("false").Envar("ES_DATA_STREAM").Bool()
+		esExportCCR = kingpin.Flag("es.ccr",
+			"Export stats for cross-cluster replication follower indices and auto-follow patterns of the cluster.").
+			Default("false").Envar("ES_CCR").Bool()
+		esExportCCS = kingpin.Flag("es.ccs",
+			"Export the cross-cluster searches and their latency per remote cluster.").
+			Default("false").Envar("ES_CCS").Bool()
+		esExportILM = kingpin.Flag("es.ilm",
+			"Export stats for index lifecycle management of the cluster.").
+			Default("false").Envar("ES_ILM").Bool()
+		esExportAllocationExplain = kingpin.Flag("es.allocation_explain",
+			"Export the allocation decisions explaining why shards are unassigned.").
+			Default("false").Envar("ES_ALLOCATION_EXPLAIN").Bool()
+		esAllocationExplainMaxShards = kingpin.Flag("es.allocation_explain.max_shards",
+			"Maximum number of unassigned shards explained per scrape.").
+			Default("5").Envar("ES_ALLOCATION_EXPLAIN_MAX_SHARDS").Int()
+		esExportCatShards = kingpin.Flag("es.cat_shards",
+			"Export the time shard copies spend initializing and the document count drift of replicas from /_cat/shards.").
+			Default("false").Envar("ES_CAT_SHARDS").Bool()
+		esExportShardStores = kingpin.Flag("es.shard_stores",
+			"Export the store exceptions and fetch duration of the shard stores of red indices.").
+			Default("false").Envar("ES_SHARD_STORES").Bool()
+		esExportSnapshotRestores = kingpin.Flag("es.snapshot_restores",
+			"Export the progress of the indices being restored from snapshots.").
+			Default("false").Envar("ES_SNAPSHOT_RESTORES").Bool()
+		esExportUpgradeCompatibility = kingpin.Flag("es.upgrade_compatibility",
+			"Export the number of deprecations blocking or affecting the upgrade to the next major version. Requires Elasticsearch 7.0 or later.").
+			Default("false").Envar("ES_UPGRADE_COMPATIBILITY").Bool()
+		esExportAutoscaling = kingpin.Flag("es.autoscaling",
+			"Export the required and current capacity of the autoscaling policies.").
+			Default("false").Envar("ES_AUTOSCALING").Bool()
+		esExportIndexZones = kingpin.Flag("es.index_zones",
+			"Export the number of zones hosting the shards of every index.").
+			Default("false").Envar("ES_INDEX_ZONES").Bool()
+		esIndexZonesAttribute = kingpin.Flag("es.index_zones.attribute",
+			"Node attribute holding the zone of a node, as used for shard allocation awareness.").
+			Default("zone").Envar("ES_INDEX_ZONES_ATTRIBUTE").String()
+		esExportClusterStats = kingpin.Flag("es.cluster_stats",
+			"Export the node, index, store and memory aggregates of the cluster stats.").
+			Default("false").Envar("ES_CLUSTER_STATS").Bool()
+		esExportSearchableSnapshots = kingpin.Flag("es.searchable_snapshots",
+			"Export the cache stats of the searchable snapshots, available starting with 7.10.").
+			Default("false").Envar("ES_SEARCHABLE_SNAPSHOTS").Bool()
+		esExportComponentTemplates = kingpin.Flag("es.component_templates",
+			"Export how many index templates reference each component template.").
+			Default("false").Envar("ES_COMPONENT_TEMPLATES").Bool()
+		esExportAliases = kingpin.Flag("es.aliases",
+			"Export info about index aliases of the cluster.").
+			Default("false").Envar("ES_ALIASES").Bool()
+		esExportIngestPipeline = kingpin.Flag("es.ingest_pipeline",
+			"Export stats for the ingest pipelines of the cluster.").
+			Default("false").Envar("ES_INGEST_PIPELINE").Bool()
+		esExportIndicesMappings = kingpin.Flag("es.indices_mappings",
+			"Export field counts of the index mappings of the cluster.").
+			Default("false").Envar("ES_INDICES_MAPPINGS").Bool()
+		esExportSLM = kingpin.Flag("es.slm",
+			"Export stats for the snapshot lifecycle management policies of the cluster.").
+			Default("false").Envar("ES_SLM").Bool()
+		esExportIndexHealth = kingpin.Flag("es.index_health",
+			"Export the health of every index from the lightweight cat indices API.").
+			Default("false").Envar("ES_INDEX_HEALTH").Bool()
+		esIndexHealthIndexFilter = kingpin.Flag("es.index_health.index_filter",
+			"Regular expression of the indices exported by es.index_health, empty for all indices.").
+			Default("").Envar("ES_INDEX_HEALTH_INDEX_FILTER").String()
+		esExportPendingTasks = kingpin.Flag("es.pending_tasks",
+			"Export the age distribution of the pending cluster tasks.").
+			Default("false").Envar("ES_PENDING_TASKS").Bool()
+		esExportHotThreads = kingpin.Flag("es.hot_threads",
+			"Export the number of hot threads of each node.").
+			Default("false").Envar("ES_HOT_THREADS").Bool()
+		esExportLicense = kingpin.Flag("es.license",
+			"Export the license type and expiry of the cluster.").
+			Default("false").Envar("ES_LICENSE").Bool()
+		esExportWatcher = kingpin.Flag("es.watcher",
+			"Export the execution and queued time of the watches of the cluster.").
+			Default("false").Envar("ES_WATCHER").Bool()
+		esPingTimeout = kingpin.Flag("es.ping.timeout",
+			"Timeout of the connectivity check of each Elasticsearch endpoint.").
+			Default("2s").Envar("ES_PING_TIMEOUT").Duration()
+		esClusterHealthMasterRetries = kingpin.Flag("es.cluster_health.master_retries",
+			"Number of times the cluster health is fetched again while no master node is elected.").
+			Default("3").Envar("ES_CLUSTER_HEALTH_MASTER_RETRIES").Int()
+		esClusterHealthMasterRetryBackoff = kingpin.Flag("es.cluster_health.master_retry_backoff",
+			"Time to wait before fetching the cluster health again while no master node is elected.").
+			Default("1s").Envar("ES_CLUSTER_HEALTH_MASTER_RETRY_BACKOFF").Duration()
+		esClusterHealthHeap = kingpin.Flag("es.cluster_health.heap",
+			"Export the maximum and average heap usage across all nodes of the cluster.").
+			Default("false").Envar("ES_CLUSTER_HEALTH_HEAP").Bool()
+		esClusterHealthScoreFormula = kingpin.Flag("es.cluster_health_score_formula",
+			"Formula for the cluster health score. Supports + - * /, parentheses, max(), min() and the cluster health variables.").
+			Default(collector.DefaultHealthScoreFormula).Envar("ES_CLUSTER_HEALTH_SCORE_FORMULA").String()
+		esVersionCompat = kingpin.Flag("es.version_compat",
+			"Compatibility mode, legacy reads the cluster health from /_cat/health for old clusters.").
+			Default("default").Envar("ES_VERSION_COMPAT").Enum("default", "legacy")
 		esClusterInfoInterval = kingpin.Flag("es.clusterinfo.interval",
 			"Cluster info update interval for the cluster label").
 			Default("5m").Envar("ES_CLUSTERINFO_INTERVAL").Duration()
@@ -66,9 +205,18 @@ func main() {
 		esClientCert = kingpin.Flag("es.client-cert",
 			"Path to PEM file that contains the corresponding cert for the private key to connect to Elasticsearch.").
 			Default("").Envar("ES_CLIENT_CERT").String()
+		esTLSServerName = kingpin.Flag("es.tls-server-name",
+			"Host name used for SNI and the verification of the server certificate, instead of the host of es.uri.").
+			Default("").Envar("ES_TLS_SERVER_NAME").String()
 		esInsecureSkipVerify = kingpin.Flag("es.ssl-skip-verify",
 			"Skip SSL verification when connecting to Elasticsearch.").
 			Default("false").Envar("ES_SSL_SKIP_VERIFY").Bool()
+		metricsLabelAllowlist = kingpin.Flag("metrics.label-allowlist",
+			"Comma separated list of label=regex pairs, metrics with a value of the label not matching the regex are dropped.").
+			Default("").Envar("METRICS_LABEL_ALLOWLIST").String()
+		esExtraHeaders = kingpin.Flag("es.extra-headers",
+			"HTTP header added to every request to Elasticsearch as key=value, repeat the flag to add more headers.").
+			Envar("ES_EXTRA_HEADERS").Strings()
 		logLevel = kingpin.Flag("log.level",
 			"Sets the loglevel. Valid levels are debug, info, warn, error").
 			Default("info").Envar("LOG_LEVEL").String()
@@ -99,15 +247,114 @@ func main() {
 		esURLs = append(esURLs, u)
 	}
 
+	healthScoreFormula, err := collector.ParseHealthScoreFormula(*esClusterHealthScoreFormula)
+	if err != nil {
+		_ = level.Error(logger).Log(
+			"msg", "failed to parse es.cluster_health_score_formula",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nodeAttributes, err := collector.ParseNodeAttributes(*esNodesAttributes)
+	if err != nil {
+		_ = level.Error(logger).Log(
+			"msg", "failed to parse es.nodes.attributes",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	repositoryCapacities, err := collector.ParseRepositoryCapacities(*esSnapshotsRepositoryCapacity)
+	if err != nil {
+		_ = level.Error(logger).Log(
+			"msg", "failed to parse es.snapshots.repository_capacity",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if *esSnapshotsRecentCount < 1 {
+		_ = level.Error(logger).Log(
+			"msg", "es.snapshots.recent_count must be at least 1",
+			"recent_count", *esSnapshotsRecentCount,
+		)
+		os.Exit(1)
+	}
+	if *esAllocationExplainMaxShards < 0 {
+		_ = level.Error(logger).Log(
+			"msg", "es.allocation_explain.max_shards must not be negative",
+			"max_shards", *esAllocationExplainMaxShards,
+		)
+		os.Exit(1)
+	}
+
+	var indexHealthIndexFilter *regexp.Regexp
+	if *esIndexHealthIndexFilter != "" {
+		indexHealthIndexFilter, err = regexp.Compile(*esIndexHealthIndexFilter)
+		if err != nil {
+			_ = level.Error(logger).Log(
+				"msg", "failed to parse es.index_health.index_filter",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+	}
+
+	labelAllowlist, err := parseLabelAllowlist(*metricsLabelAllowlist)
+	if err != nil {
+		_ = level.Error(logger).Log(
+			"msg", "failed to parse metrics.label-allowlist",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	// mustRegister registers the collectors of the cluster, dropping metrics not matching the label allowlist
+	mustRegister := func(c prometheus.Collector) {
+		prometheus.MustRegister(newLabelAllowlistCollector(labelAllowlist, c))
+	}
+
 	// returns nil if not provided and falls back to simple TCP.
-	tlsConfig := createTLSConfig(*esCA, *esClientCert, *esClientPrivateKey, *esInsecureSkipVerify)
+	tlsConfig := createTLSConfig(*esCA, *esClientCert, *esClientPrivateKey, *esTLSServerName, *esInsecureSkipVerify)
+
+	extraHeaders, dangerousHeaders, err := parseExtraHeaders(*esExtraHeaders)
+	if err != nil {
+		_ = level.Error(logger).Log(
+			"msg", "failed to parse es.extra-headers",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	for _, header := range dangerousHeaders {
+		_ = level.Warn(logger).Log(
+			"msg", "es.extra-headers overrides a header set by the HTTP client, requests may fail",
+			"header", header,
+		)
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		TLSClientConfig: tlsConfig,
+		Proxy:           http.ProxyFromEnvironment,
+	}
+	if len(extraHeaders) > 0 {
+		transport = newHeaderRoundTripper(extraHeaders, transport)
+	}
+
+	throttledRequests := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "elasticsearch_exporter_throttled_requests_total",
+		Help: "Number of requests of the exporter rejected by ElasticSearch with 429 Too Many Requests.",
+	})
+	prometheus.MustRegister(throttledRequests)
+	transport = newThrottledRoundTripper(throttledRequests, transport)
 
 	httpClient := &http.Client{
-		Timeout: *esTimeout,
-		Transport: &http.Transport{
-			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
-		},
+		Timeout:   *esTimeout,
+		Transport: transport,
+	}
+
+	// the connectivity check uses a shorter timeout than the stats scrapes
+	pingClient := &http.Client{
+		Timeout:   *esPingTimeout,
+		Transport: transport,
 	}
 
 	// version metric
@@ -115,34 +362,154 @@ func main() {
 	prometheus.MustRegister(versionMetric)
 
 	retrievers := make(map[*url.URL]*clusterinfo.Retriever)
+	var bufferedIndices []*collector.Indices
+	var bufferedSnapshots []*collector.Snapshots
 	for _, esURL := range esURLs {
 		// cluster info retriever
 		clusterInfoRetriever := clusterinfo.New(logger, httpClient, esURL, *esClusterInfoInterval)
 
 		retrievers[esURL] = clusterInfoRetriever
 
-		prometheus.MustRegister(collector.NewClusterHealth(logger, httpClient, esURL))
-		prometheus.MustRegister(collector.NewNodes(logger, httpClient, esURL, *esAllNodes, *esNode))
+		// shards fetched once per scrape for the indices settings, allocation explain and cat shards collectors
+		catShardsCache := collector.NewCatShardsCache(logger, httpClient, esURL)
+
+		mustRegister(collector.NewPing(logger, pingClient, esURL))
+		if *esVersionCompat == "legacy" {
+			mustRegister(collector.NewCatHealth(logger, httpClient, esURL))
+			mustRegister(collector.NewCatMaster(logger, httpClient, esURL))
+		} else {
+			mustRegister(collector.NewClusterHealth(logger, httpClient, esURL, healthScoreFormula, *esClusterHealthMasterRetries, *esClusterHealthMasterRetryBackoff, *esClusterHealthHeap))
+		}
+		mustRegister(collector.NewNodes(logger, httpClient, esURL, *esAllNodes, *esNode, *esNodesQuickStats, *esNodesFielddataFields, nodeAttributes))
 
-		if *esExportIndices || *esExportShards {
-			iC := collector.NewIndices(logger, httpClient, esURL, *esExportShards)
-			prometheus.MustRegister(iC)
+		if *esExportIndices || *esExportShards || *esIndicesAggregateOnly {
+			iC := collector.NewIndices(logger, httpClient, esURL, *esExportShards, *esIndicesAggregateOnly, *esIndicesVerboseSegments)
+			mustRegister(iC)
 			if registerErr := clusterInfoRetriever.RegisterConsumer(iC); registerErr != nil {
 				_ = level.Error(logger).Log("msg", "failed to register indices collector in cluster info")
 				os.Exit(1)
 			}
+			if *esIndicesRefreshInterval > 0 {
+				bufferedIndices = append(bufferedIndices, iC)
+			}
 		}
 
 		if *esExportSnapshots {
-			prometheus.MustRegister(collector.NewSnapshots(logger, httpClient, esURL))
+			sC := collector.NewSnapshots(logger, httpClient, esURL, *esSnapshotsRecentCount, *esSnapshotsHistoryDepth, *esSnapshotsVerifyInterval, repositoryCapacities, *esSnapshotsCacheTTL)
+			mustRegister(sC)
+			if *esSnapshotsRefreshInterval > 0 {
+				bufferedSnapshots = append(bufferedSnapshots, sC)
+			}
 		}
 
 		if *esExportClusterSettings {
-			prometheus.MustRegister(collector.NewClusterSettings(logger, httpClient, esURL))
+			mustRegister(collector.NewClusterSettings(logger, httpClient, esURL))
 		}
 
 		if *esExportIndicesSettings {
-			prometheus.MustRegister(collector.NewIndicesSettings(logger, httpClient, esURL))
+			mustRegister(collector.NewIndicesSettings(logger, httpClient, esURL, catShardsCache))
+		}
+
+		if *esExportML {
+			mustRegister(collector.NewML(logger, httpClient, esURL))
+		}
+
+		if *esExportRollupJobs {
+			mustRegister(collector.NewRollupJobs(logger, httpClient, esURL))
+		}
+
+		if *esExportDataStream {
+			mustRegister(collector.NewDataStream(logger, httpClient, esURL))
+		}
+
+		if *esExportCCR {
+			mustRegister(collector.NewCCR(logger, httpClient, esURL))
+		}
+
+		if *esExportCCS {
+			mustRegister(collector.NewCCS(logger, httpClient, esURL))
+		}
+
+		if *esExportILM {
+			mustRegister(collector.NewILM(logger, httpClient, esURL))
+		}
+
+		if *esExportAllocationExplain {
+			mustRegister(collector.NewAllocationExplain(logger, httpClient, esURL, catShardsCache, *esAllocationExplainMaxShards))
+		}
+
+		if *esExportCatShards {
+			mustRegister(collector.NewCatShards(logger, httpClient, esURL, catShardsCache))
+		}
+
+		if *esExportShardStores {
+			mustRegister(collector.NewShardStores(logger, httpClient, esURL))
+		}
+
+		if *esExportSnapshotRestores {
+			mustRegister(collector.NewSnapshotRestores(logger, httpClient, esURL))
+		}
+
+		if *esExportUpgradeCompatibility {
+			mustRegister(collector.NewUpgradeCompatibility(logger, httpClient, esURL))
+		}
+
+		if *esExportAutoscaling {
+			mustRegister(collector.NewAutoscaling(logger, httpClient, esURL))
+		}
+
+		if *esExportIndexZones {
+			mustRegister(collector.NewIndexZones(logger, httpClient, esURL, *esIndexZonesAttribute))
+		}
+		if *esExportClusterStats {
+			mustRegister(collector.NewClusterStats(logger, httpClient, esURL))
+		}
+		if *esExportSearchableSnapshots {
+			ssC := collector.NewSearchableSnapshots(logger, httpClient, esURL)
+			mustRegister(ssC)
+			if registerErr := clusterInfoRetriever.RegisterConsumer(ssC); registerErr != nil {
+				_ = level.Error(logger).Log("msg", "failed to register searchable snapshots collector in cluster info")
+				os.Exit(1)
+			}
+		}
+		if *esExportComponentTemplates {
+			mustRegister(collector.NewComponentTemplates(logger, httpClient, esURL))
+		}
+
+		if *esExportAliases {
+			mustRegister(collector.NewAliases(logger, httpClient, esURL))
+		}
+
+		if *esExportIngestPipeline {
+			mustRegister(collector.NewIngestPipeline(logger, httpClient, esURL))
+		}
+
+		if *esExportIndicesMappings {
+			mustRegister(collector.NewIndicesMappings(logger, httpClient, esURL))
+		}
+
+		if *esExportSLM {
+			mustRegister(collector.NewSLM(logger, httpClient, esURL))
+		}
+
+		if *esExportIndexHealth {
+			mustRegister(collector.NewIndexHealth(logger, httpClient, esURL, indexHealthIndexFilter))
+		}
+
+		if *esExportPendingTasks {
+			mustRegister(collector.NewPendingTasks(logger, httpClient, esURL))
+		}
+
+		if *esExportLicense {
+			mustRegister(collector.NewLicense(logger, httpClient, esURL))
+		}
+
+		if *esExportHotThreads {
+			mustRegister(collector.NewHotThreads(logger, httpClient, esURL))
+		}
+
+		if *esExportWatcher {
+			mustRegister(collector.NewWatcher(logger, httpClient, esURL))
 		}
 	}
 
@@ -152,6 +519,14 @@ func main() {
 	// create a context that is cancelled on SIGKILL
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// start the background refresh of the buffered collectors
+	for _, iC := range bufferedIndices {
+		iC.Run(ctx, *esIndicesRefreshInterval)
+	}
+	for _, sC := range bufferedSnapshots {
+		sC.Run(ctx, *esSnapshotsRefreshInterval)
+	}
+
 	for esURL, retriever := range retrievers {
 		// start the cluster info retriever
 		switch err := retriever.Run(ctx); err {
@@ -169,7 +544,7 @@ func main() {
 		}
 
 		// register cluster info retriever as prometheus collector
-		prometheus.MustRegister(retriever)
+		mustRegister(retriever)
 	}
 
 	mux := http.DefaultServeMux
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// throttledRoundTripper counts the requests of the exporter rejected by the cluster with 429 Too Many Requests
+type throttledRoundTripper struct {
+	throttled prometheus.Counter
+	next      http.RoundTripper
+}
+
+func newThrottledRoundTripper(throttled prometheus.Counter, next http.RoundTripper) http.RoundTripper {
+	return &throttledRoundTripper{
+		throttled: throttled,
+		next:      next,
+	}
+}
+
+func (rt *throttledRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := rt.next.RoundTrip(req)
+	if err == nil && res.StatusCode == http.StatusTooManyRequests {
+		rt.throttled.Inc()
+	}
+	return res, err
+}
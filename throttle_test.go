@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestThrottledRoundTripper(t *testing.T) {
+	statuses := []int{http.StatusOK, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[requests])
+		requests++
+	}))
+	defer ts.Close()
+
+	throttled := prometheus.NewCounter(prometheus.CounterOpts{Name: "throttled_requests_total"})
+	client := &http.Client{Transport: newThrottledRoundTripper(throttled, http.DefaultTransport)}
+	for range statuses {
+		res, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %s", ts.URL, err)
+		}
+		res.Body.Close()
+	}
+
+	m := &dto.Metric{}
+	if err := throttled.Write(m); err != nil {
+		t.Fatalf("Failed to write metric: %s", err)
+	}
+	if v := m.GetCounter().GetValue(); v != 2 {
+		t.Errorf("Wrong number of throttled requests: got %v, expected 2", v)
+	}
+}
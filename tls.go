@@ -7,8 +7,12 @@ import (
 	"log"
 )
 
-func createTLSConfig(pemFile, pemCertFile, pemPrivateKeyFile string, insecureSkipVerify bool) *tls.Config {
-	tlsConfig := tls.Config{}
+// createTLSConfig builds the TLS configuration of the ElasticSearch client. A non-empty serverName
+// overrides the host name sent with SNI and checked against the server certificate.
+func createTLSConfig(pemFile, pemCertFile, pemPrivateKeyFile, serverName string, insecureSkipVerify bool) *tls.Config {
+	tlsConfig := tls.Config{
+		ServerName: serverName,
+	}
 	if insecureSkipVerify {
 		// pem settings are irrelevant if we're skipping verification anyway
 		tlsConfig.InsecureSkipVerify = true
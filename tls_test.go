@@ -0,0 +1,43 @@
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateTLSConfigServerName(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"cluster_name":"elasticsearch"}`)
+	}))
+	defer ts.Close()
+
+	// the test certificate is issued to example.com and 127.0.0.1, not to localhost
+	u := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+
+	tcs := map[string]struct {
+		serverName string
+		ok         bool
+	}{
+		"default":  {"", false},
+		"override": {"example.com", true},
+		"mismatch": {"elastic.example.org", false},
+	}
+	for name, tc := range tcs {
+		tlsConfig := createTLSConfig("", "", "", tc.serverName, false)
+		tlsConfig.RootCAs = rootCAs
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		res, err := client.Get(u)
+		if err == nil {
+			res.Body.Close()
+		}
+		if ok := err == nil; ok != tc.ok {
+			t.Errorf("[%s] Wrong TLS verification result, got error %v", name, err)
+		}
+	}
+}